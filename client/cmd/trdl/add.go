@@ -2,15 +2,26 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/spf13/cobra"
 
 	trdlClient "github.com/werf/trdl/client/pkg/client"
 	"github.com/werf/trdl/client/pkg/trdl"
+	"github.com/werf/trdl/client/pkg/tuf"
+	"github.com/werf/trdl/client/pkg/util"
 )
 
 func addCmd() *cobra.Command {
+	var pgpPublicKeyFile string
+	var postUpdateHooks []string
+	var proxy, caCertFile, clientCertFile, clientKeyFile string
+	var headers []string
+	var mirrors []string
+	var raceMirrors bool
+
 	cmd := &cobra.Command{
 		Use:                   "add REPO URL ROOT_VERSION ROOT_SHA512",
 		Short:                 "Add a software repository",
@@ -37,12 +48,36 @@ func addCmd() *cobra.Command {
 				return fmt.Errorf("unable to parse required argument \"ROOT_VERSION\": %w", err)
 			}
 
+			var pgpPublicKey string
+			if pgpPublicKeyFile != "" {
+				data, err := os.ReadFile(pgpPublicKeyFile)
+				if err != nil {
+					return fmt.Errorf("unable to read PGP public key file %q: %w", pgpPublicKeyFile, err)
+				}
+
+				pgpPublicKey = string(data)
+			}
+
+			remoteOptions, err := processRemoteOptions(proxy, caCertFile, clientCertFile, clientKeyFile, headers)
+			if err != nil {
+				PrintHelp(cmd)
+				return err
+			}
+
+			if raceMirrors && len(mirrors) == 0 {
+				PrintHelp(cmd)
+				return fmt.Errorf("--race-mirrors requires at least one --mirror")
+			}
+
+			remoteOptions.Mirrors = mirrors
+			remoteOptions.RaceMirrors = raceMirrors
+
 			c, err := trdlClient.NewClient(homeDir)
 			if err != nil {
 				return fmt.Errorf("unable to initialize trdl client: %w", err)
 			}
 
-			if err := c.AddRepo(repoName, repoUrl, rootVersion, rootSha512); err != nil {
+			if err := c.AddRepo(repoName, repoUrl, rootVersion, rootSha512, trdlClient.AddRepoOptions{PGPPublicKey: pgpPublicKey, PostUpdateHooks: postUpdateHooks, RemoteOptions: remoteOptions}); err != nil {
 				return err
 			}
 
@@ -50,9 +85,63 @@ func addCmd() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().StringVarP(&pgpPublicKeyFile, "pgp-public-key-file", "", "", "Verify release files signatures with the armored PGP public key from the file")
+	cmd.Flags().StringArrayVarP(&postUpdateHooks, "post-update", "", nil, `Run the shell command after the channel is updated to the new release (can be specified multiple times).
+The command gets $TRDL_REPO, $TRDL_GROUP, $TRDL_CHANNEL, $TRDL_OLD_RELEASE, $TRDL_NEW_RELEASE and $TRDL_RELEASE_DIR`)
+	cmd.Flags().StringVarP(&proxy, "proxy", "", "", "Access the repository through the HTTP proxy (default $HTTPS_PROXY, $HTTP_PROXY and $NO_PROXY)")
+	cmd.Flags().StringVarP(&caCertFile, "ca-cert-file", "", "", "Trust the PEM CA certificates from the file in addition to the system ones")
+	cmd.Flags().StringVarP(&clientCertFile, "client-cert-file", "", "", "Authenticate to the repository with the PEM client TLS certificate from the file, the certificate is not presented to the mirrors (requires --client-key-file)")
+	cmd.Flags().StringVarP(&clientKeyFile, "client-key-file", "", "", "Use the PEM private key of the client TLS certificate from the file")
+	cmd.Flags().StringArrayVarP(&headers, "header", "", nil, `Add the header in format "NAME: VALUE" to all requests to the repository URL, the headers are not sent to the mirrors (can be specified multiple times).
+The environment variables in the value are expanded on each run, e.g. "Authorization: Bearer $CDN_TOKEN", thus the secrets are not stored in the configuration`)
+	cmd.Flags().StringArrayVarP(&mirrors, "mirror", "", nil, `Fetch from the mirror URL if the fetch from the repository URL fails (can be specified multiple times, the mirrors are tried in order).
+The mirror each file is fetched from is logged in the repository logs directory`)
+	cmd.Flags().BoolVarP(&raceMirrors, "race-mirrors", "", false, "Fetch from the repository URL and all mirrors at once using the fastest response")
+
 	return cmd
 }
 
+// processRemoteOptions makes the file paths absolute since they are used on each repository access.
+func processRemoteOptions(proxy, caCertFile, clientCertFile, clientKeyFile string, headers []string) (tuf.RemoteOptions, error) {
+	opts := tuf.RemoteOptions{Proxy: proxy}
+
+	if (clientCertFile == "") != (clientKeyFile == "") {
+		return opts, fmt.Errorf("--client-cert-file and --client-key-file must be specified together")
+	}
+
+	for _, path := range []*string{&caCertFile, &clientCertFile, &clientKeyFile} {
+		if *path == "" {
+			continue
+		}
+
+		absPath, err := util.ExpandPath(*path)
+		if err != nil {
+			return opts, fmt.Errorf("unable to expand path %q: %w", *path, err)
+		}
+
+		*path = absPath
+	}
+
+	opts.CACertFile = caCertFile
+	opts.ClientCertFile = clientCertFile
+	opts.ClientKeyFile = clientKeyFile
+
+	for _, header := range headers {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return opts, fmt.Errorf("expected header in format \"NAME: VALUE\", got %q", header)
+		}
+
+		if opts.Headers == nil {
+			opts.Headers = map[string]string{}
+		}
+
+		opts.Headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return opts, nil
+}
+
 func parseRootVersionArgument(arg string) (int64, error) {
 	if !govalidator.IsNumeric(arg) {
 		return 0, fmt.Errorf("value (%q) must be an integer", arg)
@@ -10,8 +10,11 @@ import (
 )
 
 func binPathCmd() *cobra.Command {
+	var version string
+	var targetOS, targetArch string
+
 	cmd := &cobra.Command{
-		Use:                   "bin-path REPO GROUP [CHANNEL]",
+		Use:                   "bin-path REPO GROUP [CHANNEL|--version VERSION]",
 		Short:                 "Get the directory with software binaries",
 		DisableFlagsInUseLine: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -37,12 +40,23 @@ func binPathCmd() *cobra.Command {
 				}
 			}
 
-			c, err := trdlClient.NewClient(homeDir)
+			release, err := ProcessVersion(version, optionalChannel)
+			if err != nil {
+				PrintHelp(cmd)
+				return err
+			}
+
+			c, err := trdlClient.NewClientWithOptions(homeDir, trdlClient.ClientOptions{OS: targetOS, Arch: targetArch})
 			if err != nil {
 				return fmt.Errorf("unable to initialize trdl client: %w", err)
 			}
 
-			dir, err := c.GetRepoChannelReleaseBinDir(repoName, group, optionalChannel)
+			var dir string
+			if release != "" {
+				dir, err = c.GetRepoReleaseBinDir(repoName, group, release)
+			} else {
+				dir, err = c.GetRepoChannelReleaseBinDir(repoName, group, optionalChannel)
+			}
 			if err != nil {
 				return err
 			}
@@ -53,5 +67,8 @@ func binPathCmd() *cobra.Command {
 		},
 	}
 
+	SetupVersion(cmd, &version)
+	SetupPlatform(cmd, &targetOS, &targetArch)
+
 	return cmd
 }
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	trdlClient "github.com/werf/trdl/client/pkg/client"
+)
+
+func exportCmd() *cobra.Command {
+	var outputFile string
+	var platforms []string
+
+	cmd := &cobra.Command{
+		Use:   "export REPO GROUP [CHANNEL] -o BUNDLE",
+		Short: "Export the channel release into the offline bundle",
+		Long: `Export the channel release into the offline bundle.
+
+The bundle contains the verified TUF repository metadata and the release files for the selected platforms. The bundle is installed on the host without access to the TUF repository with "trdl import" command`,
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoName, group, optionalChannel, err := processRepoGroupChannelArgs(cmd, args)
+			if err != nil {
+				PrintHelp(cmd)
+				return err
+			}
+
+			if outputFile == "" {
+				PrintHelp(cmd)
+				return fmt.Errorf("required flag --output is not specified")
+			}
+
+			c, err := trdlClient.NewClient(homeDir)
+			if err != nil {
+				return fmt.Errorf("unable to initialize trdl client: %w", err)
+			}
+
+			// the bundle file appears only after the successful export
+			tmpOutputFile := outputFile + ".tmp"
+			f, err := os.Create(tmpOutputFile)
+			if err != nil {
+				return fmt.Errorf("unable to create file %q: %w", tmpOutputFile, err)
+			}
+
+			if err := c.ExportRepoChannel(repoName, group, optionalChannel, platforms, f); err != nil {
+				_ = f.Close()
+				_ = os.Remove(tmpOutputFile)
+				return err
+			}
+
+			if err := f.Close(); err != nil {
+				_ = os.Remove(tmpOutputFile)
+				return fmt.Errorf("unable to close file %q: %w", tmpOutputFile, err)
+			}
+
+			if err := os.Rename(tmpOutputFile, outputFile); err != nil {
+				return fmt.Errorf("unable to rename %q to %q: %w", tmpOutputFile, outputFile, err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Write the bundle to the file (required)")
+	cmd.Flags().StringArrayVarP(&platforms, "platform", "", nil, "Export the release files for the platform in format <os>/<arch>[/<variant>], e.g. linux/amd64 or linux/arm/v7 (can be specified multiple times, default the current platform)")
+
+	return cmd
+}
+
+func importCmd() *cobra.Command {
+	var targetOS, targetArch string
+
+	cmd := &cobra.Command{
+		Use:   "import BUNDLE [REPO]",
+		Short: "Install the channel release from the offline bundle",
+		Long: `Install the channel release from the offline bundle created with "trdl export" command.
+
+The bundle is verified against the locally trusted TUF repository metadata before the installation, thus the repository must be added beforehand. The release is installed into the repository the bundle is exported from unless the REPO is specified`,
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.RangeArgs(1, 2)(cmd, args); err != nil {
+				PrintHelp(cmd)
+				return err
+			}
+
+			bundlePath := args[0]
+
+			var optionalRepoName string
+			if len(args) == 2 {
+				optionalRepoName = args[1]
+			}
+
+			f, err := os.Open(bundlePath)
+			if err != nil {
+				return fmt.Errorf("unable to open file %q: %w", bundlePath, err)
+			}
+			defer func() { _ = f.Close() }()
+
+			c, err := trdlClient.NewClientWithOptions(homeDir, trdlClient.ClientOptions{OS: targetOS, Arch: targetArch})
+			if err != nil {
+				return fmt.Errorf("unable to initialize trdl client: %w", err)
+			}
+
+			manifest, err := c.ImportRepoBundle(f, optionalRepoName)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Release %q is installed (repo: %q, group: %q, channel: %q)\n", manifest.Release, manifest.Repo, manifest.Group, manifest.Channel)
+
+			return nil
+		},
+	}
+
+	SetupPlatform(cmd, &targetOS, &targetArch)
+
+	return cmd
+}
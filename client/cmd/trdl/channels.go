@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	trdlClient "github.com/werf/trdl/client/pkg/client"
+)
+
+func channelsCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:                   "channels REPO GROUP",
+		Short:                 "List channels of the group published in the repository",
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+				PrintHelp(cmd)
+				return err
+			}
+
+			if err := ValidateOutputFormat(format); err != nil {
+				PrintHelp(cmd)
+				return err
+			}
+
+			c, err := trdlClient.NewClient(homeDir)
+			if err != nil {
+				return fmt.Errorf("unable to initialize trdl client: %w", err)
+			}
+
+			remoteChannels, err := c.GetRepoRemoteChannels(args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			return printRemoteChannels(remoteChannels, format, false)
+		},
+	}
+
+	SetupOutputFormat(cmd, &format)
+
+	return cmd
+}
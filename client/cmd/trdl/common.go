@@ -5,22 +5,80 @@ import (
 	"os"
 	"strings"
 
-	"github.com/asaskevich/govalidator"
 	"github.com/spf13/cobra"
 
+	"github.com/werf/trdl/client/pkg/project"
 	"github.com/werf/trdl/client/pkg/trdl"
 )
 
+// ValidateChannel checks the channel name only, the channels allowed by the repository are checked on update.
 func ValidateChannel(channel string) error {
-	if !govalidator.IsIn(channel, trdl.Channels...) {
-		return fmt.Errorf(
-			"unable to parse argument \"CHANNEL\": unsupported channel %q specified, use one of the following: \"%s\"",
-			channel, strings.Join(trdl.Channels, `", "`))
+	if err := trdl.ValidateChannelName(channel); err != nil {
+		return fmt.Errorf("unable to parse argument \"CHANNEL\": %w", err)
 	}
 
 	return nil
 }
 
+const (
+	outputFormatTable = "table"
+	outputFormatJSON  = "json"
+)
+
+func SetupOutputFormat(cmd *cobra.Command, format *string) {
+	cmd.Flags().StringVarP(format, "output", "o", outputFormatTable, `Output format: "table" or "json"`)
+}
+
+func ValidateOutputFormat(format string) error {
+	switch format {
+	case outputFormatTable, outputFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q specified, use one of the following: %q, %q", format, outputFormatTable, outputFormatJSON)
+	}
+}
+
+func SetupVersion(cmd *cobra.Command, version *string) {
+	cmd.Flags().StringVar(version, "version", "", "Use the exact release version instead of the channel (e.g. 1.2.23)")
+}
+
+// ProcessVersion returns the release name of the specified version, the version cannot be used along with the channel.
+func ProcessVersion(version, optionalChannel string) (string, error) {
+	if version == "" {
+		return "", nil
+	}
+
+	if optionalChannel != "" {
+		return "", fmt.Errorf("unable to use channel %q along with the --version option", optionalChannel)
+	}
+
+	release := strings.TrimPrefix(version, "v")
+	if release == "" {
+		return "", fmt.Errorf("invalid version %q specified", version)
+	}
+
+	return release, nil
+}
+
+// GetProjectEntry returns the entry of the project file found in the current directory or its parents.
+func GetProjectEntry(optionalRepoName string) (project.Entry, error) {
+	projectFile, err := project.FindInWorkingDir()
+	if err != nil {
+		return project.Entry{}, err
+	}
+
+	if projectFile == nil {
+		return project.Entry{}, fmt.Errorf("REPO and GROUP must be specified: neither %s nor %s found in the current directory or its parents", project.YamlFileName, project.VersionFileName)
+	}
+
+	return projectFile.GetEntry(optionalRepoName)
+}
+
+func SetupPlatform(cmd *cobra.Command, targetOS, targetArch *string) {
+	cmd.Flags().StringVar(targetOS, "os", os.Getenv("TRDL_OS"), "Select the releases for the OS instead of the current one, e.g. linux, darwin or windows (default $TRDL_OS or the current OS)")
+	cmd.Flags().StringVar(targetArch, "arch", os.Getenv("TRDL_ARCH"), "Select the releases for the architecture instead of the current one, e.g. amd64, arm64 or arm/v7 (default $TRDL_ARCH or the current architecture)")
+}
+
 func SetupNoSelfUpdate(cmd *cobra.Command, noSelfUpdate *bool) {
 	cmd.Flags().BoolVar(noSelfUpdate, "no-self-update", GetBoolEnvironmentDefaultFalse("TRDL_NO_SELF_UPDATE"), "Do not perform self-update (default $TRDL_NO_SELF_UPDATE or false)")
 }
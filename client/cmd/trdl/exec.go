@@ -13,30 +13,43 @@ type execCmdData struct {
 	repoName           string
 	group              string
 	optionalChannel    string
+	release            string
 	optionalBinaryName string
 	optionalBinaryArgs []string
 }
 
 func execCmd() *cobra.Command {
+	var version string
+
 	cmd := &cobra.Command{
-		Use:                   "exec REPO GROUP [CHANNEL] [BINARY_NAME] [--] [ARGS]",
-		Short:                 "Exec a software binary",
+		Use:   "exec REPO GROUP [CHANNEL|--version VERSION] [BINARY_NAME] [--] [ARGS]",
+		Short: "Exec a software binary",
+		Long: `Exec a software binary.
+
+REPO and GROUP can be omitted if the project file .trdl.yaml or .trdl-version is found in the current directory or its parents: the repository is selected by the optional REPO argument, the group and the channel or the exact version are taken from the file`,
+		Example: `  # Exec the binary of the channel release
+  $ trdl exec repo_name 1.2 ea -- --help
+
+  # Exec the binary of the release specified in the project file
+  $ trdl exec -- --help`,
 		DisableFlagsInUseLine: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := cobra.MinimumNArgs(2)(cmd, args); err != nil {
-				PrintHelp(cmd)
-				return err
+			c, err := trdlClient.NewClient(homeDir)
+			if err != nil {
+				return fmt.Errorf("unable to initialize trdl client: %w", err)
 			}
 
-			cmdData, err := processExecArgs(cmd, args)
+			cmdData, err := processExecArgs(cmd, args, version, c)
 			if err != nil {
 				PrintHelp(cmd)
 				return err
 			}
 
-			c, err := trdlClient.NewClient(homeDir)
-			if err != nil {
-				return fmt.Errorf("unable to initialize trdl client: %w", err)
+			if cmdData.release != "" {
+				return c.ExecRepoReleaseBin(
+					cmdData.repoName, cmdData.group, cmdData.release,
+					cmdData.optionalBinaryName, cmdData.optionalBinaryArgs,
+				)
 			}
 
 			if err := c.ExecRepoChannelReleaseBin(
@@ -50,34 +63,63 @@ func execCmd() *cobra.Command {
 		},
 	}
 
+	SetupVersion(cmd, &version)
+
 	return cmd
 }
 
-func processExecArgs(cmd *cobra.Command, args []string) (*execCmdData, error) {
+func processExecArgs(cmd *cobra.Command, args []string, version string, c trdlClient.Interface) (*execCmdData, error) {
 	data := &execCmdData{}
 
-	data.repoName = args[0]
-	data.group = args[1]
+	positionalArgs := args
+	if doubleDashInd := cmd.ArgsLenAtDash(); doubleDashInd != -1 {
+		data.optionalBinaryArgs = args[doubleDashInd:]
+		positionalArgs = args[:doubleDashInd]
+	}
+
+	if len(positionalArgs) < 2 {
+		return processExecProjectArgs(data, positionalArgs, version)
+	}
+
+	data.repoName = positionalArgs[0]
+	data.group = positionalArgs[1]
 
 	if data.repoName == trdl.SelfUpdateDefaultRepo {
 		return nil, fmt.Errorf("reserved repository name %q cannot be used", trdl.SelfUpdateDefaultRepo)
 	}
 
-	doubleDashInd := cmd.ArgsLenAtDash()
-	doubleDashExist := cmd.ArgsLenAtDash() != -1
+	restArgs := positionalArgs[2:]
 
-	restArgs := args[2:]
-	if doubleDashExist {
-		data.optionalBinaryArgs = args[doubleDashInd:]
-		restArgs = args[2:doubleDashInd]
+	if version != "" {
+		release, err := ProcessVersion(version, "")
+		if err != nil {
+			return nil, err
+		}
+		data.release = release
+
+		switch len(restArgs) {
+		case 0:
+			return data, nil
+		case 1:
+			data.optionalBinaryName = restArgs[0]
+			return data, nil
+		default:
+			return nil, fmt.Errorf("unexpected positional args format: the channel cannot be used along with the --version option")
+		}
 	}
 
 	switch len(restArgs) {
 	case 0:
 		return data, nil
 	case 1:
-		for _, c := range trdl.Channels {
-			if c == restArgs[0] {
+		// the argument is the channel if the repository declares such channel for the group
+		channelsConfig, err := c.GetRepoChannelsConfig(data.repoName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, channel := range channelsConfig.GetGroupAllowedChannels(data.group) {
+			if channel == restArgs[0] {
 				data.optionalChannel = restArgs[0]
 				return data, nil
 			}
@@ -98,3 +140,34 @@ func processExecArgs(cmd *cobra.Command, args []string) (*execCmdData, error) {
 		return nil, fmt.Errorf("unexpected positional args format")
 	}
 }
+
+// processExecProjectArgs resolves the repository group and the channel or the release by the project file.
+func processExecProjectArgs(data *execCmdData, positionalArgs []string, version string) (*execCmdData, error) {
+	var optionalRepoName string
+	if len(positionalArgs) == 1 {
+		optionalRepoName = positionalArgs[0]
+	}
+
+	entry, err := GetProjectEntry(optionalRepoName)
+	if err != nil {
+		return nil, err
+	}
+
+	data.repoName = entry.Repo
+	data.group = entry.Group
+
+	if version != "" {
+		release, err := ProcessVersion(version, "")
+		if err != nil {
+			return nil, err
+		}
+		data.release = release
+
+		return data, nil
+	}
+
+	data.optionalChannel = entry.Channel
+	data.release = entry.Release()
+
+	return data, nil
+}
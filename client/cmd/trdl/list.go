@@ -8,7 +8,6 @@ import (
 	"github.com/spf13/cobra"
 
 	trdlClient "github.com/werf/trdl/client/pkg/client"
-	"github.com/werf/trdl/client/pkg/trdl"
 )
 
 func listCmd() *cobra.Command {
@@ -37,7 +36,12 @@ func listCmd() *cobra.Command {
 			for _, repoName := range repoNameList {
 				defaultChannel := repoConfigurationByName[repoName].DefaultChannel
 				if defaultChannel == "" {
-					defaultChannel = trdl.DefaultChannel
+					channelsConfig, err := c.GetRepoChannelsConfig(repoName)
+					if err != nil {
+						return fmt.Errorf("unable to get repository %q channels config: %w", repoName, err)
+					}
+
+					defaultChannel = channelsConfig.DefaultChannel
 				}
 
 				tbl.AddRow(repoName, repoConfigurationByName[repoName].Url, defaultChannel)
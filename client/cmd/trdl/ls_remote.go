@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	trdlClient "github.com/werf/trdl/client/pkg/client"
+	"github.com/werf/trdl/client/pkg/repo"
+	"github.com/werf/trdl/client/pkg/trdl"
+)
+
+func lsRemoteCmd() *cobra.Command {
+	var format string
+	var releases, history bool
+
+	cmd := &cobra.Command{
+		Use:                   "ls-remote REPO",
+		Short:                 "List groups, channels and releases published in the repository",
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				PrintHelp(cmd)
+				return err
+			}
+
+			if err := ValidateOutputFormat(format); err != nil {
+				PrintHelp(cmd)
+				return err
+			}
+
+			if releases && history {
+				PrintHelp(cmd)
+				return fmt.Errorf("the --releases option cannot be used along with the --history option")
+			}
+
+			c, err := trdlClient.NewClient(homeDir)
+			if err != nil {
+				return fmt.Errorf("unable to initialize trdl client: %w", err)
+			}
+
+			switch {
+			case releases:
+				index, err := c.GetRepoRemoteReleasesIndex(args[0])
+				if err != nil {
+					return err
+				}
+
+				return printRemoteReleasesIndex(index, format)
+			case history:
+				channelsHistory, err := c.GetRepoRemoteChannelsHistory(args[0])
+				if err != nil {
+					return err
+				}
+
+				return printRemoteChannelsHistory(channelsHistory, format)
+			}
+
+			remoteChannels, err := c.GetRepoRemoteChannels(args[0], "")
+			if err != nil {
+				return err
+			}
+
+			return printRemoteChannels(remoteChannels, format, true)
+		},
+	}
+
+	SetupOutputFormat(cmd, &format)
+	cmd.Flags().BoolVar(&releases, "releases", false, "List the releases index of the repository instead of the channels")
+	cmd.Flags().BoolVar(&history, "history", false, "List the history of the channels of the repository instead of the current channels")
+
+	return cmd
+}
+
+func printRemoteChannels(remoteChannels []*repo.RemoteChannel, format string, withGroup bool) error {
+	if format == outputFormatJSON {
+		if remoteChannels == nil {
+			remoteChannels = []*repo.RemoteChannel{}
+		}
+
+		return printJSON(remoteChannels)
+	}
+
+	var tbl table.Table
+	if withGroup {
+		tbl = table.New("Group", "Channel", "Release", "Files")
+	} else {
+		tbl = table.New("Channel", "Release", "Files")
+	}
+
+	for _, remoteChannel := range remoteChannels {
+		files := strings.Join(remoteChannel.Files, ", ")
+		if len(remoteChannel.Files) == 0 {
+			files = "-"
+		}
+
+		if withGroup {
+			tbl.AddRow(remoteChannel.Group, remoteChannel.Channel, remoteChannel.Release, files)
+		} else {
+			tbl.AddRow(remoteChannel.Channel, remoteChannel.Release, files)
+		}
+	}
+	tbl.Print()
+
+	return nil
+}
+
+func printRemoteReleasesIndex(index trdl.ReleasesIndex, format string) error {
+	if format == outputFormatJSON {
+		return printJSON(index)
+	}
+
+	releaseNames := make([]string, 0, len(index.Releases))
+	for releaseName := range index.Releases {
+		releaseNames = append(releaseNames, releaseName)
+	}
+	sort.Strings(releaseNames)
+
+	tbl := table.New("Release", "Platforms", "Published At", "Git Tag", "Git Commit")
+	for _, releaseName := range releaseNames {
+		entry := index.Releases[releaseName]
+
+		publishedAt := "-"
+		if entry.PublishedAt != nil {
+			publishedAt = entry.PublishedAt.Format(time.RFC3339)
+		}
+
+		tbl.AddRow(releaseName, strings.Join(entry.Platforms, ", "), publishedAt, valueOrDash(entry.GitTag), valueOrDash(entry.GitCommit))
+	}
+	tbl.Print()
+
+	return nil
+}
+
+func printRemoteChannelsHistory(history trdl.ChannelsHistory, format string) error {
+	if format == outputFormatJSON {
+		return printJSON(history)
+	}
+
+	groups := make([]string, 0, len(history.Groups))
+	for group := range history.Groups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	tbl := table.New("Group", "Channel", "Release", "Published At", "Git Commit", "Rollback")
+	for _, group := range groups {
+		channels := make([]string, 0, len(history.Groups[group]))
+		for channel := range history.Groups[group] {
+			channels = append(channels, channel)
+		}
+		sort.Strings(channels)
+
+		for _, channel := range channels {
+			for _, entry := range history.Groups[group][channel] {
+				tbl.AddRow(group, channel, entry.Version, entry.PublishedAt.Format(time.RFC3339), valueOrDash(entry.GitCommit), entry.Rollback)
+			}
+		}
+	}
+	tbl.Print()
+
+	return nil
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
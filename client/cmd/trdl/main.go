@@ -40,19 +40,27 @@ func rootCmd() *cobra.Command {
 				removeCmd(),
 				listCmd(),
 				setDefaultChannelCmd(),
+				lsRemoteCmd(),
+				channelsCmd(),
 			},
 		},
 		{
 			Message: "Main commands",
 			Commands: []*cobra.Command{
 				useCmd(),
+				shimsCmd(),
 			},
 		},
 		{
 			Message: "Advanced commands",
 			Commands: []*cobra.Command{
 				updateCmd(),
+				rollbackCmd(),
+				unholdCmd(),
+				exportCmd(),
+				importCmd(),
 				execCmd(),
+				shimExecCmd(),
 				dirPathCmd(),
 				binPathCmd(),
 				docsCmd(groups),
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	trdlClient "github.com/werf/trdl/client/pkg/client"
+	"github.com/werf/trdl/client/pkg/trdl"
+)
+
+func rollbackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback REPO GROUP [CHANNEL]",
+		Short: "Roll the channel back to the previously used release",
+		Long: `Roll the channel back to the previously used release.
+
+The channel is held at the release: the updates do not switch the channel until the hold is released with "trdl unhold" command`,
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoName, group, optionalChannel, err := processRepoGroupChannelArgs(cmd, args)
+			if err != nil {
+				PrintHelp(cmd)
+				return err
+			}
+
+			c, err := trdlClient.NewClient(homeDir)
+			if err != nil {
+				return fmt.Errorf("unable to initialize trdl client: %w", err)
+			}
+
+			release, err := c.RollbackRepoChannel(repoName, group, optionalChannel)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Channel is rolled back and held at release %q\n", release)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func unholdCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "unhold REPO GROUP [CHANNEL]",
+		Short:                 "Release the channel hold set by the rollback",
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoName, group, optionalChannel, err := processRepoGroupChannelArgs(cmd, args)
+			if err != nil {
+				PrintHelp(cmd)
+				return err
+			}
+
+			c, err := trdlClient.NewClient(homeDir)
+			if err != nil {
+				return fmt.Errorf("unable to initialize trdl client: %w", err)
+			}
+
+			return c.ReleaseRepoChannelHold(repoName, group, optionalChannel)
+		},
+	}
+
+	return cmd
+}
+
+func processRepoGroupChannelArgs(cmd *cobra.Command, args []string) (repoName, group, optionalChannel string, err error) {
+	if err := cobra.RangeArgs(2, 3)(cmd, args); err != nil {
+		return "", "", "", err
+	}
+
+	repoName = args[0]
+	group = args[1]
+
+	if repoName == trdl.SelfUpdateDefaultRepo {
+		return "", "", "", fmt.Errorf("reserved repository name %q cannot be used", trdl.SelfUpdateDefaultRepo)
+	}
+
+	if len(args) == 3 {
+		optionalChannel = args[2]
+		if err := ValidateChannel(optionalChannel); err != nil {
+			return "", "", "", err
+		}
+	}
+
+	return repoName, group, optionalChannel, nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	trdlClient "github.com/werf/trdl/client/pkg/client"
+	"github.com/werf/trdl/client/pkg/project"
+	"github.com/werf/trdl/client/pkg/trdl"
+)
+
+// shimTarget is the channel or the exact release of the repository group resolved by the shim.
+type shimTarget struct {
+	group           string
+	optionalChannel string
+	release         string
+}
+
+func shimExecCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "shim-exec REPO BINARY_NAME [--] [ARGS]",
+		Short:                 "Exec a software binary by the shim",
+		Hidden:                true,
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			positionalArgs := args
+			var binaryArgs []string
+			if doubleDashInd := cmd.ArgsLenAtDash(); doubleDashInd != -1 {
+				positionalArgs = args[:doubleDashInd]
+				binaryArgs = args[doubleDashInd:]
+			}
+
+			if err := cobra.ExactArgs(2)(cmd, positionalArgs); err != nil {
+				PrintHelp(cmd)
+				return err
+			}
+
+			repoName, binName := positionalArgs[0], positionalArgs[1]
+			if repoName == trdl.SelfUpdateDefaultRepo {
+				return fmt.Errorf("reserved repository name %q cannot be used", trdl.SelfUpdateDefaultRepo)
+			}
+
+			c, err := trdlClient.NewClient(homeDir)
+			if err != nil {
+				return fmt.Errorf("unable to initialize trdl client: %w", err)
+			}
+
+			target, err := resolveShimTarget(repoName)
+			if err != nil {
+				return err
+			}
+
+			if err := updateShimTarget(c, repoName, target); err != nil {
+				return err
+			}
+
+			if target.release != "" {
+				return c.ExecRepoReleaseBin(repoName, target.group, target.release, binName, binaryArgs)
+			}
+
+			return c.ExecRepoChannelReleaseBin(repoName, target.group, target.optionalChannel, binName, binaryArgs)
+		},
+	}
+
+	return cmd
+}
+
+// resolveShimTarget takes the target from the environment of the "trdl use" script or from the project file.
+func resolveShimTarget(repoName string) (shimTarget, error) {
+	envNamePrefix := fmt.Sprintf("TRDL_USE_%s_", strings.ToUpper(repoName))
+
+	if envName := envNamePrefix + "GROUP_VERSION"; os.Getenv(envName) != "" {
+		fields := strings.Fields(os.Getenv(envName))
+		if len(fields) != 2 {
+			return shimTarget{}, fmt.Errorf("unable to parse $%s: expected \"GROUP VERSION\", got %q", envName, os.Getenv(envName))
+		}
+
+		release, err := ProcessVersion(fields[1], "")
+		if err != nil {
+			return shimTarget{}, err
+		}
+
+		return shimTarget{group: fields[0], release: release}, nil
+	}
+
+	if envName := envNamePrefix + "GROUP_CHANNEL"; os.Getenv(envName) != "" {
+		fields := strings.Fields(os.Getenv(envName))
+		switch len(fields) {
+		case 1:
+			return shimTarget{group: fields[0]}, nil
+		case 2:
+			if err := ValidateChannel(fields[1]); err != nil {
+				return shimTarget{}, err
+			}
+
+			return shimTarget{group: fields[0], optionalChannel: fields[1]}, nil
+		default:
+			return shimTarget{}, fmt.Errorf("unable to parse $%s: expected \"GROUP [CHANNEL]\", got %q", envName, os.Getenv(envName))
+		}
+	}
+
+	projectFile, err := project.FindInWorkingDir()
+	if err != nil {
+		return shimTarget{}, err
+	}
+
+	if projectFile != nil {
+		if entry, ok := projectFile.LookupEntry(repoName); ok {
+			return shimTarget{group: entry.Group, optionalChannel: entry.Channel, release: entry.Release()}, nil
+		}
+	}
+
+	return shimTarget{}, fmt.Errorf(
+		"unable to resolve group of repository %q: set $%sGROUP_CHANNEL or $%sGROUP_VERSION, or specify the repository in %s or %s",
+		repoName, envNamePrefix, envNamePrefix, project.YamlFileName, project.VersionFileName,
+	)
+}
+
+// updateShimTarget updates the missing release in the foreground, otherwise the update is started in the background.
+func updateShimTarget(c trdlClient.Interface, repoName string, target shimTarget) error {
+	updateArgs := []string{"--home-dir", homeDir, "update", repoName, target.group}
+	name := target.optionalChannel
+
+	var err error
+	if target.release != "" {
+		updateArgs = append(updateArgs, "--version", target.release)
+		name = "v" + target.release
+		_, err = c.GetRepoReleaseBinDir(repoName, target.group, target.release)
+	} else {
+		if target.optionalChannel != "" {
+			updateArgs = append(updateArgs, target.optionalChannel)
+		} else {
+			name = "default"
+		}
+		_, err = c.GetRepoChannelReleaseBinDir(repoName, target.group, target.optionalChannel)
+	}
+
+	if err != nil {
+		if target.release != "" {
+			return c.UpdateRepoRelease(repoName, target.group, target.release, true)
+		}
+
+		return c.UpdateRepoChannel(repoName, target.group, target.optionalChannel, true)
+	}
+
+	scheduled, err := c.ScheduleShimBackgroundUpdate(repoName, target.group, name)
+	if err != nil {
+		return err
+	}
+
+	if !scheduled {
+		return nil
+	}
+
+	stderrFile := filepath.Join(c.GetRepoLogsDir(repoName), fmt.Sprintf("shim_%s_%s_background_update_stderr.log", target.group, name))
+	if err := StartUpdateInBackground(os.Args[0], updateArgs, "", stderrFile); err != nil {
+		return fmt.Errorf("unable to start update in background: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	trdlClient "github.com/werf/trdl/client/pkg/client"
+)
+
+func shimsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shims",
+		Short: "Sync the shims and print the shims directory",
+		Long: `Sync the shims and print the shims directory.
+
+The shims directory contains the wrapper per binary name found in the local releases of the repositories, the shims are synced automatically on each update.
+The shim resolves the group and the channel or the exact version of the repository as follows:
+ - $TRDL_USE_<REPO>_GROUP_VERSION ("GROUP VERSION") or $TRDL_USE_<REPO>_GROUP_CHANNEL ("GROUP [CHANNEL]"), the variables are set by the "trdl use" script;
+ - the project file .trdl.yaml or .trdl-version found in the current directory or its parents;
+ - the default channel is used if the channel is not specified.
+The missing release is updated in the foreground, otherwise the update is performed in the background`,
+		Example: `  # Add the shims directory to the PATH
+  $ export PATH="$(trdl shims):$PATH"`,
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.NoArgs(cmd, args); err != nil {
+				PrintHelp(cmd)
+				return err
+			}
+
+			c, err := trdlClient.NewClient(homeDir)
+			if err != nil {
+				return fmt.Errorf("unable to initialize trdl client: %w", err)
+			}
+
+			if err := c.SyncShims(); err != nil {
+				return fmt.Errorf("unable to sync shims: %w", err)
+			}
+
+			fmt.Println(c.GetShimsDir())
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -18,9 +18,11 @@ func updateCmd() *cobra.Command {
 	var inBackground bool
 	var backgroundStdoutFile string
 	var backgroundStderrFile string
+	var version string
+	var targetOS, targetArch string
 
 	cmd := &cobra.Command{
-		Use:                   "update REPO GROUP [CHANNEL]",
+		Use:                   "update REPO GROUP [CHANNEL|--version VERSION]",
 		Short:                 "Update the software",
 		DisableFlagsInUseLine: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -46,7 +48,13 @@ func updateCmd() *cobra.Command {
 				}
 			}
 
-			c, err := trdlClient.NewClient(homeDir)
+			release, err := ProcessVersion(version, optionalChannel)
+			if err != nil {
+				PrintHelp(cmd)
+				return err
+			}
+
+			c, err := trdlClient.NewClientWithOptions(homeDir, trdlClient.ClientOptions{OS: targetOS, Arch: targetArch})
 			if err != nil {
 				return fmt.Errorf("unable to initialize trdl client: %w", err)
 			}
@@ -76,6 +84,14 @@ func updateCmd() *cobra.Command {
 				}
 			}
 
+			if release != "" {
+				if err := c.UpdateRepoRelease(repoName, group, release, autoclean); err != nil {
+					return err
+				}
+
+				return nil
+			}
+
 			if err := c.UpdateRepoChannel(repoName, group, optionalChannel, autoclean); err != nil {
 				return err
 			}
@@ -85,6 +101,8 @@ func updateCmd() *cobra.Command {
 	}
 
 	SetupNoSelfUpdate(cmd, &noSelfUpdate)
+	SetupVersion(cmd, &version)
+	SetupPlatform(cmd, &targetOS, &targetArch)
 	cmd.Flags().BoolVar(&autoclean, "autoclean", true, "Erase old downloaded releases")
 	cmd.Flags().BoolVar(&inBackground, "in-background", false, "Perform update in background")
 	cmd.Flags().StringVarP(&backgroundStdoutFile, "background-stdout-file", "", "", "Redirect the stdout of the background update to a file")
@@ -15,39 +15,68 @@ import (
 func useCmd() *cobra.Command {
 	var noSelfUpdate bool
 	var shell string
+	var version string
 
 	cmd := &cobra.Command{
-		Use:   "use REPO GROUP [CHANNEL]",
+		Use:   "use REPO GROUP [CHANNEL|--version VERSION]",
 		Short: "Generate a script to use the software binaries within a shell session",
-		Long:  `Generate a script to update the software binaries in the background and use local ones within a shell session`,
+		Long: `Generate a script to update the software binaries in the background and use local ones within a shell session.
+
+REPO and GROUP can be omitted if the project file .trdl.yaml or .trdl-version is found in the current directory or its parents: the repository is selected by the optional REPO argument, the group and the channel or the exact version are taken from the file`,
 		Example: `  # Source script in a shell
   $ . $(trdl use repo_name 1.2 ea)
 
   # Force script generation for a Unix shell on Windows
   $ trdl use repo_name 1.2 ea --shell unix
+
+  # Source script to use the exact release
+  $ . $(trdl use repo_name 1.2 --version 1.2.23)
+
+  # Source script to use the release specified in the project file
+  $ . $(trdl use)
 `,
 		DisableFlagsInUseLine: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := cobra.RangeArgs(2, 3)(cmd, args); err != nil {
+			if err := cobra.MaximumNArgs(3)(cmd, args); err != nil {
 				PrintHelp(cmd)
 				return err
 			}
 
-			repoName := args[0]
-			group := args[1]
-
-			if repoName == trdl.SelfUpdateDefaultRepo {
-				PrintHelp(cmd)
-				return fmt.Errorf("reserved repository name %q cannot be used", trdl.SelfUpdateDefaultRepo)
-			}
+			var repoName, group, optionalChannel string
+			if len(args) < 2 {
+				var optionalRepoName string
+				if len(args) == 1 {
+					optionalRepoName = args[0]
+				}
 
-			var optionalChannel string
-			if len(args) == 3 {
-				optionalChannel = args[2]
-				if err := ValidateChannel(optionalChannel); err != nil {
+				entry, err := GetProjectEntry(optionalRepoName)
+				if err != nil {
 					PrintHelp(cmd)
 					return err
 				}
+
+				repoName, group, optionalChannel = entry.Repo, entry.Group, entry.Channel
+				if version == "" {
+					version = entry.Release()
+				} else {
+					optionalChannel = ""
+				}
+			} else {
+				repoName = args[0]
+				group = args[1]
+
+				if repoName == trdl.SelfUpdateDefaultRepo {
+					PrintHelp(cmd)
+					return fmt.Errorf("reserved repository name %q cannot be used", trdl.SelfUpdateDefaultRepo)
+				}
+
+				if len(args) == 3 {
+					optionalChannel = args[2]
+					if err := ValidateChannel(optionalChannel); err != nil {
+						PrintHelp(cmd)
+						return err
+					}
+				}
 			}
 
 			switch shell {
@@ -57,18 +86,25 @@ func useCmd() *cobra.Command {
 				return fmt.Errorf("specified shell %q not supported", shell)
 			}
 
+			release, err := ProcessVersion(version, optionalChannel)
+			if err != nil {
+				PrintHelp(cmd)
+				return err
+			}
+
 			c, err := trdlClient.NewClient(homeDir)
 			if err != nil {
 				return fmt.Errorf("unable to initialize trdl client: %w", err)
 			}
 
-			scriptPath, err := c.UseRepoChannelReleaseBinDir(
-				repoName,
-				group,
-				optionalChannel,
-				shell,
-				repo.UseSourceOptions{NoSelfUpdate: noSelfUpdate},
-			)
+			useSourceOptions := repo.UseSourceOptions{NoSelfUpdate: noSelfUpdate}
+
+			var scriptPath string
+			if release != "" {
+				scriptPath, err = c.UseRepoReleaseBinDir(repoName, group, release, shell, useSourceOptions)
+			} else {
+				scriptPath, err = c.UseRepoChannelReleaseBinDir(repoName, group, optionalChannel, shell, useSourceOptions)
+			}
 			if err != nil {
 				return err
 			}
@@ -81,6 +117,7 @@ func useCmd() *cobra.Command {
 
 	SetupNoSelfUpdate(cmd, &noSelfUpdate)
 	SetupShell(cmd, &shell)
+	SetupVersion(cmd, &version)
 
 	return cmd
 }
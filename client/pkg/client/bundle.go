@@ -0,0 +1,93 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/werf/trdl/client/pkg/repo"
+	"github.com/werf/trdl/client/pkg/trdl"
+	"github.com/werf/trdl/client/pkg/util"
+)
+
+const bundlesDirName = "bundles"
+
+func (c Client) ExportRepoChannel(repoName, group, optionalChannel string, platforms []string, w io.Writer) error {
+	repoClient, err := c.GetRepoClient(repoName)
+	if err != nil {
+		return err
+	}
+
+	// the default channel declared by the repository might be changed since the last update
+	if optionalChannel == "" {
+		if err := repoClient.UpdateChannelsConfig(); err != nil {
+			return fmt.Errorf("unable to update channels config: %w", err)
+		}
+	}
+
+	channel, err := c.processRepoOptionalChannel(repoName, optionalChannel)
+	if err != nil {
+		return err
+	}
+
+	return repoClient.ExportChannel(group, channel, platforms, w)
+}
+
+// ImportRepoBundle installs the channel release from the offline bundle into the repository from the bundle manifest
+// or into the optional repository.
+func (c Client) ImportRepoBundle(r io.Reader, optionalRepoName string) (repo.BundleManifest, error) {
+	bundleDir, err := c.createBundleTmpDir()
+	if err != nil {
+		return repo.BundleManifest{}, err
+	}
+	defer func() { _ = os.RemoveAll(bundleDir) }()
+
+	if err := util.ExtractTarArchive(r, bundleDir); err != nil {
+		return repo.BundleManifest{}, fmt.Errorf("unable to extract bundle: %w", err)
+	}
+
+	manifest, err := repo.ReadBundleManifest(bundleDir)
+	if err != nil {
+		return repo.BundleManifest{}, err
+	}
+
+	if optionalRepoName != "" {
+		manifest.Repo = optionalRepoName
+	}
+
+	if manifest.Repo == trdl.SelfUpdateDefaultRepo {
+		return repo.BundleManifest{}, fmt.Errorf("reserved repository name %q cannot be used", trdl.SelfUpdateDefaultRepo)
+	}
+
+	repoClient, err := c.GetRepoClient(manifest.Repo)
+	if err != nil {
+		return repo.BundleManifest{}, err
+	}
+
+	releaseSwitch, err := repoClient.ImportChannel(bundleDir, manifest.Group, manifest.Channel)
+	if err != nil {
+		return repo.BundleManifest{}, err
+	}
+
+	c.runRepoPostUpdateHooks(repoClient, manifest.Repo, manifest.Group, manifest.Channel, releaseSwitch)
+
+	c.syncShimsAfterChange()
+
+	return manifest, nil
+}
+
+func (c Client) createBundleTmpDir() (string, error) {
+	dir := filepath.Join(c.tmpDir(), bundlesDirName)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("unable to mkdir all %q: %w", dir, err)
+	}
+
+	bundleDir, err := ioutil.TempDir(dir, "")
+	if err != nil {
+		return "", fmt.Errorf("unable to create temporary directory: %w", err)
+	}
+
+	return bundleDir, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/werf/lockgate/pkg/file_locker"
 	"github.com/werf/trdl/client/pkg/repo"
 	"github.com/werf/trdl/client/pkg/trdl"
+	"github.com/werf/trdl/client/pkg/tuf"
 	"github.com/werf/trdl/client/pkg/util"
 )
 
@@ -20,22 +21,36 @@ const (
 	selfUpdateLockFilename        = "self-update"
 	selfUpdateMetafileFilename    = "self-update"
 	selfUpdateDelayBetweenUpdates = time.Second * 30
+
+	mirrorsFetchLogFilename = "mirrors.log"
 )
 
 type Client struct {
 	dir           string
 	configuration configurationInterface
 	locker        lockgate.Locker
+	opts          ClientOptions
+}
+
+type ClientOptions struct {
+	// OS and Arch override the current platform for the releases selection
+	OS   string
+	Arch string
 }
 
 func NewClient(dir string) (Interface, error) {
+	return NewClientWithOptions(dir, ClientOptions{})
+}
+
+func NewClientWithOptions(dir string, opts ClientOptions) (Interface, error) {
 	resolvedPath, err := util.ExpandPath(dir)
 	if err != nil {
 		return nil, fmt.Errorf("unable to expand path %q, %w", dir, err)
 	}
 
 	c := Client{
-		dir: resolvedPath,
+		dir:  resolvedPath,
+		opts: opts,
 	}
 
 	if err := c.init(); err != nil {
@@ -85,7 +100,13 @@ func (c *Client) initConfiguration() error {
 	})
 }
 
-func (c Client) AddRepo(repoName, repoUrl string, rootVersion int64, rootSha512 string) error {
+type AddRepoOptions struct {
+	PGPPublicKey    string
+	PostUpdateHooks []string
+	RemoteOptions   tuf.RemoteOptions
+}
+
+func (c Client) AddRepo(repoName, repoUrl string, rootVersion int64, rootSha512 string, opts AddRepoOptions) error {
 	return lockgate.WithAcquire(c.locker, c.configurationPath(), lockgate.AcquireOptions{Shared: false, Timeout: trdl.DefaultLockerTimeout}, func(_ bool) error {
 		if err := c.configuration.Reload(); err != nil {
 			return err
@@ -93,6 +114,18 @@ func (c Client) AddRepo(repoName, repoUrl string, rootVersion int64, rootSha512
 
 		c.configuration.StageRepoConfiguration(repoName, repoUrl)
 
+		if err := c.configuration.StageRepoPGPPublicKey(repoName, opts.PGPPublicKey); err != nil {
+			return err
+		}
+
+		if err := c.configuration.StageRepoPostUpdateHooks(repoName, opts.PostUpdateHooks); err != nil {
+			return err
+		}
+
+		if err := c.configuration.StageRepoRemoteOptions(repoName, opts.RemoteOptions); err != nil {
+			return err
+		}
+
 		repoClient, err := c.GetRepoClient(repoName)
 		if err != nil {
 			return err
@@ -111,6 +144,16 @@ func (c Client) AddRepo(repoName, repoUrl string, rootVersion int64, rootSha512
 }
 
 func (c Client) RemoveRepo(repoName string) error {
+	if err := c.removeRepo(repoName); err != nil {
+		return err
+	}
+
+	c.syncShimsAfterChange()
+
+	return nil
+}
+
+func (c Client) removeRepo(repoName string) error {
 	return lockgate.WithAcquire(c.locker, c.configurationPath(), lockgate.AcquireOptions{Shared: false, Timeout: trdl.DefaultLockerTimeout}, func(_ bool) error {
 		if err := c.configuration.Reload(); err != nil {
 			return err
@@ -206,6 +249,7 @@ func (c Client) doSelfUpdate(autocleanReleases bool) error {
 			trdl.SelfUpdateDefaultUrl,
 			trdl.SelfUpdateDefaultRootVersion,
 			trdl.SelfUpdateDefaultRootSha512,
+			AddRepoOptions{},
 		); err != nil {
 			return err
 		}
@@ -221,7 +265,7 @@ func (c Client) doSelfUpdate(autocleanReleases bool) error {
 		return err
 	}
 
-	if err = repoClient.UpdateChannel(trdl.SelfUpdateDefaultGroup, channel); err != nil {
+	if _, err = repoClient.UpdateChannel(trdl.SelfUpdateDefaultGroup, channel); err != nil {
 		return err
 	}
 
@@ -259,29 +303,109 @@ func (c Client) doSelfUpdate(autocleanReleases bool) error {
 }
 
 func (c Client) UpdateRepoChannel(repoName, group, optionalChannel string, autocleanReleases bool) error {
-	channel, err := c.processRepoOptionalChannel(repoName, optionalChannel)
+	repoClient, err := c.GetRepoClient(repoName)
 	if err != nil {
 		return err
 	}
 
-	repoClient, err := c.GetRepoClient(repoName)
+	// the default channel declared by the repository might be changed since the last update
+	if optionalChannel == "" {
+		if err := repoClient.UpdateChannelsConfig(); err != nil {
+			return fmt.Errorf("unable to update channels config: %w", err)
+		}
+	}
+
+	channel, err := c.processRepoOptionalChannel(repoName, optionalChannel)
 	if err != nil {
 		return err
 	}
 
-	if err := repoClient.UpdateChannel(group, channel); err != nil {
+	releaseSwitch, err := repoClient.UpdateChannel(group, channel)
+	if err != nil {
 		return err
 	}
 
+	c.runRepoPostUpdateHooks(repoClient, repoName, group, channel, releaseSwitch)
+
 	if autocleanReleases {
 		if err := repoClient.CleanReleases(); err != nil {
 			return fmt.Errorf("unable to clean old releases: %w", err)
 		}
 	}
 
+	c.syncShimsAfterChange()
+
 	return nil
 }
 
+// runRepoPostUpdateHooks runs the post-update hooks of the repository if the update has switched the channel release,
+// the failures are only reported since the channel is already updated.
+func (c Client) runRepoPostUpdateHooks(repoClient RepoInterface, repoName, group, channel string, releaseSwitch *repo.ChannelReleaseSwitch) {
+	if releaseSwitch == nil {
+		return
+	}
+
+	repoConfiguration, err := c.getRepoConfiguration(repoName)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "WARNING: Unable to run post-update hooks (repo: %q, group: %q, channel: %q): %s\n", repoName, group, channel, err)
+		return
+	}
+
+	if len(repoConfiguration.PostUpdate) == 0 {
+		return
+	}
+
+	releaseDir, err := repoClient.GetReleaseDir(releaseSwitch.NewRelease)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "WARNING: Unable to run post-update hooks (repo: %q, group: %q, channel: %q): %s\n", repoName, group, channel, err)
+		return
+	}
+
+	runPostUpdateHooks(repoConfiguration.PostUpdate, postUpdateHookEnv{
+		RepoName:   repoName,
+		Group:      group,
+		Channel:    channel,
+		OldRelease: releaseSwitch.OldRelease,
+		NewRelease: releaseSwitch.NewRelease,
+		ReleaseDir: releaseDir,
+	})
+}
+
+func (c Client) RollbackRepoChannel(repoName, group, optionalChannel string) (string, error) {
+	channel, err := c.processRepoOptionalChannel(repoName, optionalChannel)
+	if err != nil {
+		return "", err
+	}
+
+	repoClient, err := c.GetRepoClient(repoName)
+	if err != nil {
+		return "", err
+	}
+
+	release, err := repoClient.RollbackChannel(group, channel)
+	if err != nil {
+		return "", err
+	}
+
+	c.syncShimsAfterChange()
+
+	return release, nil
+}
+
+func (c Client) ReleaseRepoChannelHold(repoName, group, optionalChannel string) error {
+	channel, err := c.processRepoOptionalChannel(repoName, optionalChannel)
+	if err != nil {
+		return err
+	}
+
+	repoClient, err := c.GetRepoClient(repoName)
+	if err != nil {
+		return err
+	}
+
+	return repoClient.ReleaseChannelHold(group, channel)
+}
+
 func (c Client) UseRepoChannelReleaseBinDir(repoName, group, optionalChannel, shell string, opts repo.UseSourceOptions) (string, error) {
 	channel, err := c.processRepoOptionalChannel(repoName, optionalChannel)
 	if err != nil {
@@ -315,9 +439,9 @@ func (c Client) ExecRepoChannelReleaseBin(repoName, group, optionalChannel, opti
 	if err := repoClient.ExecChannelReleaseBin(group, channel, optionalBinName, args); err != nil {
 		switch e := err.(type) {
 		case repo.ChannelNotFoundLocallyError:
-			return prepareChannelNotFoundLocallyErr(e)
+			return c.prepareChannelNotFoundLocallyErr(e)
 		case repo.ChannelReleaseNotFoundLocallyError:
-			return prepareChannelReleaseNotFoundLocallyErr(e)
+			return c.prepareChannelReleaseNotFoundLocallyErr(e)
 		case repo.ChannelReleaseBinSeveralFilesFoundError:
 			return prepareChannelReleaseBinSeveralFilesFoundErr(e)
 		}
@@ -343,9 +467,9 @@ func (c Client) GetRepoChannelReleaseDir(repoName, group, optionalChannel string
 	if err != nil {
 		switch e := err.(type) {
 		case repo.ChannelNotFoundLocallyError:
-			return "", prepareChannelNotFoundLocallyErr(e)
+			return "", c.prepareChannelNotFoundLocallyErr(e)
 		case repo.ChannelReleaseNotFoundLocallyError:
-			return "", prepareChannelReleaseNotFoundLocallyErr(e)
+			return "", c.prepareChannelReleaseNotFoundLocallyErr(e)
 		}
 
 		return "", err
@@ -369,9 +493,9 @@ func (c Client) GetRepoChannelReleaseBinDir(repoName, group, optionalChannel str
 	if err != nil {
 		switch e := err.(type) {
 		case repo.ChannelNotFoundLocallyError:
-			return "", prepareChannelNotFoundLocallyErr(e)
+			return "", c.prepareChannelNotFoundLocallyErr(e)
 		case repo.ChannelReleaseNotFoundLocallyError:
-			return "", prepareChannelReleaseNotFoundLocallyErr(e)
+			return "", c.prepareChannelReleaseNotFoundLocallyErr(e)
 		}
 
 		return "", err
@@ -380,23 +504,158 @@ func (c Client) GetRepoChannelReleaseBinDir(repoName, group, optionalChannel str
 	return dir, nil
 }
 
-func prepareChannelNotFoundLocallyErr(e repo.ChannelNotFoundLocallyError) error {
+func (c Client) UpdateRepoRelease(repoName, group, release string, autocleanReleases bool) error {
+	repoClient, err := c.getRepoReleaseClient(repoName, group, release)
+	if err != nil {
+		return err
+	}
+
+	if err := repoClient.UpdateRelease(release); err != nil {
+		return err
+	}
+
+	if autocleanReleases {
+		if err := repoClient.CleanReleases(); err != nil {
+			return fmt.Errorf("unable to clean old releases: %w", err)
+		}
+	}
+
+	c.syncShimsAfterChange()
+
+	return nil
+}
+
+func (c Client) UseRepoReleaseBinDir(repoName, group, release, shell string, opts repo.UseSourceOptions) (string, error) {
+	repoClient, err := c.getRepoReleaseClient(repoName, group, release)
+	if err != nil {
+		return "", err
+	}
+
+	return repoClient.UseReleaseBinDir(group, release, shell, opts)
+}
+
+func (c Client) ExecRepoReleaseBin(repoName, group, release, optionalBinName string, args []string) error {
+	repoClient, err := c.getRepoReleaseClient(repoName, group, release)
+	if err != nil {
+		return err
+	}
+
+	if err := repoClient.ExecReleaseBin(release, optionalBinName, args); err != nil {
+		switch e := err.(type) {
+		case repo.ReleaseNotFoundLocallyError:
+			return c.prepareReleaseNotFoundLocallyErr(e, group)
+		case repo.ReleaseBinSeveralFilesFoundError:
+			return fmt.Errorf(
+				"%w: it is necessary to specify the certain name:\n - %s",
+				e,
+				strings.Join(e.Names, "\n - "),
+			)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (c Client) GetRepoReleaseDir(repoName, group, release string) (string, error) {
+	repoClient, err := c.getRepoReleaseClient(repoName, group, release)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := repoClient.GetReleaseDir(release)
+	if e, ok := err.(repo.ReleaseNotFoundLocallyError); ok {
+		return "", c.prepareReleaseNotFoundLocallyErr(e, group)
+	}
+
+	return dir, err
+}
+
+func (c Client) GetRepoReleaseBinDir(repoName, group, release string) (string, error) {
+	repoClient, err := c.getRepoReleaseClient(repoName, group, release)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := repoClient.GetReleaseBinDir(release)
+	if e, ok := err.(repo.ReleaseNotFoundLocallyError); ok {
+		return "", c.prepareReleaseNotFoundLocallyErr(e, group)
+	}
+
+	return dir, err
+}
+
+func (c Client) getRepoReleaseClient(repoName, group, release string) (RepoInterface, error) {
+	if !isGroupRelease(group, release) {
+		return nil, fmt.Errorf("release %q does not belong to group %q", release, group)
+	}
+
+	return c.GetRepoClient(repoName)
+}
+
+// isGroupRelease checks the group is the semver part of the release, e.g. the group 1.2 for the release 1.2.23.
+func isGroupRelease(group, release string) bool {
+	if release == group {
+		return true
+	}
+
+	if !strings.HasPrefix(release, group) {
+		return false
+	}
+
+	switch release[len(group)] {
+	case '.', '-', '+':
+		return true
+	default:
+		return false
+	}
+}
+
+func (c Client) prepareReleaseNotFoundLocallyErr(e repo.ReleaseNotFoundLocallyError, group string) error {
 	return fmt.Errorf(
-		"%w, update channel with \"trdl update %s %s %s\" command",
+		"%w, update release with \"trdl update %s %s --version %s%s\" command",
+		e,
+		e.RepoName,
+		group,
+		e.Release,
+		c.platformArgs(),
+	)
+}
+
+// platformArgs returns the update command options selecting the overridden platform.
+func (c Client) platformArgs() string {
+	var args string
+	if c.opts.OS != "" {
+		args += " --os " + c.opts.OS
+	}
+
+	if c.opts.Arch != "" {
+		args += " --arch " + c.opts.Arch
+	}
+
+	return args
+}
+
+func (c Client) prepareChannelNotFoundLocallyErr(e repo.ChannelNotFoundLocallyError) error {
+	return fmt.Errorf(
+		"%w, update channel with \"trdl update %s %s %s%s\" command",
 		e,
 		e.RepoName,
 		e.Group,
 		e.Channel,
+		c.platformArgs(),
 	)
 }
 
-func prepareChannelReleaseNotFoundLocallyErr(e repo.ChannelReleaseNotFoundLocallyError) error {
+func (c Client) prepareChannelReleaseNotFoundLocallyErr(e repo.ChannelReleaseNotFoundLocallyError) error {
 	return fmt.Errorf(
-		"%w, update channel with \"trdl update %s %s %s\" command",
+		"%w, update channel with \"trdl update %s %s %s%s\" command",
 		e,
 		e.RepoName,
 		e.Group,
 		e.Channel,
+		c.platformArgs(),
 	)
 }
 
@@ -408,6 +667,42 @@ func prepareChannelReleaseBinSeveralFilesFoundErr(e repo.ChannelReleaseBinSevera
 	)
 }
 
+func (c Client) GetRepoChannelsConfig(repoName string) (trdl.ChannelsConfig, error) {
+	repoClient, err := c.GetRepoClient(repoName)
+	if err != nil {
+		return trdl.ChannelsConfig{}, err
+	}
+
+	return repoClient.GetChannelsConfig()
+}
+
+func (c Client) GetRepoRemoteChannels(repoName, optionalGroup string) ([]*repo.RemoteChannel, error) {
+	repoClient, err := c.GetRepoClient(repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	return repoClient.GetRemoteChannels(optionalGroup)
+}
+
+func (c Client) GetRepoRemoteReleasesIndex(repoName string) (trdl.ReleasesIndex, error) {
+	repoClient, err := c.GetRepoClient(repoName)
+	if err != nil {
+		return trdl.ReleasesIndex{}, err
+	}
+
+	return repoClient.GetRemoteReleasesIndex()
+}
+
+func (c Client) GetRepoRemoteChannelsHistory(repoName string) (trdl.ChannelsHistory, error) {
+	repoClient, err := c.GetRepoClient(repoName)
+	if err != nil {
+		return trdl.ChannelsHistory{}, err
+	}
+
+	return repoClient.GetRemoteChannelsHistory()
+}
+
 func (c Client) GetRepoList() []*RepoConfiguration {
 	return c.configuration.GetRepoConfigurationList()
 }
@@ -422,17 +717,32 @@ func (c Client) repoClient(repoName string) (RepoInterface, error) {
 		return nil, err
 	}
 
-	repoUrl, err := c.getRepoRemoteUrl(repoName)
+	repoConfiguration, err := c.getRepoConfiguration(repoName)
 	if err != nil {
 		return nil, err
 	}
 
+	// trdl itself is always updated for the current platform
+	opts := c.opts
+	if repoName == trdl.SelfUpdateDefaultRepo {
+		opts = ClientOptions{}
+	}
+
+	remoteOptions := repoConfiguration.RemoteOptions()
+	remoteOptions.MirrorsFetchLogPath = filepath.Join(c.repoLogsDir(repoName), mirrorsFetchLogFilename)
+
 	return repo.NewClient(
-		repoName, repoDir, repoUrl,
+		repoName, repoDir, repoConfiguration.Url,
 		c.repoLocksDir(repoName),
 		c.repoTmpDir(repoName),
 		c.repoLogsDir(repoName),
 		c.repoMetafileDir(repoName),
+		repo.ClientOptions{
+			PGPPublicKey:  repoConfiguration.PGPPublicKey,
+			OS:            opts.OS,
+			Arch:          opts.Arch,
+			RemoteOptions: remoteOptions,
+		},
 	)
 }
 
@@ -440,30 +750,31 @@ func (c *Client) repoDir(repoName string) string {
 	return filepath.Join(c.dir, "repositories", repoName)
 }
 
-func (c *Client) getRepoRemoteUrl(repoName string) (string, error) {
+func (c *Client) processRepoOptionalChannel(repoName, optionalChannel string) (string, error) {
+	if optionalChannel != "" {
+		return optionalChannel, nil
+	}
+
 	repoConfiguration, err := c.getRepoConfiguration(repoName)
 	if err != nil {
 		return "", err
 	}
 
-	return repoConfiguration.Url, nil
-}
-
-func (c *Client) processRepoOptionalChannel(repoName, optionalChannel string) (string, error) {
-	if optionalChannel != "" {
-		return optionalChannel, nil
+	if repoConfiguration.DefaultChannel != "" {
+		return repoConfiguration.DefaultChannel, nil
 	}
 
-	repoConfiguration, err := c.getRepoConfiguration(repoName)
+	repoClient, err := c.GetRepoClient(repoName)
 	if err != nil {
 		return "", err
 	}
 
-	if repoConfiguration.DefaultChannel == "" {
-		return trdl.DefaultChannel, nil
+	channelsConfig, err := repoClient.GetChannelsConfig()
+	if err != nil {
+		return "", err
 	}
 
-	return repoConfiguration.DefaultChannel, nil
+	return channelsConfig.DefaultChannel, nil
 }
 
 func (c *Client) getRepoConfiguration(repoName string) (*RepoConfiguration, error) {
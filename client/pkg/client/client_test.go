@@ -0,0 +1,29 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsGroupRelease(t *testing.T) {
+	for _, tc := range []struct {
+		group    string
+		release  string
+		expected bool
+	}{
+		{group: "1.2", release: "1.2", expected: true},
+		{group: "1.2", release: "1.2.23", expected: true},
+		{group: "1.2", release: "1.2-rc.1", expected: true},
+		{group: "1.2", release: "1.2+build.1", expected: true},
+		{group: "1", release: "1.2.23", expected: true},
+		{group: "1.2", release: "1.20.1", expected: false},
+		{group: "1.2", release: "1.3.0", expected: false},
+		{group: "1.2.23", release: "1.2", expected: false},
+		{group: "1", release: "10.0.0", expected: false},
+	} {
+		t.Run(tc.group+" "+tc.release, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isGroupRelease(tc.group, tc.release))
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/werf/trdl/client/pkg/tuf"
 	"github.com/werf/trdl/client/pkg/util"
 )
 
@@ -31,12 +32,46 @@ type RepoConfiguration struct {
 	Name           string `yaml:"name"`
 	Url            string `yaml:"url"`
 	DefaultChannel string `yaml:"defaultChannel"`
+	PGPPublicKey   string `yaml:"pgpPublicKey,omitempty"`
+	// PostUpdate commands are run by the shell after the channel is switched to the new release
+	PostUpdate []string `yaml:"postUpdate,omitempty"`
+	// Proxy, CACertFile, ClientCertFile, ClientKeyFile and Headers are the TUF repository connection settings
+	Proxy          string            `yaml:"proxy,omitempty"`
+	CACertFile     string            `yaml:"caCertFile,omitempty"`
+	ClientCertFile string            `yaml:"clientCertFile,omitempty"`
+	ClientKeyFile  string            `yaml:"clientKeyFile,omitempty"`
+	Headers        map[string]string `yaml:"headers,omitempty"`
+	// Mirrors are tried in order after the Url if the fetch fails or all at once if RaceMirrors is enabled
+	Mirrors     []string `yaml:"mirrors,omitempty"`
+	RaceMirrors bool     `yaml:"raceMirrors,omitempty"`
 }
 
 func newRepoConfiguration(name, url string) *RepoConfiguration {
 	return &RepoConfiguration{Name: name, Url: url}
 }
 
+// RemoteOptions returns the TUF repository connection settings,
+// the environment variables in the header values are expanded to keep the secrets out of the configuration.
+func (c RepoConfiguration) RemoteOptions() tuf.RemoteOptions {
+	var headers map[string]string
+	if len(c.Headers) != 0 {
+		headers = map[string]string{}
+		for name, value := range c.Headers {
+			headers[name] = os.ExpandEnv(value)
+		}
+	}
+
+	return tuf.RemoteOptions{
+		Proxy:          c.Proxy,
+		CACertFile:     c.CACertFile,
+		ClientCertFile: c.ClientCertFile,
+		ClientKeyFile:  c.ClientKeyFile,
+		Headers:        headers,
+		Mirrors:        c.Mirrors,
+		RaceMirrors:    c.RaceMirrors,
+	}
+}
+
 func (c configuration) GetRepoConfigurationList() []*RepoConfiguration {
 	return c.Repositories
 }
@@ -85,6 +120,45 @@ func (c *configuration) StageRepoDefaultChannel(name, channel string) error {
 	return nil
 }
 
+func (c *configuration) StageRepoPGPPublicKey(name, pgpPublicKey string) error {
+	repo := c.GetRepoConfiguration(name)
+	if repo == nil {
+		return errRepoConfigurationNotFound
+	}
+
+	repo.PGPPublicKey = pgpPublicKey
+
+	return nil
+}
+
+func (c *configuration) StageRepoPostUpdateHooks(name string, hooks []string) error {
+	repo := c.GetRepoConfiguration(name)
+	if repo == nil {
+		return errRepoConfigurationNotFound
+	}
+
+	repo.PostUpdate = hooks
+
+	return nil
+}
+
+func (c *configuration) StageRepoRemoteOptions(name string, opts tuf.RemoteOptions) error {
+	repo := c.GetRepoConfiguration(name)
+	if repo == nil {
+		return errRepoConfigurationNotFound
+	}
+
+	repo.Proxy = opts.Proxy
+	repo.CACertFile = opts.CACertFile
+	repo.ClientCertFile = opts.ClientCertFile
+	repo.ClientKeyFile = opts.ClientKeyFile
+	repo.Headers = opts.Headers
+	repo.Mirrors = opts.Mirrors
+	repo.RaceMirrors = opts.RaceMirrors
+
+	return nil
+}
+
 func (c *configuration) Reload() error {
 	return c.load()
 }
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// postUpdateHookEnv describes the channel release switch to the post-update hooks.
+type postUpdateHookEnv struct {
+	RepoName   string
+	Group      string
+	Channel    string
+	OldRelease string
+	NewRelease string
+	ReleaseDir string
+}
+
+func (e postUpdateHookEnv) environ() []string {
+	return append(os.Environ(),
+		"TRDL_REPO="+e.RepoName,
+		"TRDL_GROUP="+e.Group,
+		"TRDL_CHANNEL="+e.Channel,
+		"TRDL_OLD_RELEASE="+e.OldRelease,
+		"TRDL_NEW_RELEASE="+e.NewRelease,
+		"TRDL_RELEASE_DIR="+e.ReleaseDir,
+	)
+}
+
+// runPostUpdateHooks runs the hooks one by one, the failures are only reported since the channel is already updated.
+func runPostUpdateHooks(hooks []string, env postUpdateHookEnv) {
+	for _, hook := range hooks {
+		if err := runHook(hook, env.environ()); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "WARNING: Post-update hook %q failed (repo: %q, group: %q, channel: %q, release: %q): %s\n", hook, env.RepoName, env.Group, env.Channel, env.NewRelease, err)
+		}
+	}
+}
+
+func runHook(hook string, environ []string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", hook)
+	} else {
+		cmd = exec.Command("sh", "-c", hook)
+	}
+
+	// the stdout of the update might be the script data, thus the hook output is redirected to stderr
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	cmd.Env = environ
+
+	return cmd.Run()
+}
@@ -1,30 +1,67 @@
 package client
 
-import "github.com/werf/trdl/client/pkg/repo"
+import (
+	"io"
+
+	"github.com/werf/trdl/client/pkg/repo"
+	"github.com/werf/trdl/client/pkg/trdl"
+	"github.com/werf/trdl/client/pkg/tuf"
+)
 
 type Interface interface {
-	AddRepo(repoName, repoUrl string, rootVersion int64, rootSha512 string) error
+	AddRepo(repoName, repoUrl string, rootVersion int64, rootSha512 string, opts AddRepoOptions) error
 	RemoveRepo(repoName string) error
 	SetRepoDefaultChannel(repoName, channel string) error
 	DoSelfUpdate(autocleanReleases bool) error
 	UpdateRepoChannel(repoName, group, optionalChannel string, autocleanReleases bool) error
+	RollbackRepoChannel(repoName, group, optionalChannel string) (string, error)
+	ReleaseRepoChannelHold(repoName, group, optionalChannel string) error
+	ExportRepoChannel(repoName, group, optionalChannel string, platforms []string, w io.Writer) error
+	ImportRepoBundle(r io.Reader, optionalRepoName string) (repo.BundleManifest, error)
 	UseRepoChannelReleaseBinDir(repoName, group, optionalChannel, shell string, opts repo.UseSourceOptions) (string, error)
 	ExecRepoChannelReleaseBin(repoName, group, optionalChannel, optionalBinName string, args []string) error
 	GetRepoChannelReleaseDir(repoName, group, optionalChannel string) (string, error)
 	GetRepoChannelReleaseBinDir(repoName, group, optionalChannel string) (string, error)
+	UpdateRepoRelease(repoName, group, release string, autocleanReleases bool) error
+	UseRepoReleaseBinDir(repoName, group, release, shell string, opts repo.UseSourceOptions) (string, error)
+	ExecRepoReleaseBin(repoName, group, release, optionalBinName string, args []string) error
+	GetRepoReleaseDir(repoName, group, release string) (string, error)
+	GetRepoReleaseBinDir(repoName, group, release string) (string, error)
+	GetRepoChannelsConfig(repoName string) (trdl.ChannelsConfig, error)
+	GetRepoRemoteChannels(repoName, optionalGroup string) ([]*repo.RemoteChannel, error)
+	GetRepoRemoteReleasesIndex(repoName string) (trdl.ReleasesIndex, error)
+	GetRepoRemoteChannelsHistory(repoName string) (trdl.ChannelsHistory, error)
 	GetRepoList() []*RepoConfiguration
+	GetRepoLogsDir(repoName string) string
+	GetShimsDir() string
+	SyncShims() error
+	ScheduleShimBackgroundUpdate(repoName, group, name string) (bool, error)
 	GetRepoClient(repoName string) (RepoInterface, error)
 }
 
 type RepoInterface interface {
 	Setup(rootVersion int64, rootSha512 string) error
-	UpdateChannel(group, channel string) error
+	UpdateChannel(group, channel string) (*repo.ChannelReleaseSwitch, error)
+	RollbackChannel(group, channel string) (string, error)
+	ReleaseChannelHold(group, channel string) error
+	ExportChannel(group, channel string, platforms []string, w io.Writer) error
+	ImportChannel(bundleDir, group, channel string) (*repo.ChannelReleaseSwitch, error)
 	UseChannelReleaseBinDir(group, channel, shell string, opts repo.UseSourceOptions) (string, error)
 	ExecChannelReleaseBin(group, channel, optionalBinName string, args []string) error
 	GetChannelRelease(group, channel string) (string, error)
 	GetChannelReleaseDir(group, channel string) (string, error)
 	GetChannelReleaseBinDir(group, channel string) (string, error)
 	GetChannelReleaseBinPath(group, channel, optionalBinName string) (string, error)
+	UpdateRelease(release string) error
+	UseReleaseBinDir(group, release, shell string, opts repo.UseSourceOptions) (string, error)
+	ExecReleaseBin(release, optionalBinName string, args []string) error
+	GetReleaseDir(release string) (string, error)
+	GetReleaseBinDir(release string) (string, error)
+	UpdateChannelsConfig() error
+	GetChannelsConfig() (trdl.ChannelsConfig, error)
+	GetRemoteChannels(optionalGroup string) ([]*repo.RemoteChannel, error)
+	GetRemoteReleasesIndex() (trdl.ReleasesIndex, error)
+	GetRemoteChannelsHistory() (trdl.ChannelsHistory, error)
 	CleanReleases() error
 }
 
@@ -32,6 +69,9 @@ type configurationInterface interface {
 	RemoveRepoConfiguration(name string) error
 	StageRepoConfiguration(name, url string)
 	StageRepoDefaultChannel(name, channel string) error
+	StageRepoPGPPublicKey(name, pgpPublicKey string) error
+	StageRepoPostUpdateHooks(name string, hooks []string) error
+	StageRepoRemoteOptions(name string, opts tuf.RemoteOptions) error
 	Reload() error
 	Save(configPath string) error
 	GetRepoConfiguration(name string) *RepoConfiguration
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/werf/lockgate"
+	"github.com/werf/trdl/client/pkg/repo"
+	"github.com/werf/trdl/client/pkg/trdl"
+	"github.com/werf/trdl/client/pkg/util"
+)
+
+const (
+	shimsDirName                  = "shims"
+	shimsLockName                 = "shims"
+	shimBackgroundUpdateDelay     = time.Minute
+	shimBackgroundUpdateMetafiles = "shims"
+)
+
+// shimBinNameRegexp matches the bin names safe to be used as the shim file and command names.
+var shimBinNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9._+-]*$`)
+
+func (c Client) GetShimsDir() string {
+	return filepath.Join(c.dir, shimsDirName)
+}
+
+func (c Client) GetRepoLogsDir(repoName string) string {
+	return c.repoLogsDir(repoName)
+}
+
+// SyncShims creates the shim per bin file name of the local releases and removes the stale ones.
+// The bin file name found in several repositories is bound to the first repository in alphabetical order.
+func (c Client) SyncShims() error {
+	trdlBinPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("unable to get trdl executable path: %w", err)
+	}
+
+	return lockgate.WithAcquire(c.locker, shimsLockName, lockgate.AcquireOptions{Shared: false, Timeout: trdl.DefaultLockerTimeout}, func(_ bool) error {
+		shims, err := c.getShimsData(trdlBinPath)
+		if err != nil {
+			return err
+		}
+
+		shimsDir := c.GetShimsDir()
+		if err := os.MkdirAll(shimsDir, os.ModePerm); err != nil {
+			return fmt.Errorf("unable to create directory %q: %w", shimsDir, err)
+		}
+
+		for name, data := range shims {
+			if err := syncShimFile(filepath.Join(shimsDir, name), data); err != nil {
+				return err
+			}
+		}
+
+		entries, err := ioutil.ReadDir(shimsDir)
+		if err != nil {
+			return fmt.Errorf("unable to read directory %q: %w", shimsDir, err)
+		}
+
+		for _, entry := range entries {
+			if _, ok := shims[entry.Name()]; ok {
+				continue
+			}
+
+			path := filepath.Join(shimsDir, entry.Name())
+			if err := os.RemoveAll(path); err != nil {
+				return fmt.Errorf("unable to remove %q: %w", path, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// syncShimsAfterChange syncs the shims after the local releases have been changed,
+// the failure is only reported since the change itself is already done.
+func (c Client) syncShimsAfterChange() {
+	if err := c.SyncShims(); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "WARNING: Unable to sync shims: %s\n", err)
+	}
+}
+
+// getShimsData returns the shim file data by the shim file name.
+// The shims exec the binaries of the current platform, thus the local releases of the current platform are used.
+func (c Client) getShimsData(trdlBinPath string) (map[string][]byte, error) {
+	var repoNames []string
+	for _, repoConfiguration := range c.GetRepoList() {
+		if repoConfiguration.Name != trdl.SelfUpdateDefaultRepo {
+			repoNames = append(repoNames, repoConfiguration.Name)
+		}
+	}
+	sort.Strings(repoNames)
+
+	shims := map[string][]byte{}
+	for _, repoName := range repoNames {
+		binNames, err := repo.GetNativeLocalBinNames(c.repoDir(repoName))
+		if err != nil {
+			return nil, fmt.Errorf("unable to get repository %q bin names: %w", repoName, err)
+		}
+
+		for _, binName := range binNames {
+			if !shimBinNameRegexp.MatchString(binName) {
+				_, _ = fmt.Fprintf(os.Stderr, "WARNING: Shim for bin file %q of repository %q skipped: the name is not safe to be used as the command name\n", binName, repoName)
+				continue
+			}
+
+			name, data, ok := c.prepareShimFileNameAndData(trdlBinPath, repoName, binName)
+			if !ok {
+				_, _ = fmt.Fprintf(os.Stderr, "WARNING: Shim for bin file %q of repository %q skipped: the paths cannot be quoted in the shim script\n", binName, repoName)
+				continue
+			}
+
+			if _, ok := shims[name]; ok {
+				continue
+			}
+
+			shims[name] = data
+		}
+	}
+
+	return shims, nil
+}
+
+// prepareShimFileNameAndData returns false if the values cannot be quoted in the shim script.
+func (c Client) prepareShimFileNameAndData(trdlBinPath, repoName, binName string) (string, []byte, bool) {
+	if runtime.GOOS == "windows" {
+		values := []string{trdlBinPath, c.dir, repoName, binName}
+		for _, value := range values {
+			// the double quote cannot be escaped inside the quoted cmd argument
+			if strings.Contains(value, `"`) {
+				return "", nil, false
+			}
+		}
+
+		name := strings.TrimSuffix(binName, filepath.Ext(binName)) + ".cmd"
+		script := fmt.Sprintf("@echo off\r\n%s --home-dir %s shim-exec %s %s -- %%*\r\n", cmdQuote(trdlBinPath), cmdQuote(c.dir), cmdQuote(repoName), cmdQuote(binName))
+		return name, []byte(script), true
+	}
+
+	script := fmt.Sprintf("#!/bin/sh\nexec %s --home-dir %s shim-exec %s %s -- \"$@\"\n", shQuote(trdlBinPath), shQuote(c.dir), shQuote(repoName), shQuote(binName))
+	return binName, []byte(script), true
+}
+
+// shQuote quotes the value in single quotes, nothing is expanded by sh inside them.
+func shQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// cmdQuote quotes the value in double quotes escaping the variable expansion of the cmd script.
+func cmdQuote(value string) string {
+	return `"` + strings.ReplaceAll(value, "%", "%%") + `"`
+}
+
+func syncShimFile(path string, data []byte) error {
+	currentData, err := ioutil.ReadFile(path)
+	if err == nil && bytes.Equal(currentData, data) {
+		return nil
+	}
+
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0o755); err != nil {
+		return fmt.Errorf("unable to write file %q: %w", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("unable to rename %q to %q: %w", tmpPath, path, err)
+	}
+
+	return nil
+}
+
+// ScheduleShimBackgroundUpdate reports whether the shim should start the background update of the channel or the release,
+// the update is scheduled at most once per delay period.
+func (c Client) ScheduleShimBackgroundUpdate(repoName, group, name string) (bool, error) {
+	metafile := util.NewMetafile(filepath.Join(c.repoMetafileDir(repoName), shimBackgroundUpdateMetafiles, strings.Join([]string{group, name}, "-")))
+
+	isRecentlyUpdated, err := metafile.HasBeenModifiedWithinPeriod(c.locker, shimBackgroundUpdateDelay)
+	if err != nil {
+		return false, fmt.Errorf("unable to check metafile: %w", err)
+	}
+
+	if isRecentlyUpdated {
+		return false, nil
+	}
+
+	if err := metafile.Reset(c.locker); err != nil {
+		return false, fmt.Errorf("unable to reset metafile: %w", err)
+	}
+
+	return true, nil
+}
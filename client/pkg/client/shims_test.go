@@ -0,0 +1,59 @@
+package client
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShimBinNameRegexp(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"werf":         true,
+		"kubectl-1.25": true,
+		"app_v2+x":     true,
+		"app.exe":      true,
+		".hidden":      false,
+		"-rf":          false,
+		"app name":     false,
+		"app;rm":       false,
+		"$(reboot)":    false,
+		"app`id`":      false,
+		"app'x":        false,
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, expected, shimBinNameRegexp.MatchString(name))
+		})
+	}
+}
+
+func TestPrepareShimFileNameAndData_Quoting(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is not available")
+	}
+
+	dir := t.TempDir()
+	c := Client{dir: filepath.Join(dir, "home $HOME `id` 'quoted'")}
+
+	// the shim execs echo to print the arguments as they are passed
+	echoPath, err := exec.LookPath("echo")
+	assert.Nil(t, err)
+
+	name, data, ok := c.prepareShimFileNameAndData(echoPath, "repo $(id) 'x'", "app")
+	assert.True(t, ok)
+	assert.Equal(t, "app", name)
+
+	shimPath := filepath.Join(dir, name)
+	assert.Nil(t, os.WriteFile(shimPath, data, 0o755))
+
+	output, err := exec.Command(shimPath, "arg $1").Output()
+	assert.Nil(t, err)
+	assert.Equal(t, "--home-dir "+c.dir+" shim-exec repo $(id) 'x' app -- arg $1\n", string(output))
+}
+
+func TestCmdQuote(t *testing.T) {
+	assert.Equal(t, `"C:\trdl %%PATH%% & dir"`, cmdQuote(`C:\trdl %PATH% & dir`))
+}
@@ -0,0 +1,202 @@
+package project
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/werf/trdl/client/pkg/trdl"
+	"github.com/werf/trdl/client/pkg/util"
+)
+
+const (
+	// YamlFileName contains the single entry:
+	//
+	//	repo: werf
+	//	group: "1.2"
+	//	channel: stable # or version: 1.2.23
+	YamlFileName = ".trdl.yaml"
+
+	// VersionFileName contains the entry per line in format "REPO GROUP CHANNEL|VERSION",
+	// the version is distinguished from the channel by the dot which is not allowed in the channel name.
+	VersionFileName = ".trdl-version"
+)
+
+// Entry selects the channel or the exact release of the repository group.
+type Entry struct {
+	Repo    string `yaml:"repo"`
+	Group   string `yaml:"group"`
+	Channel string `yaml:"channel,omitempty"`
+	Version string `yaml:"version,omitempty"`
+}
+
+// Release returns the release name of the exact version or an empty string if the channel is used.
+func (e Entry) Release() string {
+	return strings.TrimPrefix(e.Version, "v")
+}
+
+type File struct {
+	Path    string
+	Entries []Entry
+}
+
+// Find looks for the project file in the directory and its parents, nil is returned if nothing found.
+// The .trdl.yaml file takes precedence over the .trdl-version file in the same directory.
+func Find(dir string) (*File, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get absolute path of %q: %w", dir, err)
+	}
+
+	for {
+		for _, name := range []string{YamlFileName, VersionFileName} {
+			path := filepath.Join(dir, name)
+			exist, err := util.IsRegularFileExist(path)
+			if err != nil {
+				return nil, fmt.Errorf("unable to check existence of file %q: %w", path, err)
+			}
+
+			if exist {
+				return Load(path)
+			}
+		}
+
+		parentDir := filepath.Dir(dir)
+		if parentDir == dir {
+			return nil, nil
+		}
+		dir = parentDir
+	}
+}
+
+// FindInWorkingDir looks for the project file starting from the current directory.
+func FindInWorkingDir() (*File, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get working directory: %w", err)
+	}
+
+	return Find(wd)
+}
+
+func Load(path string) (*File, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file %q: %w", path, err)
+	}
+
+	var entries []Entry
+	if filepath.Base(path) == YamlFileName {
+		entries, err = parseYamlFile(data)
+	} else {
+		entries, err = parseVersionFile(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse file %q: %w", path, err)
+	}
+
+	for _, entry := range entries {
+		if err := entry.validate(); err != nil {
+			return nil, fmt.Errorf("invalid file %q: %w", path, err)
+		}
+	}
+
+	return &File{Path: path, Entries: entries}, nil
+}
+
+// GetEntry returns the entry of the repository or the only entry if the repository is not specified.
+func (f *File) GetEntry(optionalRepo string) (Entry, error) {
+	if optionalRepo == "" {
+		if len(f.Entries) != 1 {
+			return Entry{}, fmt.Errorf("file %q contains %d entries: the repository must be specified", f.Path, len(f.Entries))
+		}
+
+		return f.Entries[0], nil
+	}
+
+	entry, ok := f.LookupEntry(optionalRepo)
+	if !ok {
+		return Entry{}, fmt.Errorf("repository %q not found in file %q", optionalRepo, f.Path)
+	}
+
+	return entry, nil
+}
+
+func (f *File) LookupEntry(repo string) (Entry, bool) {
+	for _, entry := range f.Entries {
+		if entry.Repo == repo {
+			return entry, true
+		}
+	}
+
+	return Entry{}, false
+}
+
+func parseYamlFile(data []byte) ([]Entry, error) {
+	var entry Entry
+	if err := yaml.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("error unmarshalling yaml: %w", err)
+	}
+
+	return []Entry{entry}, nil
+}
+
+func parseVersionFile(data []byte) ([]Entry, error) {
+	var entries []Entry
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected \"REPO GROUP CHANNEL|VERSION\", got %q", lineNumber, line)
+		}
+
+		entry := Entry{Repo: fields[0], Group: fields[1]}
+		if strings.Contains(fields[2], ".") {
+			entry.Version = fields[2]
+		} else {
+			entry.Channel = fields[2]
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (e Entry) validate() error {
+	if e.Repo == "" || e.Group == "" {
+		return fmt.Errorf("repository and group must be specified")
+	}
+
+	if e.Repo == trdl.SelfUpdateDefaultRepo {
+		return fmt.Errorf("reserved repository name %q cannot be used", trdl.SelfUpdateDefaultRepo)
+	}
+
+	if e.Channel != "" && e.Version != "" {
+		return fmt.Errorf("repository %q: channel and version cannot be specified together", e.Repo)
+	}
+
+	if e.Channel != "" {
+		if err := trdl.ValidateChannelName(e.Channel); err != nil {
+			return fmt.Errorf("repository %q: %w", e.Repo, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersionFile(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		data     string
+		expected []Entry
+	}{
+		{
+			name:     "channel",
+			data:     "werf 1.2 stable\n",
+			expected: []Entry{{Repo: "werf", Group: "1.2", Channel: "stable"}},
+		},
+		{
+			name:     "version is distinguished by the dot",
+			data:     "werf 1.2 1.2.23\n",
+			expected: []Entry{{Repo: "werf", Group: "1.2", Version: "1.2.23"}},
+		},
+		{
+			name:     "version with the v prefix",
+			data:     "werf 1.2 v1.2.23\n",
+			expected: []Entry{{Repo: "werf", Group: "1.2", Version: "v1.2.23"}},
+		},
+		{
+			name: "several entries with comments and empty lines",
+			data: "# comment\nwerf 1.2 stable\n\n  kubedog 0 0.9.1  \n",
+			expected: []Entry{
+				{Repo: "werf", Group: "1.2", Channel: "stable"},
+				{Repo: "kubedog", Group: "0", Version: "0.9.1"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := parseVersionFile([]byte(tc.data))
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, entries)
+		})
+	}
+}
+
+func TestParseVersionFile_Invalid(t *testing.T) {
+	for _, data := range []string{
+		"werf 1.2\n",
+		"werf 1.2 stable extra\n",
+	} {
+		t.Run(data, func(t *testing.T) {
+			_, err := parseVersionFile([]byte(data))
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestFileGetEntry(t *testing.T) {
+	file := &File{Path: VersionFileName, Entries: []Entry{
+		{Repo: "werf", Group: "1.2", Channel: "stable"},
+		{Repo: "kubedog", Group: "0", Version: "0.9.1"},
+	}}
+
+	entry, err := file.GetEntry("kubedog")
+	assert.Nil(t, err)
+	assert.Equal(t, Entry{Repo: "kubedog", Group: "0", Version: "0.9.1"}, entry)
+	assert.Equal(t, "0.9.1", entry.Release())
+
+	_, err = file.GetEntry("unknown")
+	assert.NotNil(t, err)
+
+	// the repository must be specified if there are several entries
+	_, err = file.GetEntry("")
+	assert.NotNil(t, err)
+}
+
+func TestFind(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		files        map[string]string
+		expectedPath string
+		expected     []Entry
+	}{
+		{
+			name:         "version file",
+			files:        map[string]string{"a/b/" + VersionFileName: "werf 1.2 stable\n"},
+			expectedPath: "a/b/" + VersionFileName,
+			expected:     []Entry{{Repo: "werf", Group: "1.2", Channel: "stable"}},
+		},
+		{
+			name: "yaml file takes precedence over version file",
+			files: map[string]string{
+				"a/b/" + YamlFileName:    "repo: werf\ngroup: \"1.2\"\nversion: 1.2.23\n",
+				"a/b/" + VersionFileName: "werf 1.2 stable\n",
+			},
+			expectedPath: "a/b/" + YamlFileName,
+			expected:     []Entry{{Repo: "werf", Group: "1.2", Version: "1.2.23"}},
+		},
+		{
+			name:         "file in parent directory",
+			files:        map[string]string{VersionFileName: "werf 1.2 stable\n"},
+			expectedPath: VersionFileName,
+			expected:     []Entry{{Repo: "werf", Group: "1.2", Channel: "stable"}},
+		},
+		{
+			name: "nearest file takes precedence over parent directory",
+			files: map[string]string{
+				YamlFileName:           "repo: werf\ngroup: \"1.1\"\nchannel: alpha\n",
+				"a/" + VersionFileName: "werf 1.2 stable\n",
+			},
+			expectedPath: "a/" + VersionFileName,
+			expected:     []Entry{{Repo: "werf", Group: "1.2", Channel: "stable"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			for path, data := range tc.files {
+				path = filepath.Join(root, filepath.FromSlash(path))
+				assert.Nil(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+				assert.Nil(t, os.WriteFile(path, []byte(data), 0o644))
+			}
+
+			workDir := filepath.Join(root, "a", "b")
+			assert.Nil(t, os.MkdirAll(workDir, os.ModePerm))
+
+			file, err := Find(workDir)
+			assert.Nil(t, err)
+			if assert.NotNil(t, file) {
+				assert.Equal(t, filepath.Join(root, filepath.FromSlash(tc.expectedPath)), file.Path)
+				assert.Equal(t, tc.expected, file.Entries)
+			}
+		})
+	}
+}
+
+func TestFind_NotFound(t *testing.T) {
+	file, err := Find(t.TempDir())
+	assert.Nil(t, err)
+	assert.Nil(t, file)
+}
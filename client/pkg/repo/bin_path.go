@@ -1,8 +1,14 @@
 package repo
 
 import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+
 	"github.com/werf/lockgate"
 	"github.com/werf/trdl/client/pkg/trdl"
+	"github.com/werf/trdl/client/pkg/util"
 )
 
 func (c Client) GetChannelReleaseBinDir(group, channel string) (dir string, err error) {
@@ -22,3 +28,44 @@ func (c Client) GetChannelReleaseBinPath(group, channel, optionalBinName string)
 
 	return
 }
+
+// GetNativeLocalBinNames returns the bin names of the local releases of the repository directory suitable for the current platform,
+// the local releases are read without initializing the repository client.
+func GetNativeLocalBinNames(repoDir string) ([]string, error) {
+	c := Client{dir: repoDir, os: runtime.GOOS, arch: runtime.GOARCH, variant: currentArchVariant()}
+	return c.GetLocalBinNames()
+}
+
+// GetLocalBinNames returns the sorted unique names of the bin files of all local releases suitable for the client platform.
+func (c Client) GetLocalBinNames() ([]string, error) {
+	var matches []string
+	for _, osArch := range c.platformOsArchList() {
+		binFilesGlob := filepath.Join(c.dir, releasesDir, "*", osArch, "bin", "*")
+		osArchMatches, err := filepath.Glob(binFilesGlob)
+		if err != nil {
+			return nil, fmt.Errorf("unable to glob files: %w", err)
+		}
+
+		matches = append(matches, osArchMatches...)
+	}
+
+	namesSet := map[string]bool{}
+	for _, path := range matches {
+		exist, err := util.IsRegularFileExist(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to check existence of file %q: %w", path, err)
+		}
+
+		if exist {
+			namesSet[filepath.Base(path)] = true
+		}
+	}
+
+	var names []string
+	for name := range namesSet {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
@@ -0,0 +1,213 @@
+package repo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/werf/trdl/client/pkg/trdl"
+	"github.com/werf/trdl/client/pkg/util"
+)
+
+const (
+	bundlesDir             = "bundles"
+	bundleManifestFileName = "bundle.json"
+)
+
+// BundleManifest describes the channel release packed into the offline bundle.
+// The manifest is not signed: the channel release and the release files are verified by the bundle TUF metadata.
+type BundleManifest struct {
+	Repo      string   `json:"repo"`
+	Group     string   `json:"group"`
+	Channel   string   `json:"channel"`
+	Release   string   `json:"release"`
+	Platforms []string `json:"platforms"`
+}
+
+// ExportChannel writes the offline bundle with the verified TUF metadata and the targets of the channel release for the platforms.
+// The current client platform is used if the platforms are not specified.
+func (c Client) ExportChannel(group, channel string, platforms []string, w io.Writer) error {
+	if len(platforms) == 0 {
+		platforms = []string{c.platformString()}
+	}
+
+	bundleDir, err := c.createBundleTmpDir()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(bundleDir) }()
+
+	recorder, err := c.tufClient.NewBundleRecorder(bundleDir)
+	if err != nil {
+		return fmt.Errorf("unable to init tuf bundle recorder: %w", err)
+	}
+
+	exportClient := c
+	exportClient.tufClient = recorder
+
+	if err := recorder.Update(); err != nil {
+		return err
+	}
+
+	if err := recorder.RecordRootChain(); err != nil {
+		return fmt.Errorf("unable to record root metadata: %w", err)
+	}
+
+	// the channels config is fetched explicitly since it is not downloaded on sync if it is up-to-date locally
+	_, ok, err := recorder.GetTarget(trdl.ChannelsTargetName)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		if err := recorder.Fetch(trdl.ChannelsTargetName); err != nil {
+			return fmt.Errorf("unable to download channels config: %w", err)
+		}
+	}
+
+	if err := exportClient.syncChannelsConfigWithLock(); err != nil {
+		return fmt.Errorf("unable to sync channels config: %w", err)
+	}
+
+	if err := exportClient.validateChannel(group, channel); err != nil {
+		return err
+	}
+
+	channelData, err := exportClient.tufClient.DownloadBytes(c.channelTargetName(group, channel))
+	if err != nil {
+		return fmt.Errorf("unable to download channel %[2]q (group: %[1]q): %[3]w", group, channel, err)
+	}
+	release := strings.TrimSpace(string(channelData))
+
+	for _, platform := range platforms {
+		if err := exportClient.exportReleasePlatform(release, platform); err != nil {
+			return err
+		}
+	}
+
+	manifest := BundleManifest{Repo: c.repoName, Group: group, Channel: channel, Release: release, Platforms: platforms}
+	if err := writeBundleManifest(bundleDir, manifest); err != nil {
+		return err
+	}
+
+	if err := util.WriteTarArchive(bundleDir, w); err != nil {
+		return fmt.Errorf("unable to write bundle: %w", err)
+	}
+
+	return nil
+}
+
+// exportReleasePlatform fetches the release files suitable for the platform along with their signatures.
+func (c Client) exportReleasePlatform(release, platform string) error {
+	osName, arch, err := parsePlatform(platform)
+	if err != nil {
+		return err
+	}
+
+	platformClient := c
+	platformClient.os = osName
+	platformClient.arch, platformClient.variant = parseArch(arch)
+
+	targets, _, err := platformClient.selectAppropriateReleaseTargets(release)
+	if err != nil {
+		return err
+	}
+
+	releaseTargetNamePrefix := c.releaseTargetNamePrefix(release)
+	for targetName := range targets {
+		if err := c.tufClient.Fetch(targetName); err != nil {
+			return fmt.Errorf("unable to download target %q: %w", targetName, err)
+		}
+
+		signatureTargetName := c.releaseFileSignatureTargetName(release, strings.TrimPrefix(targetName, releaseTargetNamePrefix+"/"))
+		_, ok, err := c.tufClient.GetTarget(signatureTargetName)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if err := c.tufClient.Fetch(signatureTargetName); err != nil {
+			return fmt.Errorf("unable to download signature %q: %w", signatureTargetName, err)
+		}
+	}
+
+	return nil
+}
+
+// ImportChannel installs the channel release from the extracted bundle directory.
+// The bundle metadata is verified against the locally trusted TUF metadata the same way as on update.
+func (c Client) ImportChannel(bundleDir, group, channel string) (*ChannelReleaseSwitch, error) {
+	bundleClient, err := c.tufClient.NewBundleClient(bundleDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to init tuf bundle client: %w", err)
+	}
+
+	importClient := c
+	importClient.tufClient = bundleClient
+
+	return importClient.UpdateChannel(group, channel)
+}
+
+func (c Client) createBundleTmpDir() (string, error) {
+	dir := filepath.Join(c.tmpDir, bundlesDir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("unable to mkdir all %q: %w", dir, err)
+	}
+
+	bundleDir, err := ioutil.TempDir(dir, "")
+	if err != nil {
+		return "", fmt.Errorf("unable to create temporary directory: %w", err)
+	}
+
+	return bundleDir, nil
+}
+
+func writeBundleManifest(bundleDir string, manifest BundleManifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("unable to marshal bundle manifest: %w", err)
+	}
+
+	manifestPath := filepath.Join(bundleDir, bundleManifestFileName)
+	if err := ioutil.WriteFile(manifestPath, data, fileModeRegular); err != nil {
+		return fmt.Errorf("unable to write file %q: %w", manifestPath, err)
+	}
+
+	return nil
+}
+
+// ReadBundleManifest reads the manifest of the extracted bundle directory.
+func ReadBundleManifest(bundleDir string) (BundleManifest, error) {
+	var manifest BundleManifest
+
+	manifestPath := filepath.Join(bundleDir, bundleManifestFileName)
+	data, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		return manifest, fmt.Errorf("unable to read bundle manifest: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("unable to unmarshal bundle manifest: %w", err)
+	}
+
+	if manifest.Repo == "" || manifest.Group == "" || manifest.Channel == "" {
+		return manifest, fmt.Errorf("bundle manifest is incomplete: repo, group and channel are required")
+	}
+
+	if strings.ContainsAny(manifest.Group, `/\`) || manifest.Group == "." || manifest.Group == ".." {
+		return manifest, fmt.Errorf("bundle manifest group %q is not valid", manifest.Group)
+	}
+
+	if err := trdl.ValidateChannelName(manifest.Channel); err != nil {
+		return manifest, fmt.Errorf("bundle manifest channel is not valid: %w", err)
+	}
+
+	return manifest, nil
+}
@@ -0,0 +1,79 @@
+package repo
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/werf/trdl/client/pkg/util"
+)
+
+func exportTestBundle(t *testing.T, r *testRepository) string {
+	buf := bytes.NewBuffer(nil)
+	require.Nil(t, r.newClient(ClientOptions{}).ExportChannel("0", "stable", nil, buf))
+
+	bundleDir := t.TempDir()
+	require.Nil(t, util.ExtractTarArchive(buf, bundleDir))
+
+	return bundleDir
+}
+
+func TestExportImportChannel(t *testing.T) {
+	r := newTestRepository(t)
+	r.publishChannelRelease("0", "stable", "0.1.0")
+
+	c := r.newClient(ClientOptions{})
+	bundleDir := exportTestBundle(t, r)
+
+	manifest, err := ReadBundleManifest(bundleDir)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.1.0", manifest.Release)
+
+	// the bundle is imported offline
+	r.server.Close()
+
+	releaseSwitch, err := c.ImportChannel(bundleDir, "0", "stable")
+	assert.Nil(t, err)
+	assert.Equal(t, &ChannelReleaseSwitch{NewRelease: "0.1.0"}, releaseSwitch)
+
+	release, err := c.GetChannelRelease("0", "stable")
+	assert.Nil(t, err)
+	assert.Equal(t, "0.1.0", release)
+	assertReleaseBinData(t, c, "0.1.0", "app 0.1.0")
+}
+
+func TestImportChannel_TamperedTarget(t *testing.T) {
+	r := newTestRepository(t)
+	r.publishChannelRelease("0", "stable", "0.1.0")
+
+	c := r.newClient(ClientOptions{})
+	bundleDir := exportTestBundle(t, r)
+
+	targetPath := filepath.Join(bundleDir, "targets", "releases", "0.1.0", "any-any", "bin", "app")
+	require.Nil(t, os.WriteFile(targetPath, []byte("app 6.6.6"), 0o644))
+
+	// the target of the same size is replaced
+	_, err := c.ImportChannel(bundleDir, "0", "stable")
+	assert.ErrorContains(t, err, "wrong sha512 hash")
+
+	_, err = c.GetChannelRelease("0", "stable")
+	assert.IsType(t, ChannelNotFoundLocallyError{}, err)
+}
+
+func TestImportChannel_UntrustedRoot(t *testing.T) {
+	r := newTestRepository(t)
+	r.publishChannelRelease("0", "stable", "0.1.0")
+	bundleDir := exportTestBundle(t, r)
+
+	// the client trusts the root of another repository
+	other := newTestRepository(t)
+	other.publishChannelRelease("0", "stable", "0.1.0")
+	c := other.newClient(ClientOptions{})
+
+	_, err := c.ImportChannel(bundleDir, "0", "stable")
+	assert.ErrorContains(t, err, "valid signatures did not meet threshold")
+}
@@ -0,0 +1,124 @@
+package repo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/werf/lockgate"
+	"github.com/werf/trdl/client/pkg/trdl"
+	"github.com/werf/trdl/client/pkg/util"
+)
+
+// UpdateChannelsConfig updates the repository metadata and the local copy of the declared channels.
+func (c Client) UpdateChannelsConfig() error {
+	return lockgate.WithAcquire(c.locker, c.updateChannelsConfigLockName(), lockgate.AcquireOptions{Shared: false, Timeout: time.Minute * 5}, func(_ bool) error {
+		if err := c.tufClient.Update(); err != nil {
+			return err
+		}
+
+		return c.syncChannelsConfig()
+	})
+}
+
+func (c Client) syncChannelsConfigWithLock() error {
+	return lockgate.WithAcquire(c.locker, c.updateChannelsConfigLockName(), lockgate.AcquireOptions{Shared: false, Timeout: time.Minute * 5}, func(_ bool) error {
+		return c.syncChannelsConfig()
+	})
+}
+
+// GetChannelsConfig returns the channels declared by the repository at the last update,
+// the default channels are returned if the repository does not declare channels or has never been updated.
+func (c Client) GetChannelsConfig() (trdl.ChannelsConfig, error) {
+	configPath := c.channelsConfigPath()
+	exist, err := util.IsRegularFileExist(configPath)
+	if err != nil {
+		return trdl.ChannelsConfig{}, fmt.Errorf("unable to check existence of file %q: %w", configPath, err)
+	}
+
+	if !exist {
+		return trdl.DefaultChannelsConfig(), nil
+	}
+
+	data, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return trdl.ChannelsConfig{}, fmt.Errorf("unable to read file %q: %w", configPath, err)
+	}
+
+	var config trdl.ChannelsConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return trdl.ChannelsConfig{}, fmt.Errorf("unable to unmarshal file %q: %w", configPath, err)
+	}
+
+	if len(config.AllowedChannels) == 0 {
+		return trdl.DefaultChannelsConfig(), nil
+	}
+
+	return config, nil
+}
+
+func (c Client) validateChannel(group, channel string) error {
+	config, err := c.GetChannelsConfig()
+	if err != nil {
+		return err
+	}
+
+	return config.ValidateGroupChannel(group, channel)
+}
+
+// syncChannelsConfig must be called after the repository metadata update.
+func (c Client) syncChannelsConfig() error {
+	configPath := c.channelsConfigPath()
+
+	targetMeta, ok, err := c.tufClient.GetTarget(trdl.ChannelsTargetName)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		if err := os.RemoveAll(configPath); err != nil {
+			return fmt.Errorf("unable to remove %q: %w", configPath, err)
+		}
+
+		return nil
+	}
+
+	upToDate, err := isLocalFileUpToDate(configPath, targetMeta)
+	if err != nil {
+		return fmt.Errorf("unable to compare the file %q to the target: %w", configPath, err)
+	}
+
+	if upToDate {
+		return nil
+	}
+
+	configTmpPath := c.channelsConfigTmpPath()
+	if err := os.RemoveAll(configTmpPath); err != nil {
+		return fmt.Errorf("unable to remove %q: %w", configTmpPath, err)
+	}
+
+	if err := c.syncFile(trdl.ChannelsTargetName, targetMeta, configTmpPath, fileModeRegular); err != nil {
+		return err
+	}
+
+	if err := os.Rename(configTmpPath, configPath); err != nil {
+		return fmt.Errorf("unable to rename %q to %q: %w", configTmpPath, configPath, err)
+	}
+
+	return nil
+}
+
+func (c Client) channelsConfigPath() string {
+	return filepath.Join(c.dir, trdl.ChannelsTargetName)
+}
+
+func (c Client) channelsConfigTmpPath() string {
+	return filepath.Join(c.tmpDir, trdl.ChannelsTargetName)
+}
+
+func (c Client) updateChannelsConfigLockName() string {
+	return "update-channels-config"
+}
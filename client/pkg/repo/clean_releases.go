@@ -17,6 +17,11 @@ func (c Client) CleanReleases() error {
 		return fmt.Errorf("unable to get actual local releases: %w", err)
 	}
 
+	channelsStateReleases, err := c.getChannelsStateReleases()
+	if err != nil {
+		return fmt.Errorf("unable to get channels state releases: %w", err)
+	}
+
 	allReleasesGlob := filepath.Join(c.dir, releasesDir, "*")
 	releaseDirList, err := filepath.Glob(allReleasesGlob)
 	if err != nil {
@@ -31,6 +36,11 @@ func (c Client) CleanReleases() error {
 			continue
 		}
 
+		// skip previous or held channel release
+		if channelsStateReleases[releaseName] {
+			continue
+		}
+
 		// skip recently used release
 		{
 			metafile := c.releaseMetafile(releaseName)
@@ -0,0 +1,51 @@
+package repo
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/werf/trdl/client/pkg/util"
+)
+
+func TestCleanReleases_PlatformOverridden(t *testing.T) {
+	defer func(period time.Duration) { releaseMetafileExpirationPeriod = period }(releaseMetafileExpirationPeriod)
+	releaseMetafileExpirationPeriod = 0
+
+	r := newTestRepository(t)
+	r.publishChannelRelease("0", "stable", "0.0.1")
+
+	native := r.newClient(ClientOptions{})
+	foreign := r.newClientInDir(filepath.Dir(native.dir), ClientOptions{OS: "foreignos", Arch: "arm/v7"})
+
+	// the native channel: 0.0.1 -> 0.1.0 (previous) -> 0.2.0
+	updateTestChannel(t, native)
+	for _, release := range []string{"0.1.0", "0.2.0"} {
+		r.publishChannelRelease("0", "stable", release)
+		updateTestChannel(t, native)
+	}
+
+	// the foreign platform channel: 0.3.0 (previous) -> 0.4.0
+	for _, release := range []string{"0.3.0", "0.4.0"} {
+		r.publishChannelRelease("0", "stable", release)
+		updateTestChannel(t, foreign)
+	}
+
+	for _, c := range []Client{foreign, native} {
+		require.Nil(t, c.CleanReleases())
+
+		for release, expected := range map[string]bool{"0.0.1": false, "0.1.0": true, "0.2.0": true, "0.3.0": true, "0.4.0": true} {
+			exist, err := util.IsDirExist(c.channelReleaseDir(release))
+			assert.Nil(t, err)
+			assert.Equal(t, expected, exist, release)
+		}
+	}
+}
+
+func updateTestChannel(t *testing.T, c Client) {
+	_, err := c.UpdateChannel("0", "stable")
+	require.Nil(t, err)
+}
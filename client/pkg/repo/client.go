@@ -6,8 +6,11 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"strings"
 
+	"golang.org/x/crypto/openpgp"
+
 	"github.com/werf/lockgate"
 	"github.com/werf/lockgate/pkg/file_locker"
 	"github.com/werf/trdl/client/pkg/tuf"
@@ -15,8 +18,9 @@ import (
 )
 
 const (
-	targetsChannels = "channels"
-	targetsReleases = "releases"
+	targetsChannels   = "channels"
+	targetsReleases   = "releases"
+	targetsSignatures = "signatures"
 
 	channelsDir = targetsChannels
 	releasesDir = targetsReleases
@@ -31,30 +35,59 @@ type Client struct {
 	metafileDir string
 	tufClient   TufInterface
 	locker      lockgate.Locker
+	pgpKeyring  openpgp.EntityList
+	os          string
+	arch        string
+	variant     string
+}
+
+type ClientOptions struct {
+	PGPPublicKey string
+	// OS and Arch of the releases, the current platform is used by default.
+	// The Arch may contain the variant, e.g. arm/v7
+	OS   string
+	Arch string
+	// RemoteOptions are the connection settings of the TUF repository
+	RemoteOptions tuf.RemoteOptions
 }
 
-func NewClient(repoName, dir, repoUrl, locksPath, tmpDir, logsDir, metafileDir string) (Client, error) {
+func NewClient(repoName, dir, repoUrl, locksPath, tmpDir, logsDir, metafileDir string, opts ClientOptions) (Client, error) {
 	c := Client{
 		repoName:    repoName,
 		dir:         dir,
 		tmpDir:      tmpDir,
 		logsDir:     logsDir,
 		metafileDir: metafileDir,
+		os:          opts.OS,
+	}
+
+	if c.os == "" {
+		c.os = runtime.GOOS
+	}
+
+	if opts.Arch == "" {
+		c.arch, c.variant = runtime.GOARCH, currentArchVariant()
+	} else {
+		c.arch, c.variant = parseArch(opts.Arch)
 	}
 
-	if err := c.init(repoUrl, locksPath); err != nil {
+	if err := c.init(repoUrl, locksPath, opts); err != nil {
 		return c, err
 	}
 
 	return c, nil
 }
 
-func (c *Client) init(repoUrl, locksPath string) error {
+func (c *Client) init(repoUrl, locksPath string, opts ClientOptions) error {
 	if err := c.initFileLocker(locksPath); err != nil {
 		return fmt.Errorf("unable to init file locker: %w", err)
 	}
 
-	if err := c.initTufClient(repoUrl, locksPath); err != nil {
+	if err := c.initPGPKeyring(opts.PGPPublicKey); err != nil {
+		return fmt.Errorf("unable to init pgp keyring: %w", err)
+	}
+
+	if err := c.initTufClient(repoUrl, locksPath, opts.RemoteOptions); err != nil {
 		return fmt.Errorf("unable to init tuf client: %w", err)
 	}
 
@@ -65,8 +98,8 @@ func (c *Client) init(repoUrl, locksPath string) error {
 	return nil
 }
 
-func (c *Client) initTufClient(repoUrl, locksPath string) (err error) {
-	tufClient, err := tuf.NewClient(repoUrl, c.metaLocalStoreDir(), filepath.Join(locksPath, "tuf"))
+func (c *Client) initTufClient(repoUrl, locksPath string, remoteOpts tuf.RemoteOptions) (err error) {
+	tufClient, err := tuf.NewClient(repoUrl, c.metaLocalStoreDir(), filepath.Join(locksPath, "tuf"), remoteOpts)
 	if err != nil {
 		return err
 	}
@@ -76,6 +109,21 @@ func (c *Client) initTufClient(repoUrl, locksPath string) (err error) {
 	return nil
 }
 
+func (c *Client) initPGPKeyring(pgpPublicKey string) error {
+	if pgpPublicKey == "" {
+		return nil
+	}
+
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(pgpPublicKey))
+	if err != nil {
+		return fmt.Errorf("unable to read armored pgp public key: %w", err)
+	}
+
+	c.pgpKeyring = keyring
+
+	return nil
+}
+
 func (c *Client) initFileLocker(locksPath string) error {
 	locker, err := file_locker.NewFileLocker(locksPath)
 	if err != nil {
@@ -95,8 +143,12 @@ func (c Client) releaseTargetNamePrefix(release string) string {
 	return path.Join(targetsReleases, release)
 }
 
+func (c Client) releaseFileSignatureTargetName(release, releaseFileRelPath string) string {
+	return path.Join(targetsSignatures, release, releaseFileRelPath+".sig")
+}
+
 func (c Client) channelPath(group, channel string) string {
-	return filepath.Join(c.dir, channelsDir, group, channel)
+	return filepath.Join(c.dir, channelsDir, group, c.channelFileName(channel))
 }
 
 func (c Client) channelReleaseDir(releaseName string) string {
@@ -104,19 +156,23 @@ func (c Client) channelReleaseDir(releaseName string) string {
 }
 
 func (c Client) channelScriptsDir(group, channel string) string {
-	return filepath.Join(c.dir, scriptsDir, strings.Join([]string{group, channel}, "-"))
+	return filepath.Join(c.dir, scriptsDir, strings.Join([]string{group, c.channelFileName(channel)}, "-"))
 }
 
 func (c Client) channelTmpPath(group, channel string) string {
-	return filepath.Join(c.tmpDir, channelsDir, group, channel)
+	return filepath.Join(c.tmpDir, channelsDir, group, c.channelFileName(channel))
 }
 
 func (c Client) channelReleaseTmpDir(releaseName string) string {
 	return filepath.Join(c.tmpDir, releasesDir, releaseName)
 }
 
+func (c Client) releaseSignaturesTmpDir(releaseName string) string {
+	return filepath.Join(c.tmpDir, targetsSignatures, releaseName)
+}
+
 func (c Client) channelScriptsTmpDir(group, channel string) string {
-	return filepath.Join(c.tmpDir, scriptsDir, strings.Join([]string{group, channel}, "-"))
+	return filepath.Join(c.tmpDir, scriptsDir, strings.Join([]string{group, c.channelFileName(channel)}, "-"))
 }
 
 func (c Client) findChannelReleaseBinPath(group, channel, optionalBinName string) (string, error) {
@@ -125,6 +181,20 @@ func (c Client) findChannelReleaseBinPath(group, channel, optionalBinName string
 		return "", err
 	}
 
+	matches, err := globBinFiles(dir, optionalBinName)
+	if err != nil {
+		return "", err
+	}
+
+	if len(matches) > 1 {
+		return "", NewChannelReleaseSeveralFilesFoundError(c.repoName, group, channel, releaseName, binFileNames(dir, matches))
+	}
+
+	return matches[0], nil
+}
+
+// globBinFiles returns at least one bin file path.
+func globBinFiles(dir, optionalBinName string) ([]string, error) {
 	var glob string
 	if optionalBinName == "" {
 		glob = filepath.Join(dir, "*")
@@ -134,25 +204,27 @@ func (c Client) findChannelReleaseBinPath(group, channel, optionalBinName string
 
 	matches, err := filepath.Glob(glob)
 	if err != nil {
-		return "", fmt.Errorf("unable to glob files: %w", err)
+		return nil, fmt.Errorf("unable to glob files: %w", err)
 	}
 
-	if len(matches) > 1 {
-		var names []string
-		for _, m := range matches {
-			names = append(names, strings.TrimPrefix(m, dir+string(os.PathSeparator)))
-		}
-
-		return "", NewChannelReleaseSeveralFilesFoundError(c.repoName, group, channel, releaseName, names)
-	} else if len(matches) == 0 {
+	if len(matches) == 0 {
 		if optionalBinName == "" {
-			return "", fmt.Errorf("binary file not found in release")
+			return nil, fmt.Errorf("binary file not found in release")
 		} else {
-			return "", fmt.Errorf("binary file %q not found in release", optionalBinName)
+			return nil, fmt.Errorf("binary file %q not found in release", optionalBinName)
 		}
 	}
 
-	return matches[0], nil
+	return matches, nil
+}
+
+func binFileNames(dir string, matches []string) []string {
+	var names []string
+	for _, m := range matches {
+		names = append(names, strings.TrimPrefix(m, dir+string(os.PathSeparator)))
+	}
+
+	return names
 }
 
 func (c Client) findChannelReleaseBinDir(group, channel string) (dir, release string, err error) {
@@ -161,10 +233,9 @@ func (c Client) findChannelReleaseBinDir(group, channel string) (dir, release st
 		return "", "", err
 	}
 
-	binDir := filepath.Join(releaseDir, "bin")
-	exist, err := util.IsDirExist(binDir)
+	binDir, exist, err := findReleaseBinDir(releaseDir)
 	if err != nil {
-		return "", "", fmt.Errorf("unable to check existence of directory %q: %w", binDir, err)
+		return "", "", err
 	}
 
 	if !exist {
@@ -174,26 +245,50 @@ func (c Client) findChannelReleaseBinDir(group, channel string) (dir, release st
 	return binDir, releaseName, nil
 }
 
+func findReleaseBinDir(releaseDir string) (string, bool, error) {
+	binDir := filepath.Join(releaseDir, "bin")
+	exist, err := util.IsDirExist(binDir)
+	if err != nil {
+		return "", false, fmt.Errorf("unable to check existence of directory %q: %w", binDir, err)
+	}
+
+	return binDir, exist, nil
+}
+
 func (c Client) findChannelReleaseDir(group, channel string) (dir, release string, err error) {
 	release, err = c.GetChannelRelease(group, channel)
 	if err != nil {
 		return "", "", err
 	}
 
-	dirGlob := filepath.Join(c.dir, releasesDir, release, "*")
-
-	matches, err := filepath.Glob(dirGlob)
+	dir, exist, err := c.findReleaseDir(release)
 	if err != nil {
-		return "", "", fmt.Errorf("unable to glob files: %w", err)
+		return "", "", err
 	}
 
-	if len(matches) > 1 {
-		return "", "", fmt.Errorf("unexpected files in release directory:\n - %s", strings.Join(matches, "\n - "))
-	} else if len(matches) == 0 {
+	if !exist {
 		return "", "", NewChannelReleaseNotFoundLocallyError(c.repoName, group, channel, release)
 	}
 
-	return matches[0], release, nil
+	return dir, release, nil
+}
+
+// findReleaseDir returns the <os>-<arch> directory of the local release suitable for the client platform.
+// The releases for the different platforms are kept side by side in the release directory.
+func (c Client) findReleaseDir(release string) (string, bool, error) {
+	for _, osArch := range c.platformOsArchList() {
+		dir := filepath.Join(c.channelReleaseDir(release), osArch)
+		exist, err := util.IsDirExist(dir)
+		if err != nil {
+			return "", false, fmt.Errorf("unable to check existence of directory %q: %w", dir, err)
+		}
+
+		if exist {
+			return dir, true, nil
+		}
+	}
+
+	return "", false, nil
 }
 
 func (c Client) GetChannelRelease(group, channel string) (string, error) {
@@ -234,11 +329,11 @@ func (c Client) metaLocalStoreDir() string {
 }
 
 func (c Client) channelLockName(group, channel string) string {
-	return fmt.Sprintf("%s-%s", group, channel)
+	return fmt.Sprintf("%s-%s", group, c.channelFileName(channel))
 }
 
 func (c Client) updateChannelLockName(group, channel string) string {
-	return fmt.Sprintf("update-channel-%s-%s", group, channel)
+	return fmt.Sprintf("update-channel-%s-%s", group, c.channelFileName(channel))
 }
 
 func (c Client) updateReleaseLockName(release string) string {
@@ -61,3 +61,53 @@ func NewChannelReleaseSeveralFilesFoundError(repoName, group, channel, release s
 func (e ChannelReleaseBinSeveralFilesFoundError) Error() string {
 	return fmt.Sprintf("several binary files found in release %q (group: %q, channel: %q)", e.Release, e.Group, e.Channel)
 }
+
+type ReleaseNotFoundLocallyError struct {
+	RepoName string
+	Release  string
+}
+
+func NewReleaseNotFoundLocallyError(repoName, release string) error {
+	return ReleaseNotFoundLocallyError{
+		RepoName: repoName,
+		Release:  release,
+	}
+}
+
+func (e ReleaseNotFoundLocallyError) Error() string {
+	return fmt.Sprintf("release %q not found locally", e.Release)
+}
+
+type ReleaseBinSeveralFilesFoundError struct {
+	RepoName string
+	Release  string
+	Names    []string
+}
+
+func NewReleaseBinSeveralFilesFoundError(repoName, release string, names []string) error {
+	return ReleaseBinSeveralFilesFoundError{
+		RepoName: repoName,
+		Release:  release,
+		Names:    names,
+	}
+}
+
+func (e ReleaseBinSeveralFilesFoundError) Error() string {
+	return fmt.Sprintf("several binary files found in release %q", e.Release)
+}
+
+type ReleaseNotFoundForPlatformError struct {
+	Release  string
+	Platform string
+}
+
+func NewReleaseNotFoundForPlatformError(release, platform string) error {
+	return ReleaseNotFoundForPlatformError{
+		Release:  release,
+		Platform: platform,
+	}
+}
+
+func (e ReleaseNotFoundForPlatformError) Error() string {
+	return fmt.Sprintf("channel release %q not found in the repository (platform: %q)", e.Release, e.Platform)
+}
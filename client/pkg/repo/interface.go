@@ -4,11 +4,18 @@ import (
 	"os"
 
 	"github.com/theupdateframework/go-tuf/data"
+
+	"github.com/werf/trdl/client/pkg/tuf"
 )
 
 type TufInterface interface {
 	Setup(rootVersion int64, rootSha512 string) error
 	Update() error
 	DownloadFile(targetName, dest string, destMode os.FileMode) error
+	DownloadBytes(targetName string) ([]byte, error)
+	GetTarget(targetName string) (data.TargetFileMeta, bool, error)
 	GetTargets() (data.TargetFiles, error)
+	Fetch(targetName string) error
+	NewBundleRecorder(bundleDir string) (*tuf.Client, error)
+	NewBundleClient(bundleDir string) (*tuf.Client, error)
 }
@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// platformOsArchList returns the <os>-<arch>[-<variant>] of the release targets suitable for the client platform in order of preference.
+func (c Client) platformOsArchList() []string {
+	archList := c.platformArchList()
+
+	var result []string
+	for _, osName := range []string{c.os, "any"} {
+		for _, arch := range archList {
+			result = append(result, fmt.Sprintf("%s-%s", osName, arch))
+		}
+	}
+
+	return result
+}
+
+// platformArchList returns the compatible arch variants from the newest to the generic arch and "any",
+// e.g. arm-v7, arm-v6, arm-v5, arm, any.
+func (c Client) platformArchList() []string {
+	var result []string
+
+	if c.variant != "" {
+		result = append(result, fmt.Sprintf("%s-%s", c.arch, c.variant))
+
+		// the arm binaries of the older versions are compatible
+		if version, err := strconv.Atoi(strings.TrimPrefix(c.variant, "v")); c.arch == "arm" && err == nil {
+			for v := version - 1; v >= 5; v-- {
+				result = append(result, fmt.Sprintf("%s-v%d", c.arch, v))
+			}
+		}
+	}
+
+	return append(result, c.arch, "any")
+}
+
+// isNativePlatform reports whether the client platform is the current one and not overridden by the options.
+func (c Client) isNativePlatform() bool {
+	return c.os == runtime.GOOS && c.arch == runtime.GOARCH && c.variant == currentArchVariant()
+}
+
+// channelFileName returns the name of the local channel files, the channel of the overridden platform is kept separately
+// in format <channel>@<os>-<arch>[-<variant>] so that the update for another platform does not switch the native channel.
+func (c Client) channelFileName(channel string) string {
+	if c.isNativePlatform() {
+		return channel
+	}
+
+	return fmt.Sprintf("%s@%s-%s", channel, c.os, c.platformArchList()[0])
+}
+
+func (c Client) platformString() string {
+	if c.variant == "" {
+		return fmt.Sprintf("%s/%s", c.os, c.arch)
+	}
+
+	return fmt.Sprintf("%s/%s/%s", c.os, c.arch, c.variant)
+}
+
+// parseArch splits the arch in format <arch>[/<variant>].
+func parseArch(arch string) (string, string) {
+	parts := strings.SplitN(arch, "/", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+
+	return parts[0], parts[1]
+}
+
+// currentArchVariant returns the arm version the client is built for.
+func currentArchVariant() string {
+	if runtime.GOARCH != "arm" {
+		return ""
+	}
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	for _, setting := range buildInfo.Settings {
+		if setting.Key == "GOARM" && setting.Value != "" {
+			return "v" + setting.Value
+		}
+	}
+
+	return ""
+}
+
+// parsePlatform splits the platform in format <os>/<arch>[/<variant>] into the os and the arch with the optional variant.
+func parsePlatform(platform string) (string, string, error) {
+	parts := strings.SplitN(platform, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.Count(parts[1], "/") > 1 {
+		return "", "", fmt.Errorf("expected platform in format <os>/<arch>[/<variant>], got %q", platform)
+	}
+
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelFileName(t *testing.T) {
+	native := Client{os: runtime.GOOS, arch: runtime.GOARCH, variant: currentArchVariant()}
+	assert.Equal(t, "stable", native.channelFileName("stable"))
+
+	foreign := Client{os: "foreignos", arch: "arm", variant: "v7"}
+	assert.Equal(t, "stable@foreignos-arm-v7", foreign.channelFileName("stable"))
+
+	foreign = Client{os: "foreignos", arch: "amd64"}
+	assert.Equal(t, "stable@foreignos-amd64", foreign.channelFileName("stable"))
+}
+
+func TestPlatformOsArchList(t *testing.T) {
+	c := Client{os: "darwin", arch: "amd64"}
+	assert.Equal(t, []string{"darwin-amd64", "darwin-any", "any-amd64", "any-any"}, c.platformOsArchList())
+}
+
+func TestPlatformArchList(t *testing.T) {
+	for _, tc := range []struct {
+		arch     string
+		variant  string
+		expected []string
+	}{
+		{arch: "amd64", expected: []string{"amd64", "any"}},
+		{arch: "arm", variant: "v7", expected: []string{"arm-v7", "arm-v6", "arm-v5", "arm", "any"}},
+		{arch: "arm", variant: "v5", expected: []string{"arm-v5", "arm", "any"}},
+		{arch: "arm64", variant: "v8", expected: []string{"arm64-v8", "arm64", "any"}},
+	} {
+		t.Run(tc.arch+"/"+tc.variant, func(t *testing.T) {
+			c := Client{arch: tc.arch, variant: tc.variant}
+			assert.Equal(t, tc.expected, c.platformArchList())
+		})
+	}
+}
+
+func TestPlatformOsArchList_Variant(t *testing.T) {
+	c := Client{os: "linux", arch: "arm", variant: "v6"}
+	assert.Equal(t, []string{
+		"linux-arm-v6", "linux-arm-v5", "linux-arm", "linux-any",
+		"any-arm-v6", "any-arm-v5", "any-arm", "any-any",
+	}, c.platformOsArchList())
+}
+
+func TestParseArch(t *testing.T) {
+	arch, variant := parseArch("arm/v7")
+	assert.Equal(t, "arm", arch)
+	assert.Equal(t, "v7", variant)
+
+	arch, variant = parseArch("amd64")
+	assert.Equal(t, "amd64", arch)
+	assert.Equal(t, "", variant)
+}
+
+func TestParsePlatform(t *testing.T) {
+	for _, tc := range []struct {
+		platform     string
+		expectedOS   string
+		expectedArch string
+	}{
+		{platform: "linux/amd64", expectedOS: "linux", expectedArch: "amd64"},
+		{platform: "linux/arm/v7", expectedOS: "linux", expectedArch: "arm/v7"},
+	} {
+		t.Run(tc.platform, func(t *testing.T) {
+			os, arch, err := parsePlatform(tc.platform)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedOS, os)
+			assert.Equal(t, tc.expectedArch, arch)
+		})
+	}
+}
+
+func TestParsePlatform_Invalid(t *testing.T) {
+	for _, platform := range []string{
+		"linux",
+		"linux/",
+		"/amd64",
+		"linux/arm/v7/extra",
+	} {
+		t.Run(platform, func(t *testing.T) {
+			_, _, err := parsePlatform(platform)
+			assert.NotNil(t, err)
+		})
+	}
+}
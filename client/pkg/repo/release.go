@@ -0,0 +1,86 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/werf/lockgate"
+	"github.com/werf/trdl/client/pkg/util"
+)
+
+// UpdateRelease downloads the exact release regardless of the channels.
+func (c Client) UpdateRelease(release string) error {
+	return lockgate.WithAcquire(c.locker, c.updateReleaseLockName(release), lockgate.AcquireOptions{Shared: false, Timeout: time.Minute * 5}, func(_ bool) error {
+		if err := c.tufClient.Update(); err != nil {
+			return err
+		}
+
+		if err := c.syncChannelRelease(release); err != nil {
+			return err
+		}
+
+		return c.releaseMetafile(release).Reset(c.locker)
+	})
+}
+
+func (c Client) GetReleaseDir(release string) (string, error) {
+	return c.findReleaseDirLocally(release)
+}
+
+func (c Client) GetReleaseBinDir(release string) (string, error) {
+	return c.findReleaseBinDirLocally(release)
+}
+
+func (c Client) ExecReleaseBin(release, optionalBinName string, args []string) error {
+	binDir, err := c.findReleaseBinDirLocally(release)
+	if err != nil {
+		return err
+	}
+
+	matches, err := globBinFiles(binDir, optionalBinName)
+	if err != nil {
+		return err
+	}
+
+	if len(matches) > 1 {
+		return NewReleaseBinSeveralFilesFoundError(c.repoName, release, binFileNames(binDir, matches))
+	}
+
+	return util.Exec(matches[0], args)
+}
+
+func (c Client) findReleaseDirLocally(release string) (string, error) {
+	dir, exist, err := c.findReleaseDir(release)
+	if err != nil {
+		return "", err
+	}
+
+	if !exist {
+		return "", NewReleaseNotFoundLocallyError(c.repoName, release)
+	}
+
+	// keep the used release from cleaning
+	if err := c.releaseMetafile(release).Reset(c.locker); err != nil {
+		return "", fmt.Errorf("unable to reset release metafile: %w", err)
+	}
+
+	return dir, nil
+}
+
+func (c Client) findReleaseBinDirLocally(release string) (string, error) {
+	releaseDir, err := c.findReleaseDirLocally(release)
+	if err != nil {
+		return "", err
+	}
+
+	binDir, exist, err := findReleaseBinDir(releaseDir)
+	if err != nil {
+		return "", err
+	}
+
+	if !exist {
+		return "", fmt.Errorf("bin directory not found in the release %q directory", release)
+	}
+
+	return binDir, nil
+}
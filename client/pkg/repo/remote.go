@@ -0,0 +1,197 @@
+package repo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/werf/lockgate"
+	"github.com/werf/trdl/client/pkg/trdl"
+)
+
+// RemoteChannel is the channel published in the repository.
+type RemoteChannel struct {
+	Group   string `json:"group"`
+	Channel string `json:"channel"`
+	Release string `json:"release"`
+	// Platform is the os and arch of the release files suitable for the current platform
+	Platform string `json:"platform,omitempty"`
+	// Files of the release for the current platform
+	Files []string `json:"files"`
+}
+
+// GetRemoteChannels updates the repository metadata and returns the published channels of all groups or of the specified group.
+func (c Client) GetRemoteChannels(optionalGroup string) ([]*RemoteChannel, error) {
+	var remoteChannels []*RemoteChannel
+	if err := lockgate.WithAcquire(c.locker, c.updateChannelsConfigLockName(), lockgate.AcquireOptions{Shared: false, Timeout: time.Minute * 5}, func(_ bool) error {
+		if err := c.tufClient.Update(); err != nil {
+			return err
+		}
+
+		if err := c.syncChannelsConfig(); err != nil {
+			return fmt.Errorf("unable to sync channels config: %w", err)
+		}
+
+		var err error
+		remoteChannels, err = c.getRemoteChannels(optionalGroup)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return remoteChannels, nil
+}
+
+// GetRemoteReleasesIndex updates the repository metadata and returns the index of the published releases,
+// the empty index is returned if the repository does not maintain the index.
+func (c Client) GetRemoteReleasesIndex() (trdl.ReleasesIndex, error) {
+	index := trdl.ReleasesIndex{Releases: map[string]*trdl.ReleasesIndexEntry{}}
+	if err := c.downloadRemoteJSON(trdl.ReleasesIndexTargetName, &index); err != nil {
+		return trdl.ReleasesIndex{}, err
+	}
+
+	return index, nil
+}
+
+// GetRemoteChannelsHistory updates the repository metadata and returns the history of the channels,
+// the empty history is returned if the repository does not maintain the history.
+func (c Client) GetRemoteChannelsHistory() (trdl.ChannelsHistory, error) {
+	history := trdl.ChannelsHistory{Groups: map[string]map[string][]*trdl.ChannelsHistoryEntry{}}
+	if err := c.downloadRemoteJSON(trdl.ChannelsHistoryTargetName, &history); err != nil {
+		return trdl.ChannelsHistory{}, err
+	}
+
+	return history, nil
+}
+
+// downloadRemoteJSON updates the repository metadata and decodes the target if it is published.
+func (c Client) downloadRemoteJSON(targetName string, v interface{}) error {
+	return lockgate.WithAcquire(c.locker, c.updateChannelsConfigLockName(), lockgate.AcquireOptions{Shared: false, Timeout: time.Minute * 5}, func(_ bool) error {
+		if err := c.tufClient.Update(); err != nil {
+			return err
+		}
+
+		_, ok, err := c.tufClient.GetTarget(targetName)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return nil
+		}
+
+		data, err := c.tufClient.DownloadBytes(targetName)
+		if err != nil {
+			return fmt.Errorf("unable to download target %q: %w", targetName, err)
+		}
+
+		if err := json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("unable to unmarshal target %q: %w", targetName, err)
+		}
+
+		return nil
+	})
+}
+
+func (c Client) getRemoteChannels(optionalGroup string) ([]*RemoteChannel, error) {
+	channelTargetNamePrefix := targetsChannels + "/"
+	if optionalGroup != "" {
+		channelTargetNamePrefix = path.Join(targetsChannels, optionalGroup) + "/"
+	}
+
+	targets, err := c.filterTargets(channelTargetNamePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var remoteChannels []*RemoteChannel
+	for targetName := range targets {
+		parts := strings.Split(strings.TrimPrefix(targetName, targetsChannels+"/"), "/")
+		if len(parts) != 2 {
+			continue
+		}
+
+		data, err := c.tufClient.DownloadBytes(targetName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to download target %q: %w", targetName, err)
+		}
+
+		remoteChannel := &RemoteChannel{
+			Group:   parts[0],
+			Channel: parts[1],
+			Release: strings.TrimSpace(string(data)),
+			Files:   []string{},
+		}
+
+		if err := c.fillRemoteChannelFiles(remoteChannel); err != nil {
+			return nil, err
+		}
+
+		remoteChannels = append(remoteChannels, remoteChannel)
+	}
+
+	if err := c.sortRemoteChannels(remoteChannels); err != nil {
+		return nil, err
+	}
+
+	return remoteChannels, nil
+}
+
+func (c Client) fillRemoteChannelFiles(remoteChannel *RemoteChannel) error {
+	releaseTargets, osArch, err := c.selectAppropriateReleaseTargets(remoteChannel.Release)
+	if err != nil {
+		// the release is not available for the current platform
+		if errors.As(err, &ReleaseNotFoundForPlatformError{}) {
+			return nil
+		}
+
+		return fmt.Errorf("unable to select release %q targets: %w", remoteChannel.Release, err)
+	}
+
+	releaseTargetNamePrefixWithOSArch := path.Join(c.releaseTargetNamePrefix(remoteChannel.Release), osArch) + "/"
+	for targetName := range releaseTargets {
+		remoteChannel.Files = append(remoteChannel.Files, strings.TrimPrefix(targetName, releaseTargetNamePrefixWithOSArch))
+	}
+
+	sort.Strings(remoteChannel.Files)
+	remoteChannel.Platform = osArch
+
+	return nil
+}
+
+// sortRemoteChannels sorts by group and then in order of the channels declared by the repository.
+func (c Client) sortRemoteChannels(remoteChannels []*RemoteChannel) error {
+	channelsConfig, err := c.GetChannelsConfig()
+	if err != nil {
+		return err
+	}
+
+	channelIndex := func(channel string) int {
+		for ind, allowedChannel := range channelsConfig.AllowedChannels {
+			if allowedChannel == channel {
+				return ind
+			}
+		}
+
+		return len(channelsConfig.AllowedChannels)
+	}
+
+	sort.Slice(remoteChannels, func(i, j int) bool {
+		if remoteChannels[i].Group != remoteChannels[j].Group {
+			return remoteChannels[i].Group < remoteChannels[j].Group
+		}
+
+		iInd, jInd := channelIndex(remoteChannels[i].Channel), channelIndex(remoteChannels[j].Channel)
+		if iInd != jInd {
+			return iInd < jInd
+		}
+
+		return remoteChannels[i].Channel < remoteChannels[j].Channel
+	})
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package repo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tufRepo "github.com/theupdateframework/go-tuf"
+
+	"github.com/werf/trdl/client/pkg/util"
+)
+
+// testRepository is the TUF repository served over http to test the client against.
+type testRepository struct {
+	t      *testing.T
+	dir    string
+	repo   *tufRepo.Repo
+	server *httptest.Server
+}
+
+func newTestRepository(t *testing.T) *testRepository {
+	dir := t.TempDir()
+
+	repo, err := tufRepo.NewRepo(tufRepo.FileSystemStore(dir, nil))
+	require.Nil(t, err)
+	require.Nil(t, repo.Init(false))
+
+	for _, role := range []string{"root", "targets", "snapshot", "timestamp"} {
+		_, err := repo.GenKey(role)
+		require.Nil(t, err)
+	}
+
+	server := httptest.NewServer(http.FileServer(http.Dir(filepath.Join(dir, "repository"))))
+	t.Cleanup(server.Close)
+
+	return &testRepository{t: t, dir: dir, repo: repo, server: server}
+}
+
+// publish adds the targets with the data and commits the repository.
+func (r *testRepository) publish(targets map[string]string) {
+	var names []string
+	for name, data := range targets {
+		path := filepath.Join(r.dir, "staged", "targets", filepath.FromSlash(name))
+		require.Nil(r.t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.Nil(r.t, os.WriteFile(path, []byte(data), 0o644))
+		names = append(names, name)
+	}
+
+	require.Nil(r.t, r.repo.AddTargets(names, nil))
+	require.Nil(r.t, r.repo.Snapshot())
+	require.Nil(r.t, r.repo.Timestamp())
+	require.Nil(r.t, r.repo.Commit())
+}
+
+// publishChannelRelease publishes the release with the single bin file for any platform and switches the channel to it.
+func (r *testRepository) publishChannelRelease(group, channel, release string) {
+	r.publish(map[string]string{
+		"releases/" + release + "/any-any/bin/app": "app " + release,
+		"channels/" + group + "/" + channel:        release + "\n",
+	})
+}
+
+// newClient returns the client of the new local repository directory trusting the current root.
+func (r *testRepository) newClient(opts ClientOptions) Client {
+	rootData, err := os.ReadFile(filepath.Join(r.dir, "repository", "root.json"))
+	require.Nil(r.t, err)
+
+	c := r.newClientInDir(r.t.TempDir(), opts)
+	require.Nil(r.t, c.Setup(0, util.Sha512Checksum(rootData)))
+
+	return c
+}
+
+// newClientInDir returns the client of the existing local repository directory.
+func (r *testRepository) newClientInDir(dir string, opts ClientOptions) Client {
+	c, err := NewClient("test", filepath.Join(dir, "repo"), r.server.URL, filepath.Join(dir, "locks"), filepath.Join(dir, "tmp"), filepath.Join(dir, "logs"), filepath.Join(dir, "metafiles"), opts)
+	require.Nil(r.t, err)
+
+	return c
+}
+
+func assertReleaseBinData(t *testing.T, c Client, release, expected string) {
+	dir, err := c.GetReleaseDir(release)
+	if assert.Nil(t, err) {
+		data, err := os.ReadFile(filepath.Join(dir, "bin", "app"))
+		assert.Nil(t, err)
+		assert.Equal(t, expected, string(data))
+	}
+}
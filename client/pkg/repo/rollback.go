@@ -0,0 +1,180 @@
+package repo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/werf/lockgate"
+	"github.com/werf/trdl/client/pkg/trdl"
+	"github.com/werf/trdl/client/pkg/util"
+)
+
+const channelsStateDir = "channels_state"
+
+// channelState is kept locally per channel along with the channel file.
+type channelState struct {
+	// PreviousRelease is the release used before the last channel switch
+	PreviousRelease string `json:"previousRelease,omitempty"`
+	// HeldRelease is the release the channel is held at by the rollback, the channel is not updated until the hold is released
+	HeldRelease string `json:"heldRelease,omitempty"`
+}
+
+// RollbackChannel switches the channel to the previously used release and holds the channel there.
+func (c Client) RollbackChannel(group, channel string) (release string, err error) {
+	err = lockgate.WithAcquire(c.locker, c.updateChannelLockName(group, channel), lockgate.AcquireOptions{Shared: false, Timeout: time.Minute * 5}, func(_ bool) error {
+		state, err := c.readChannelState(group, channel)
+		if err != nil {
+			return err
+		}
+
+		if state.PreviousRelease == "" {
+			return fmt.Errorf("previous release of channel %[2]q not found locally (group: %[1]q)", group, channel)
+		}
+		release = state.PreviousRelease
+
+		if err := c.syncHeldChannelRelease(release); err != nil {
+			return fmt.Errorf("unable to sync release %q: %w", release, err)
+		}
+
+		if err := lockgate.WithAcquire(c.locker, c.channelLockName(group, channel), lockgate.AcquireOptions{Shared: false, Timeout: trdl.DefaultLockerTimeout}, func(_ bool) error {
+			return writeFileAtomically(c.channelPath(group, channel), c.channelTmpPath(group, channel), []byte(release))
+		}); err != nil {
+			return fmt.Errorf("unable to switch channel to release %q: %w", release, err)
+		}
+
+		return c.writeChannelState(group, channel, channelState{HeldRelease: release})
+	})
+
+	return
+}
+
+// ReleaseChannelHold allows the next update to switch the channel held by the rollback.
+func (c Client) ReleaseChannelHold(group, channel string) error {
+	return lockgate.WithAcquire(c.locker, c.updateChannelLockName(group, channel), lockgate.AcquireOptions{Shared: false, Timeout: time.Minute * 5}, func(_ bool) error {
+		state, err := c.readChannelState(group, channel)
+		if err != nil {
+			return err
+		}
+
+		if state.HeldRelease == "" {
+			return fmt.Errorf("channel %[2]q is not held (group: %[1]q)", group, channel)
+		}
+
+		// the held release is the previous one for the next update
+		return c.writeChannelState(group, channel, channelState{PreviousRelease: state.HeldRelease})
+	})
+}
+
+// syncHeldChannelRelease downloads the release only if it is not found locally.
+func (c Client) syncHeldChannelRelease(release string) error {
+	_, exist, err := c.findReleaseDir(release)
+	if err != nil {
+		return err
+	}
+
+	if exist {
+		return nil
+	}
+
+	if err := c.tufClient.Update(); err != nil {
+		return err
+	}
+
+	return c.syncChannelReleaseWithLock(release)
+}
+
+func (c Client) readChannelState(group, channel string) (channelState, error) {
+	return readChannelStateFile(c.channelStatePath(group, channel))
+}
+
+// readChannelStateFile returns the empty state if the file does not exist.
+func readChannelStateFile(statePath string) (channelState, error) {
+	var state channelState
+
+	exist, err := util.IsRegularFileExist(statePath)
+	if err != nil {
+		return state, fmt.Errorf("unable to check existence of file %q: %w", statePath, err)
+	}
+
+	if !exist {
+		return state, nil
+	}
+
+	data, err := ioutil.ReadFile(statePath)
+	if err != nil {
+		return state, fmt.Errorf("unable to read file %q: %w", statePath, err)
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("unable to unmarshal file %q: %w", statePath, err)
+	}
+
+	return state, nil
+}
+
+func (c Client) writeChannelState(group, channel string, state channelState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("unable to marshal channel state: %w", err)
+	}
+
+	return writeFileAtomically(c.channelStatePath(group, channel), c.channelStateTmpPath(group, channel), data)
+}
+
+// getChannelsStateReleases returns the previous and the held releases of all channels of all platforms.
+func (c Client) getChannelsStateReleases() (map[string]bool, error) {
+	releases := map[string]bool{}
+
+	filePathList, err := filepath.Glob(filepath.Join(c.dir, channelsStateDir, "*", "*"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to glob files: %w", err)
+	}
+
+	for _, filePath := range filePathList {
+		// the file name includes the platform of the channel, thus the file is read as is
+		state, err := readChannelStateFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, release := range []string{state.PreviousRelease, state.HeldRelease} {
+			if release != "" {
+				releases[release] = true
+			}
+		}
+	}
+
+	return releases, nil
+}
+
+func (c Client) channelStatePath(group, channel string) string {
+	return filepath.Join(c.dir, channelsStateDir, group, c.channelFileName(channel))
+}
+
+func (c Client) channelStateTmpPath(group, channel string) string {
+	return filepath.Join(c.tmpDir, channelsStateDir, group, c.channelFileName(channel))
+}
+
+func writeFileAtomically(path, tmpPath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(tmpPath), os.ModePerm); err != nil {
+		return fmt.Errorf("unable to mkdir all %q: %w", filepath.Dir(tmpPath), err)
+	}
+
+	if err := ioutil.WriteFile(tmpPath, data, fileModeRegular); err != nil {
+		return fmt.Errorf("unable to write file %q: %w", tmpPath, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("unable to mkdir all %q: %w", filepath.Dir(path), err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("unable to rename %q to %q: %w", tmpPath, path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRolledBackTestClient returns the client with the channel switched 0.1.0 -> 0.2.0 and rolled back to 0.1.0.
+func newRolledBackTestClient(t *testing.T, r *testRepository) Client {
+	r.publishChannelRelease("0", "stable", "0.1.0")
+	c := r.newClient(ClientOptions{})
+	updateTestChannel(t, c)
+
+	r.publishChannelRelease("0", "stable", "0.2.0")
+	updateTestChannel(t, c)
+
+	release, err := c.RollbackChannel("0", "stable")
+	require.Nil(t, err)
+	require.Equal(t, "0.1.0", release)
+
+	return c
+}
+
+func assertChannelRelease(t *testing.T, c Client, expected string) {
+	release, err := c.GetChannelRelease("0", "stable")
+	if assert.Nil(t, err) {
+		assert.Equal(t, expected, release)
+	}
+}
+
+func TestUpdateChannel_PreviousRelease(t *testing.T) {
+	r := newTestRepository(t)
+	r.publishChannelRelease("0", "stable", "0.1.0")
+
+	c := r.newClient(ClientOptions{})
+	updateTestChannel(t, c)
+
+	state, err := c.readChannelState("0", "stable")
+	require.Nil(t, err)
+	assert.Equal(t, channelState{}, state, "the first installation has no previous release")
+
+	r.publishChannelRelease("0", "stable", "0.2.0")
+	updateTestChannel(t, c)
+
+	state, err = c.readChannelState("0", "stable")
+	require.Nil(t, err)
+	assert.Equal(t, channelState{PreviousRelease: "0.1.0"}, state)
+
+	// the update without the channel switch keeps the previous release
+	updateTestChannel(t, c)
+
+	state, err = c.readChannelState("0", "stable")
+	require.Nil(t, err)
+	assert.Equal(t, channelState{PreviousRelease: "0.1.0"}, state)
+}
+
+func TestRollbackChannel(t *testing.T) {
+	r := newTestRepository(t)
+	c := newRolledBackTestClient(t, r)
+
+	assertChannelRelease(t, c, "0.1.0")
+	assertReleaseBinData(t, c, "0.1.0", "app 0.1.0")
+
+	state, err := c.readChannelState("0", "stable")
+	require.Nil(t, err)
+	assert.Equal(t, channelState{HeldRelease: "0.1.0"}, state)
+
+	// the held channel has no previous release to roll back to
+	_, err = c.RollbackChannel("0", "stable")
+	assert.EqualError(t, err, `previous release of channel "stable" not found locally (group: "0")`)
+}
+
+func TestRollbackChannel_NoPreviousRelease(t *testing.T) {
+	r := newTestRepository(t)
+	r.publishChannelRelease("0", "stable", "0.1.0")
+
+	c := r.newClient(ClientOptions{})
+	updateTestChannel(t, c)
+
+	_, err := c.RollbackChannel("0", "stable")
+	assert.EqualError(t, err, `previous release of channel "stable" not found locally (group: "0")`)
+	assertChannelRelease(t, c, "0.1.0")
+}
+
+func TestUpdateChannel_Held(t *testing.T) {
+	r := newTestRepository(t)
+	c := newRolledBackTestClient(t, r)
+
+	r.publishChannelRelease("0", "stable", "0.3.0")
+
+	releaseSwitch, err := c.UpdateChannel("0", "stable")
+	require.Nil(t, err)
+	assert.Nil(t, releaseSwitch)
+	assertChannelRelease(t, c, "0.1.0")
+
+	state, err := c.readChannelState("0", "stable")
+	require.Nil(t, err)
+	assert.Equal(t, channelState{HeldRelease: "0.1.0"}, state)
+}
+
+func TestReleaseChannelHold(t *testing.T) {
+	r := newTestRepository(t)
+	c := newRolledBackTestClient(t, r)
+
+	require.Nil(t, c.ReleaseChannelHold("0", "stable"))
+
+	state, err := c.readChannelState("0", "stable")
+	require.Nil(t, err)
+	assert.Equal(t, channelState{PreviousRelease: "0.1.0"}, state)
+
+	r.publishChannelRelease("0", "stable", "0.3.0")
+
+	releaseSwitch, err := c.UpdateChannel("0", "stable")
+	require.Nil(t, err)
+	assert.Equal(t, &ChannelReleaseSwitch{OldRelease: "0.1.0", NewRelease: "0.3.0"}, releaseSwitch)
+	assertChannelRelease(t, c, "0.3.0")
+	assertReleaseBinData(t, c, "0.3.0", "app 0.3.0")
+
+	err = c.ReleaseChannelHold("0", "stable")
+	assert.EqualError(t, err, `channel "stable" is not held (group: "0")`)
+}
@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/openpgp"
+)
+
+func (c Client) verifyReleaseFileSignature(release, releaseFileRelPath, releaseFilePath string) error {
+	signatureTargetName := c.releaseFileSignatureTargetName(release, releaseFileRelPath)
+
+	_, ok, err := c.tufClient.GetTarget(signatureTargetName)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("signature %q not found in the repository", signatureTargetName)
+	}
+
+	signatureTmpPath := filepath.Join(c.releaseSignaturesTmpDir(release), filepath.FromSlash(releaseFileRelPath)+".sig")
+	if err := os.RemoveAll(signatureTmpPath); err != nil {
+		return fmt.Errorf("unable to remove %q: %w", signatureTmpPath, err)
+	}
+	defer func() { _ = os.RemoveAll(signatureTmpPath) }()
+
+	if err := c.tufClient.DownloadFile(signatureTargetName, signatureTmpPath, fileModeRegular); err != nil {
+		return fmt.Errorf("unable to download signature %q: %w", signatureTargetName, err)
+	}
+
+	signature, err := os.Open(signatureTmpPath)
+	if err != nil {
+		return fmt.Errorf("unable to open file %q: %w", signatureTmpPath, err)
+	}
+	defer func() { _ = signature.Close() }()
+
+	signed, err := os.Open(releaseFilePath)
+	if err != nil {
+		return fmt.Errorf("unable to open file %q: %w", releaseFilePath, err)
+	}
+	defer func() { _ = signed.Close() }()
+
+	if _, err := openpgp.CheckDetachedSignature(c.pgpKeyring, signed, signature); err != nil {
+		return fmt.Errorf("signature %q mismatch: %w", signatureTargetName, err)
+	}
+
+	return nil
+}
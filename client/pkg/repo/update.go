@@ -5,7 +5,6 @@ import (
 	"os"
 	"path"
 	"path/filepath"
-	"runtime"
 	"strings"
 	"time"
 
@@ -22,25 +21,53 @@ var (
 	fileModeRegular    os.FileMode = 0o655
 )
 
-func (c Client) UpdateChannel(group, channel string) error {
-	return lockgate.WithAcquire(c.locker, c.updateChannelLockName(group, channel), lockgate.AcquireOptions{Shared: false, Timeout: time.Minute * 5}, func(_ bool) error {
+// ChannelReleaseSwitch is the change of the local channel release made by the update.
+type ChannelReleaseSwitch struct {
+	// OldRelease is empty if the channel has not been installed before
+	OldRelease string
+	NewRelease string
+}
+
+// UpdateChannel updates the channel release and returns the release switch or nil if the channel release has not been changed.
+// The switch is returned only to the update that has switched the channel.
+func (c Client) UpdateChannel(group, channel string) (*ChannelReleaseSwitch, error) {
+	var releaseSwitch *ChannelReleaseSwitch
+	if err := lockgate.WithAcquire(c.locker, c.updateChannelLockName(group, channel), lockgate.AcquireOptions{Shared: false, Timeout: time.Minute * 5}, func(_ bool) error {
+		state, err := c.readChannelState(group, channel)
+		if err != nil {
+			return err
+		}
+
+		// the channel is held by the rollback
+		if state.HeldRelease != "" {
+			_, _ = fmt.Fprintf(os.Stderr, "WARNING: Channel %q is held at release %q by the rollback and not updated (repo: %q, group: %q), run \"trdl unhold %s %s %s\" to release the hold\n", channel, state.HeldRelease, c.repoName, group, c.repoName, group, channel)
+			return c.syncHeldChannelRelease(state.HeldRelease)
+		}
+
 		if err := c.tufClient.Update(); err != nil {
 			return err
 		}
 
+		if err := c.syncChannelsConfigWithLock(); err != nil {
+			return fmt.Errorf("unable to sync channels config: %w", err)
+		}
+
+		if err := c.validateChannel(group, channel); err != nil {
+			return err
+		}
+
 		var deferErr error // the error affects the defer function
 		var channelUpToDate bool
 		var release string
 		channelPath := c.channelPath(group, channel)
 		channelTmpPath := c.channelTmpPath(group, channel)
 		{ // create tmp channel if channel is not up-to-date
-			targets, err := c.tufClient.GetTargets()
+			targetName := c.channelTargetName(group, channel)
+			targetMeta, ok, err := c.tufClient.GetTarget(targetName)
 			if err != nil {
 				return err
 			}
 
-			targetName := c.channelTargetName(group, channel)
-			targetMeta, ok := targets[targetName]
 			if !ok {
 				return fmt.Errorf("channel %[2]q not found in the repository (group: %[1]q)", group, channel)
 			}
@@ -80,22 +107,47 @@ func (c Client) UpdateChannel(group, channel string) error {
 
 		{ // rename tmp channel to channel (optional)
 			if !channelUpToDate {
-				return lockgate.WithAcquire(c.locker, c.channelLockName(group, channel), lockgate.AcquireOptions{Shared: false, Timeout: trdl.DefaultLockerTimeout}, func(_ bool) error {
-					if deferErr = os.MkdirAll(filepath.Dir(channelPath), os.ModePerm); deferErr != nil {
-						return fmt.Errorf("unable to mkdir all %q: %w", channelPath, deferErr)
+				var previousRelease string
+				if deferErr = lockgate.WithAcquire(c.locker, c.channelLockName(group, channel), lockgate.AcquireOptions{Shared: false, Timeout: trdl.DefaultLockerTimeout}, func(_ bool) error {
+					exist, err := util.IsRegularFileExist(channelPath)
+					if err != nil {
+						return fmt.Errorf("unable to check existence of file %q: %w", channelPath, err)
 					}
 
-					if deferErr = os.Rename(channelTmpPath, channelPath); deferErr != nil {
-						return deferErr
+					if exist {
+						if previousRelease, err = readChannelRelease(channelPath); err != nil {
+							return fmt.Errorf("unable to get channel release: %w", err)
+						}
 					}
 
-					return nil
-				})
+					if err := os.MkdirAll(filepath.Dir(channelPath), os.ModePerm); err != nil {
+						return fmt.Errorf("unable to mkdir all %q: %w", channelPath, err)
+					}
+
+					return os.Rename(channelTmpPath, channelPath)
+				}); deferErr != nil {
+					return deferErr
+				}
+
+				if previousRelease != release {
+					releaseSwitch = &ChannelReleaseSwitch{OldRelease: previousRelease, NewRelease: release}
+				}
+
+				// keep the previous release for the rollback
+				if previousRelease != "" && previousRelease != release {
+					if err := c.writeChannelState(group, channel, channelState{PreviousRelease: previousRelease}); err != nil {
+						return fmt.Errorf("unable to save channel state: %w", err)
+					}
+				}
 			}
 		}
 
 		return nil
-	})
+	}); err != nil {
+		return nil, err
+	}
+
+	return releaseSwitch, nil
 }
 
 func (c Client) syncChannelReleaseWithLock(release string) error {
@@ -116,6 +168,9 @@ func (c Client) syncChannelRelease(release string) error {
 	var deferErr error // the error affects the defer function
 	releaseDir := c.channelReleaseDir(release)
 	releaseTmpDir := c.channelReleaseTmpDir(release)
+	// only the platform directory is replaced to keep the releases for the other platforms
+	releasePlatformDir := filepath.Join(releaseDir, osArch)
+	releasePlatformTmpDir := filepath.Join(releaseTmpDir, osArch)
 	{ // stop updating if all release files are up-to-date
 		releaseFilesUpToDate := true
 		for targetName, targetMeta := range targets {
@@ -160,31 +215,32 @@ func (c Client) syncChannelRelease(release string) error {
 		if deferErr = c.syncFile(targetName, targetMeta, releaseFilePath, releaseFilePathMode); deferErr != nil {
 			return fmt.Errorf("unable to sync file %q: %w", releaseFilePath, deferErr)
 		}
+
+		if c.pgpKeyring != nil {
+			if deferErr = c.verifyReleaseFileSignature(release, filepath.ToSlash(releaseFileRelPath), releaseFilePath); deferErr != nil {
+				return fmt.Errorf("unable to verify release file %q signature: %w", releaseFilePath, deferErr)
+			}
+		}
 	}
 
-	if deferErr = os.RemoveAll(releaseDir); deferErr != nil {
-		return fmt.Errorf("unable to remove broken release dir %q: %w", releaseDir, deferErr)
+	if deferErr = os.RemoveAll(releasePlatformDir); deferErr != nil {
+		return fmt.Errorf("unable to remove broken release dir %q: %w", releasePlatformDir, deferErr)
 	}
 
-	if deferErr = os.MkdirAll(filepath.Dir(releaseDir), os.ModePerm); deferErr != nil {
+	if deferErr = os.MkdirAll(releaseDir, os.ModePerm); deferErr != nil {
 		return fmt.Errorf("unable to mkdir all %q: %w", releaseDir, deferErr)
 	}
 
-	if deferErr = os.Rename(releaseTmpDir, releaseDir); deferErr != nil {
+	if deferErr = os.Rename(releasePlatformTmpDir, releasePlatformDir); deferErr != nil {
 		return deferErr
 	}
 
-	return nil
+	return os.RemoveAll(releaseTmpDir)
 }
 
 func (c Client) selectAppropriateReleaseTargets(release string) (targets data.TargetFiles, resultOsArch string, err error) {
 	releaseTargetNamePrefix := c.releaseTargetNamePrefix(release)
-	for _, osArch := range []string{
-		fmt.Sprintf("%s-%s", runtime.GOOS, runtime.GOARCH),
-		fmt.Sprintf("%s-any", runtime.GOOS),
-		fmt.Sprintf("any-%s", runtime.GOARCH),
-		"any-any",
-	} {
+	for _, osArch := range c.platformOsArchList() {
 		prefix := path.Join(releaseTargetNamePrefix, osArch)
 		targets, err = c.filterTargets(prefix + "/")
 		if err != nil {
@@ -198,10 +254,7 @@ func (c Client) selectAppropriateReleaseTargets(release string) (targets data.Ta
 	}
 
 	if len(targets) == 0 {
-		return nil, "", fmt.Errorf(
-			"channel release %q not found in the repository (os: %q, arch: %q)",
-			release, runtime.GOOS, runtime.GOARCH,
-		)
+		return nil, "", NewReleaseNotFoundForPlatformError(release, c.platformString())
 	}
 
 	return targets, resultOsArch, nil
@@ -12,25 +12,58 @@ import (
 )
 
 func (c Client) UseChannelReleaseBinDir(group, channel, shell string, opts UseSourceOptions) (string, error) {
-	name, data := c.prepareSourceScriptFileNameAndData(group, channel, shell, opts)
-	sourceScriptPath, err := c.syncSourceScriptFile(group, channel, name, data)
-	if err != nil {
-		return "", err
-	}
+	return c.useBinDir(useTarget{
+		group:     group,
+		name:      channel,
+		args:      []string{channel},
+		envSuffix: "GROUP_CHANNEL",
+		envValue:  fmt.Sprintf("%s %s", group, channel),
+	}, shell, opts)
+}
 
-	return sourceScriptPath, nil
+// UseReleaseBinDir generates the script to use the exact release, see UpdateRelease.
+func (c Client) UseReleaseBinDir(group, release, shell string, opts UseSourceOptions) (string, error) {
+	return c.useBinDir(useTarget{
+		group:     group,
+		name:      "v" + release,
+		args:      []string{"--version", release},
+		envSuffix: "GROUP_VERSION",
+		envValue:  fmt.Sprintf("%s %s", group, release),
+	}, shell, opts)
 }
 
 type UseSourceOptions struct {
 	NoSelfUpdate bool
 }
 
-func (c Client) prepareSourceScriptFileNameAndData(group, channel, shell string, opts UseSourceOptions) (string, []byte) {
-	basename := c.prepareSourceScriptBasename(group, channel, shell, opts)
+// useTarget is the channel or the release of the group used by the source script.
+type useTarget struct {
+	group string
+	// name of the channel or the release in the script paths
+	name string
+	// args selecting the channel or the release in the trdl commands after the group
+	args []string
+	// envSuffix and envValue of the TRDL_USE_<REPO>_<SUFFIX> environment variable set by the script
+	envSuffix string
+	envValue  string
+}
+
+func (c Client) useBinDir(target useTarget, shell string, opts UseSourceOptions) (string, error) {
+	name, data := c.prepareSourceScriptFileNameAndData(target, shell, opts)
+	sourceScriptPath, err := c.syncSourceScriptFile(target.group, target.name, name, data)
+	if err != nil {
+		return "", err
+	}
+
+	return sourceScriptPath, nil
+}
+
+func (c Client) prepareSourceScriptFileNameAndData(target useTarget, shell string, opts UseSourceOptions) (string, []byte) {
+	basename := c.prepareSourceScriptBasename(target.group, target.name, shell, opts)
 	logPathBackgroundUpdateStdout := filepath.Join(c.logsDir, basename+"_background_update_stdout.log")
 	logPathBackgroundUpdateStderr := filepath.Join(c.logsDir, basename+"_background_update_stderr.log")
 
-	commonArgs := []string{c.repoName, group, channel}
+	commonArgs := append([]string{c.repoName, target.group}, target.args...)
 	foregroundUpdateArgs := commonArgs[0:]
 	backgroundUpdateArgs := append(
 		append([]string{}, commonArgs[0:]...),
@@ -49,8 +82,8 @@ func (c Client) prepareSourceScriptFileNameAndData(group, channel, shell string,
 	backgroundUpdateArgsString := strings.Join(backgroundUpdateArgs, " ")
 	_ = logPathBackgroundUpdateStderr
 	trdlBinaryPath := os.Args[0]
-	trdlUseRepoGroupChannelEnvName := fmt.Sprintf("TRDL_USE_%s_GROUP_CHANNEL", strings.ToUpper(c.repoName))
-	trdlUseRepoGroupChannelEnvValue := fmt.Sprintf("%s %s", group, channel)
+	trdlUseRepoGroupChannelEnvName := fmt.Sprintf("TRDL_USE_%s_%s", strings.ToUpper(c.repoName), target.envSuffix)
+	trdlUseRepoGroupChannelEnvValue := target.envValue
 
 	var tmpl string
 	var ext string
@@ -102,13 +135,13 @@ export PATH="$trdl_repo_bin_path${PATH:+:${PATH}}"
 	}
 
 	script := fmt.Sprintf(tmpl,
-		commonArgsString,                // %[1]s: REPO GROUP CHANNEL            (common args string)
+		commonArgsString,                // %[1]s: REPO GROUP CHANNEL            (common args string, or REPO GROUP --version RELEASE)
 		foregroundUpdateArgsString,      // %[2]s: REPO GROUP CHANNEL [flag ...] (foreground update args string)
 		backgroundUpdateArgsString,      // %[3]s: REPO GROUP CHANNEL [flag ...] (background update args string)
 		logPathBackgroundUpdateStderr,   // %[4]s: <path>                        (background update error file path)
 		trdlBinaryPath,                  // %[5]s: <path>                        (trdl binary path)
-		trdlUseRepoGroupChannelEnvName,  // %[6]s: <env name>                    (TRDL_USE_<REPO>_GROUP_CHANNEL)
-		trdlUseRepoGroupChannelEnvValue, // %[7]s: <env value>                   (TRDL_USE_<REPO>_GROUP_CHANNEL value)
+		trdlUseRepoGroupChannelEnvName,  // %[6]s: <env name>                    (TRDL_USE_<REPO>_GROUP_CHANNEL or TRDL_USE_<REPO>_GROUP_VERSION)
+		trdlUseRepoGroupChannelEnvValue, // %[7]s: <env value>                   (TRDL_USE_<REPO>_GROUP_CHANNEL or TRDL_USE_<REPO>_GROUP_VERSION value)
 	)
 
 	name := "source_script"
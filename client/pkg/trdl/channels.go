@@ -0,0 +1,57 @@
+package trdl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ChannelsTargetName is the target with the channels declared by the repository.
+const ChannelsTargetName = "trdl_channels.json"
+
+var channelNameRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9_-]*[a-z0-9])?$`)
+
+// ChannelsConfig is the declaration of the channels published by the repository.
+type ChannelsConfig struct {
+	// AllowedChannels in order from the least to the most stable one
+	AllowedChannels []string            `json:"allowedChannels"`
+	DefaultChannel  string              `json:"defaultChannel"`
+	Groups          map[string][]string `json:"groups,omitempty"`
+}
+
+// DefaultChannelsConfig is used for the repositories which do not declare the channels.
+func DefaultChannelsConfig() ChannelsConfig {
+	return ChannelsConfig{
+		AllowedChannels: Channels,
+		DefaultChannel:  DefaultChannel,
+	}
+}
+
+func (c ChannelsConfig) GetGroupAllowedChannels(group string) []string {
+	if groupChannels, ok := c.Groups[group]; ok && len(groupChannels) != 0 {
+		return groupChannels
+	}
+
+	return c.AllowedChannels
+}
+
+func (c ChannelsConfig) ValidateGroupChannel(group, channel string) error {
+	allowedChannels := c.GetGroupAllowedChannels(group)
+	for _, allowedChannel := range allowedChannels {
+		if allowedChannel == channel {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"unsupported channel %q specified (group: %q), use one of the following: \"%s\"",
+		channel, group, strings.Join(allowedChannels, `", "`))
+}
+
+func ValidateChannelName(channel string) error {
+	if !channelNameRegexp.MatchString(channel) {
+		return fmt.Errorf("invalid channel name %q: lower case alphanumeric characters, '-' or '_' expected", channel)
+	}
+
+	return nil
+}
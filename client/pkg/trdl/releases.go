@@ -0,0 +1,35 @@
+package trdl
+
+import "time"
+
+// ReleasesIndexTargetName is the target with the index of the published releases.
+const ReleasesIndexTargetName = "trdl_releases.json"
+
+// ChannelsHistoryTargetName is the target with the history of the channels.
+const ChannelsHistoryTargetName = "trdl_channels_history.json"
+
+// ReleasesIndex is the index of the published releases maintained by the repository.
+type ReleasesIndex struct {
+	Releases map[string]*ReleasesIndexEntry `json:"releases"`
+}
+
+type ReleasesIndexEntry struct {
+	// Platforms in format <os>-<arch>
+	Platforms []string `json:"platforms"`
+	// PublishedAt, GitTag and GitCommit are unknown for the releases published before the index
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+	GitTag      string     `json:"gitTag,omitempty"`
+	GitCommit   string     `json:"gitCommit,omitempty"`
+}
+
+// ChannelsHistory keeps the versions of each group channel in order of publishing.
+type ChannelsHistory struct {
+	Groups map[string]map[string][]*ChannelsHistoryEntry `json:"groups"`
+}
+
+type ChannelsHistoryEntry struct {
+	Version     string    `json:"version"`
+	PublishedAt time.Time `json:"publishedAt"`
+	GitCommit   string    `json:"gitCommit,omitempty"`
+	Rollback    bool      `json:"rollback,omitempty"`
+}
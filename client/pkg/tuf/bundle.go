@@ -0,0 +1,147 @@
+package tuf
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	tufClient "github.com/theupdateframework/go-tuf/client"
+
+	"github.com/werf/lockgate"
+)
+
+const (
+	bundleMetadataDir = "metadata"
+	bundleTargetsDir  = "targets"
+)
+
+// bundleRecorderLocalMeta is the local metadata the bundle recorder starts with,
+// the targets metadata is not kept to record it in the bundle even if it is up-to-date locally.
+var bundleRecorderLocalMeta = []string{"root.json", "timestamp.json", "snapshot.json"}
+
+// NewBundleRecorder returns the client saving all fetched metadata and targets into the bundle directory.
+// The fetched metadata is verified against the locally trusted metadata as usual.
+func (c *Client) NewBundleRecorder(bundleDir string) (*Client, error) {
+	recorder := &recordingRemoteStore{RemoteStore: c.RemoteStore, dir: bundleDir}
+	return c.newClientWithRemoteStore(recorder, bundleRecorderLocalMeta)
+}
+
+// NewBundleClient returns the client fetching the metadata and targets from the extracted bundle directory.
+// The bundle metadata is verified against the locally trusted metadata and saved locally on update.
+func (c *Client) NewBundleClient(bundleDir string) (*Client, error) {
+	return c.newClientWithRemoteStore(dirRemoteStore{dir: bundleDir}, nil)
+}
+
+// RecordRootChain saves all versions of the root metadata into the bundle directory
+// to allow the clients with the older trusted root to verify the bundle.
+func (c *Client) RecordRootChain() error {
+	for version := 1; ; version++ {
+		if _, err := c.DownloadMeta(fmt.Sprintf("%d.root.json", version)); err != nil {
+			if tufClient.IsNotFound(err) {
+				return nil
+			}
+
+			return fmt.Errorf("unable to download root version %d: %w", version, err)
+		}
+	}
+}
+
+func (c *Client) newClientWithRemoteStore(remote tufClient.RemoteStore, optionalLocalMeta []string) (*Client, error) {
+	newClient := &Client{
+		repoUrl:           c.repoUrl,
+		remoteOpts:        c.remoteOpts,
+		metaLocalStoreDir: c.metaLocalStoreDir,
+		locker:            c.locker,
+	}
+
+	if err := lockgate.WithAcquire(
+		c.locker,
+		metaLocalStoreDirLockName,
+		lockgate.AcquireOptions{Shared: false, Timeout: time.Minute * 2},
+		func(_ bool) error {
+			return newClient.initTufClientWithRemoteStore(remote, optionalLocalMeta)
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	return newClient, nil
+}
+
+// recordingRemoteStore saves the fetched files in the bundle directory layout.
+type recordingRemoteStore struct {
+	tufClient.RemoteStore
+	dir string
+}
+
+func (s *recordingRemoteStore) GetMeta(name string) (io.ReadCloser, int64, error) {
+	return s.record(s.RemoteStore.GetMeta, filepath.Join(s.dir, bundleMetadataDir, filepath.FromSlash(name)), name)
+}
+
+func (s *recordingRemoteStore) GetTarget(path string) (io.ReadCloser, int64, error) {
+	return s.record(s.RemoteStore.GetTarget, filepath.Join(s.dir, bundleTargetsDir, filepath.FromSlash(path)), path)
+}
+
+func (s *recordingRemoteStore) record(get func(string) (io.ReadCloser, int64, error), dest, name string) (io.ReadCloser, int64, error) {
+	stream, _, err := get(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = stream.Close() }()
+
+	if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+		return nil, 0, fmt.Errorf("unable to mkdir all %q: %w", filepath.Dir(dest), err)
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to create file %q: %w", dest, err)
+	}
+
+	size, err := io.Copy(f, stream)
+	if err != nil {
+		_ = f.Close()
+		return nil, 0, fmt.Errorf("unable to write file %q: %w", dest, err)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		_ = f.Close()
+		return nil, 0, fmt.Errorf("unable to seek file %q: %w", dest, err)
+	}
+
+	return f, size, nil
+}
+
+// dirRemoteStore serves the files of the bundle directory.
+type dirRemoteStore struct {
+	dir string
+}
+
+func (s dirRemoteStore) GetMeta(name string) (io.ReadCloser, int64, error) {
+	return s.get(filepath.Join(s.dir, bundleMetadataDir, filepath.FromSlash(name)), name)
+}
+
+func (s dirRemoteStore) GetTarget(path string) (io.ReadCloser, int64, error) {
+	return s.get(filepath.Join(s.dir, bundleTargetsDir, filepath.FromSlash(path)), path)
+}
+
+func (s dirRemoteStore) get(path, name string) (io.ReadCloser, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, tufClient.ErrNotFound{File: name}
+		}
+
+		return nil, 0, fmt.Errorf("unable to open file %q: %w", path, err)
+	}
+
+	fileInfo, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, 0, fmt.Errorf("unable to stat file %q: %w", path, err)
+	}
+
+	return f, fileInfo.Size(), nil
+}
@@ -2,12 +2,12 @@ package tuf
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
 	tufClient "github.com/theupdateframework/go-tuf/client"
 	leveldbstore "github.com/theupdateframework/go-tuf/client/leveldbstore"
-	"github.com/theupdateframework/go-tuf/data"
 
 	"github.com/werf/lockgate"
 	"github.com/werf/lockgate/pkg/file_locker"
@@ -22,14 +22,16 @@ type Client struct {
 	ReadOnlyLocalStore tufClient.LocalStore
 
 	repoUrl           string
+	remoteOpts        RemoteOptions
 	metaLocalStoreDir string
 	locker            lockgate.Locker
 }
 
-func NewClient(repoUrl, metaLocalStoreDir, locksPath string) (*Client, error) {
+func NewClient(repoUrl, metaLocalStoreDir, locksPath string, remoteOpts RemoteOptions) (*Client, error) {
 	c := &Client{}
 	c.metaLocalStoreDir = metaLocalStoreDir
 	c.repoUrl = repoUrl
+	c.remoteOpts = remoteOpts
 
 	if err := c.initFileLocker(locksPath); err != nil {
 		return nil, fmt.Errorf("unable to init file locker: %w", err)
@@ -65,6 +67,40 @@ func (c *Client) initFileLocker(locksPath string) error {
 }
 
 func (c *Client) initTufClient() error {
+	httpClient, err := newHTTPClient(c.repoUrl, c.remoteOpts)
+	if err != nil {
+		return fmt.Errorf("unable to init http client: %w", err)
+	}
+
+	var remote tufClient.RemoteStore
+	if len(c.remoteOpts.Mirrors) == 0 {
+		remote, err = tufClient.HTTPRemoteStore(c.repoUrl, nil, httpClient)
+	} else {
+		// the headers and the client certificate are the credentials of the repository url only
+		var mirrorHTTPClient *http.Client
+		mirrorHTTPClient, err = newHTTPClient(c.repoUrl, c.remoteOpts.mirrorOptions())
+		if err != nil {
+			return fmt.Errorf("unable to init mirror http client: %w", err)
+		}
+
+		urls := append([]string{c.repoUrl}, c.remoteOpts.Mirrors...)
+		remote, err = newMirrorsRemoteStore(urls, c.remoteOpts.RaceMirrors, c.remoteOpts.MirrorsFetchLogPath, func(url string) (tufClient.RemoteStore, error) {
+			if url == c.repoUrl {
+				return tufClient.HTTPRemoteStore(url, nil, httpClient)
+			}
+
+			return tufClient.HTTPRemoteStore(url, nil, mirrorHTTPClient)
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("unable to init http remote store: %w", err)
+	}
+
+	return c.initTufClientWithRemoteStore(remote, nil)
+}
+
+// initTufClientWithRemoteStore loads the local metadata, all metadata is loaded if the optionalLocalMeta list is empty.
+func (c *Client) initTufClientWithRemoteStore(remote tufClient.RemoteStore, optionalLocalMeta []string) error {
 	localDB, err := leveldbstore.FileLocalStore(c.metaLocalStoreDir)
 	if err != nil {
 		return fmt.Errorf("unable to init file local store: %w", err)
@@ -80,16 +116,15 @@ func (c *Client) initTufClient() error {
 
 	localMemory := tufClient.MemoryLocalStore()
 	for name, meta := range allMeta {
+		if len(optionalLocalMeta) != 0 && !isStringInList(name, optionalLocalMeta) {
+			continue
+		}
+
 		if err := localMemory.SetMeta(name, meta); err != nil {
 			return fmt.Errorf("unable to set meta: %w", err)
 		}
 	}
 
-	remote, err := tufClient.HTTPRemoteStore(c.repoUrl, nil, nil)
-	if err != nil {
-		return fmt.Errorf("unable to init http remote store: %w", err)
-	}
-
 	c.Client = tufClient.NewClient(localMemory, remote)
 	c.ReadOnlyLocalStore = localMemory
 	c.RemoteStore = remote
@@ -97,6 +132,16 @@ func (c *Client) initTufClient() error {
 	return nil
 }
 
+func isStringInList(s string, list []string) bool {
+	for _, elm := range list {
+		if elm == s {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (c *Client) Setup(rootVersion int64, rootSha512 string) error {
 	return lockgate.WithAcquire(
 		c.locker, metaLocalStoreDirLockName,
@@ -188,7 +233,3 @@ func (c *Client) saveMeta() error {
 
 	return nil
 }
-
-func (c Client) GetTargets() (data.TargetFiles, error) {
-	return c.Client.Targets()
-}
@@ -1,6 +1,7 @@
 package tuf
 
 import (
+	"bytes"
 	"io"
 	"os"
 	"path/filepath"
@@ -37,6 +38,25 @@ func (t *destinationFile) Delete() error {
 	return os.Remove(t.Name())
 }
 
+// DownloadBytes downloads and verifies the small target in memory.
+func (c Client) DownloadBytes(targetName string) ([]byte, error) {
+	var dest bufferDestination
+	if err := c.Download(targetName, &dest); err != nil {
+		return nil, err
+	}
+
+	return dest.Bytes(), nil
+}
+
+type bufferDestination struct {
+	bytes.Buffer
+}
+
+func (t *bufferDestination) Delete() error {
+	t.Reset()
+	return nil
+}
+
 func (c Client) Download(targetName string, destination tufClient.Destination) error {
 	return c.Client.Download(tufUtil.NormalizeTarget(targetName), destination)
 }
@@ -49,3 +69,18 @@ func (c Client) DownloadMeta(name string) ([]byte, error) {
 
 	return io.ReadAll(ioReader)
 }
+
+// Fetch downloads and verifies the target discarding the data.
+func (c Client) Fetch(targetName string) error {
+	return c.Download(targetName, discardDestination{})
+}
+
+type discardDestination struct{}
+
+func (discardDestination) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (discardDestination) Delete() error {
+	return nil
+}
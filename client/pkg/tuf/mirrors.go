@@ -0,0 +1,174 @@
+package tuf
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tufClient "github.com/theupdateframework/go-tuf/client"
+)
+
+// fetchLogMaxSize is the size of the fetch log file after which the log is started anew.
+const fetchLogMaxSize = 1024 * 1024
+
+type mirror struct {
+	url   string
+	store tufClient.RemoteStore
+}
+
+// mirrorsRemoteStore fetches the files from the first available mirror in order or from the fastest one in race mode.
+// Any mirror is equally trusted since the fetched files are verified by TUF.
+type mirrorsRemoteStore struct {
+	mirrors      []mirror
+	race         bool
+	fetchLogPath string
+}
+
+func newMirrorsRemoteStore(urls []string, race bool, fetchLogPath string, newStore func(url string) (tufClient.RemoteStore, error)) (*mirrorsRemoteStore, error) {
+	s := &mirrorsRemoteStore{race: race, fetchLogPath: fetchLogPath}
+	for _, url := range urls {
+		store, err := newStore(url)
+		if err != nil {
+			return nil, fmt.Errorf("unable to init remote store %q: %w", url, err)
+		}
+
+		s.mirrors = append(s.mirrors, mirror{url: url, store: store})
+	}
+
+	return s, nil
+}
+
+func (s *mirrorsRemoteStore) GetMeta(name string) (io.ReadCloser, int64, error) {
+	return s.get(name, func(store tufClient.RemoteStore) (io.ReadCloser, int64, error) {
+		return store.GetMeta(name)
+	})
+}
+
+func (s *mirrorsRemoteStore) GetTarget(path string) (io.ReadCloser, int64, error) {
+	return s.get(path, func(store tufClient.RemoteStore) (io.ReadCloser, int64, error) {
+		return store.GetTarget(path)
+	})
+}
+
+type mirrorResult struct {
+	mirror mirror
+	stream io.ReadCloser
+	size   int64
+	err    error
+}
+
+func (s *mirrorsRemoteStore) get(name string, get func(store tufClient.RemoteStore) (io.ReadCloser, int64, error)) (io.ReadCloser, int64, error) {
+	var results []mirrorResult
+	if s.race && len(s.mirrors) > 1 {
+		results = s.raceMirrors(name, get)
+	} else {
+		for _, m := range s.mirrors {
+			stream, size, err := get(m.store)
+			results = append(results, mirrorResult{mirror: m, stream: stream, size: size, err: err})
+			if err == nil {
+				break
+			}
+
+			s.logFetch(name, m.url, err)
+		}
+	}
+
+	last := results[len(results)-1]
+	if last.err == nil {
+		s.logFetch(name, last.mirror.url, nil)
+		return last.stream, last.size, nil
+	}
+
+	return nil, 0, mirrorsError(name, results)
+}
+
+// raceMirrors requests all mirrors at once and returns the first successful result or all failed results,
+// the streams of the late mirrors are closed.
+func (s *mirrorsRemoteStore) raceMirrors(name string, get func(store tufClient.RemoteStore) (io.ReadCloser, int64, error)) []mirrorResult {
+	resultCh := make(chan mirrorResult, len(s.mirrors))
+	for _, m := range s.mirrors {
+		go func(m mirror) {
+			stream, size, err := get(m.store)
+			resultCh <- mirrorResult{mirror: m, stream: stream, size: size, err: err}
+		}(m)
+	}
+
+	var failed []mirrorResult
+	for i := range s.mirrors {
+		result := <-resultCh
+		if result.err != nil {
+			s.logFetch(name, result.mirror.url, result.err)
+			failed = append(failed, result)
+			continue
+		}
+
+		go func(late int) {
+			for j := 0; j < late; j++ {
+				if r := <-resultCh; r.err == nil {
+					_ = r.stream.Close()
+				}
+			}
+		}(len(s.mirrors) - i - 1)
+
+		return []mirrorResult{result}
+	}
+
+	return failed
+}
+
+// mirrorsError returns the not found error if any available mirror has not found the file,
+// thus TUF client stops looking for the next root version as usual even if some mirrors are unavailable.
+func mirrorsError(name string, results []mirrorResult) error {
+	if len(results) == 1 {
+		return results[0].err
+	}
+
+	var errs []string
+	var isNotFound bool
+	for _, result := range results {
+		if tufClient.IsNotFound(result.err) {
+			isNotFound = true
+		}
+
+		errs = append(errs, fmt.Sprintf("%s: %s", result.mirror.url, result.err))
+	}
+
+	if isNotFound {
+		return tufClient.ErrNotFound{File: name}
+	}
+
+	return fmt.Errorf("unable to fetch %q from all mirrors:\n%s", name, strings.Join(errs, "\n"))
+}
+
+// logFetch writes the mirror the file is fetched from or the fetch error into the fetch log file,
+// the log file is started anew when it grows too large.
+func (s *mirrorsRemoteStore) logFetch(name, url string, fetchErr error) {
+	if s.fetchLogPath == "" {
+		return
+	}
+
+	flag := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if fileInfo, err := os.Stat(s.fetchLogPath); err == nil && fileInfo.Size() > fetchLogMaxSize {
+		flag |= os.O_TRUNC
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.fetchLogPath), os.ModePerm); err != nil {
+		return
+	}
+
+	f, err := os.OpenFile(s.fetchLogPath, flag, 0o644)
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	status := "ok"
+	if fetchErr != nil {
+		status = fmt.Sprintf("failed: %s", fetchErr)
+	}
+
+	_, _ = fmt.Fprintf(f, "%s %s %s %s\n", time.Now().Format(time.RFC3339), name, url, status)
+}
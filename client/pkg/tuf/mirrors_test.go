@@ -0,0 +1,131 @@
+package tuf
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	tufClient "github.com/theupdateframework/go-tuf/client"
+)
+
+// fakeRemoteStore serves the data or fails with the error counting the fetches.
+type fakeRemoteStore struct {
+	data    string
+	err     error
+	fetches int
+}
+
+func (s *fakeRemoteStore) GetMeta(name string) (io.ReadCloser, int64, error) {
+	return s.get()
+}
+
+func (s *fakeRemoteStore) GetTarget(path string) (io.ReadCloser, int64, error) {
+	return s.get()
+}
+
+func (s *fakeRemoteStore) get() (io.ReadCloser, int64, error) {
+	s.fetches++
+	if s.err != nil {
+		return nil, 0, s.err
+	}
+
+	return ioutil.NopCloser(strings.NewReader(s.data)), int64(len(s.data)), nil
+}
+
+func newTestMirrorsRemoteStore(t *testing.T, race bool, fetchLogPath string, stores map[string]*fakeRemoteStore, urls ...string) *mirrorsRemoteStore {
+	s, err := newMirrorsRemoteStore(urls, race, fetchLogPath, func(url string) (tufClient.RemoteStore, error) {
+		return stores[url], nil
+	})
+	assert.Nil(t, err)
+
+	return s
+}
+
+func readAll(t *testing.T, stream io.ReadCloser) string {
+	defer func() { _ = stream.Close() }()
+
+	data, err := ioutil.ReadAll(stream)
+	assert.Nil(t, err)
+
+	return string(data)
+}
+
+func TestMirrorsRemoteStore_Failover(t *testing.T) {
+	stores := map[string]*fakeRemoteStore{
+		"primary": {err: errors.New("connection refused")},
+		"mirror1": {data: "mirror1"},
+		"mirror2": {data: "mirror2"},
+	}
+	fetchLogPath := filepath.Join(t.TempDir(), "fetch.log")
+	s := newTestMirrorsRemoteStore(t, false, fetchLogPath, stores, "primary", "mirror1", "mirror2")
+
+	stream, size, err := s.GetTarget("file")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len("mirror1")), size)
+	assert.Equal(t, "mirror1", readAll(t, stream))
+
+	assert.Equal(t, 1, stores["primary"].fetches)
+	assert.Equal(t, 1, stores["mirror1"].fetches)
+	assert.Equal(t, 0, stores["mirror2"].fetches)
+
+	data, err := os.ReadFile(fetchLogPath)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if assert.Len(t, lines, 2) {
+		assert.Contains(t, lines[0], "file primary failed: connection refused")
+		assert.Contains(t, lines[1], "file mirror1 ok")
+	}
+}
+
+func TestMirrorsRemoteStore_Race(t *testing.T) {
+	stores := map[string]*fakeRemoteStore{
+		"primary": {err: errors.New("connection refused")},
+		"mirror1": {data: "data"},
+	}
+	s := newTestMirrorsRemoteStore(t, true, "", stores, "primary", "mirror1")
+
+	stream, _, err := s.GetMeta("root.json")
+	assert.Nil(t, err)
+	assert.Equal(t, "data", readAll(t, stream))
+}
+
+func TestMirrorsError(t *testing.T) {
+	notFoundErr := tufClient.ErrNotFound{File: "2.root.json"}
+	otherErr := errors.New("connection refused")
+
+	for _, tc := range []struct {
+		name             string
+		errs             []error
+		expectedNotFound bool
+		expectedErr      error
+	}{
+		{name: "single mirror error is returned as is", errs: []error{otherErr}, expectedErr: otherErr},
+		{name: "single mirror not found", errs: []error{notFoundErr}, expectedNotFound: true},
+		{name: "not found by any mirror", errs: []error{otherErr, notFoundErr}, expectedNotFound: true},
+		{name: "not found by all mirrors", errs: []error{notFoundErr, notFoundErr}, expectedNotFound: true},
+		{name: "all mirrors unavailable", errs: []error{otherErr, otherErr}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var results []mirrorResult
+			for i, err := range tc.errs {
+				results = append(results, mirrorResult{mirror: mirror{url: []string{"primary", "mirror"}[i]}, err: err})
+			}
+
+			err := mirrorsError("2.root.json", results)
+			assert.Equal(t, tc.expectedNotFound, tufClient.IsNotFound(err))
+
+			switch {
+			case tc.expectedErr != nil:
+				assert.Equal(t, tc.expectedErr, err)
+			case !tc.expectedNotFound:
+				assert.Contains(t, err.Error(), "primary: connection refused")
+				assert.Contains(t, err.Error(), "mirror: connection refused")
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package tuf
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// RemoteOptions are the connection settings of the TUF repository applied to all metadata and target fetches.
+type RemoteOptions struct {
+	// Proxy is the HTTP proxy url, the proxy is taken from the environment by default ($HTTPS_PROXY, $HTTP_PROXY and $NO_PROXY)
+	Proxy string
+	// CACertFile is the PEM file with the CA certificates trusted in addition to the system ones
+	CACertFile string
+	// ClientCertFile and ClientKeyFile are the PEM files of the client TLS certificate presented only to the repository url
+	ClientCertFile string
+	ClientKeyFile  string
+	// Headers are added to the requests to the repository url, the credentials are not sent to the mirrors
+	Headers map[string]string
+	// Mirrors are the urls of the repository mirrors tried in order after the repository url if the fetch fails
+	Mirrors []string
+	// RaceMirrors fetches from the repository url and all mirrors at once using the fastest response
+	RaceMirrors bool
+	// MirrorsFetchLogPath is the file where the mirror of each fetch is logged if the mirrors are used
+	MirrorsFetchLogPath string
+}
+
+// mirrorOptions returns the options of the mirror requests without the credentials of the repository url.
+func (o RemoteOptions) mirrorOptions() RemoteOptions {
+	o.ClientCertFile, o.ClientKeyFile, o.Headers = "", "", nil
+	return o
+}
+
+func (o RemoteOptions) isDefault() bool {
+	return o.Proxy == "" && o.CACertFile == "" && o.ClientCertFile == "" && o.ClientKeyFile == "" && len(o.Headers) == 0
+}
+
+// newHTTPClient returns nil if the default http client can be used.
+// The headers are added only to the requests to the host of the repository url, thus they are not sent on redirects to other hosts.
+func newHTTPClient(repoUrl string, opts RemoteOptions) (*http.Client, error) {
+	if opts.isDefault() {
+		return nil, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.Proxy != "" {
+		proxyUrl, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse proxy url %q: %w", opts.Proxy, err)
+		}
+
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+
+	if opts.CACertFile != "" || opts.ClientCertFile != "" || opts.ClientKeyFile != "" {
+		tlsConfig, err := newTLSConfig(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if len(opts.Headers) != 0 {
+		u, err := url.Parse(repoUrl)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse repository url %q: %w", repoUrl, err)
+		}
+
+		roundTripper = headersRoundTripper{RoundTripper: transport, host: u.Host, headers: opts.Headers}
+	}
+
+	return &http.Client{Transport: roundTripper}, nil
+}
+
+func newTLSConfig(opts RemoteOptions) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if opts.CACertFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		data, err := ioutil.ReadFile(opts.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA certificate file %q: %w", opts.CACertFile, err)
+		}
+
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no PEM certificates found in CA certificate file %q", opts.CACertFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.ClientCertFile != "" || opts.ClientKeyFile != "" {
+		if opts.ClientCertFile == "" || opts.ClientKeyFile == "" {
+			return nil, fmt.Errorf("both client certificate and client key files must be specified")
+		}
+
+		cert, err := tls.LoadX509KeyPair(opts.ClientCertFile, opts.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate %q and key %q: %w", opts.ClientCertFile, opts.ClientKeyFile, err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+type headersRoundTripper struct {
+	http.RoundTripper
+	host    string
+	headers map[string]string
+}
+
+func (t headersRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.host {
+		return t.RoundTripper.RoundTrip(req)
+	}
+
+	// the request must not be modified by the round tripper
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+
+	return t.RoundTripper.RoundTrip(req)
+}
@@ -0,0 +1,90 @@
+package tuf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteOptionsMirrorOptions(t *testing.T) {
+	opts := RemoteOptions{
+		Proxy:          "http://proxy:3128",
+		CACertFile:     "ca.pem",
+		ClientCertFile: "client.pem",
+		ClientKeyFile:  "client-key.pem",
+		Headers:        map[string]string{"Authorization": "Bearer TOKEN"},
+		Mirrors:        []string{"https://mirror"},
+	}
+
+	assert.Equal(t, RemoteOptions{
+		Proxy:      "http://proxy:3128",
+		CACertFile: "ca.pem",
+		Mirrors:    []string{"https://mirror"},
+	}, opts.mirrorOptions())
+}
+
+func TestNewHTTPClient_Headers(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	opts := RemoteOptions{Headers: map[string]string{"Authorization": "Bearer TOKEN"}}
+
+	for _, tc := range []struct {
+		name     string
+		opts     RemoteOptions
+		expected string
+	}{
+		{name: "repository", opts: opts, expected: "Bearer TOKEN"},
+		{name: "mirror", opts: opts.mirrorOptions(), expected: ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			httpClient, err := newHTTPClient(server.URL, tc.opts)
+			assert.Nil(t, err)
+			if httpClient == nil {
+				httpClient = http.DefaultClient
+			}
+
+			authorization = ""
+			resp, err := httpClient.Get(server.URL)
+			if assert.Nil(t, err) {
+				_ = resp.Body.Close()
+			}
+
+			assert.Equal(t, tc.expected, authorization)
+		})
+	}
+}
+
+func TestNewHTTPClient_HeadersNotSentOnRedirectToOtherHost(t *testing.T) {
+	var authorization string
+	otherServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer otherServer.Close()
+
+	var repoAuthorization string
+	repoServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repoAuthorization = r.Header.Get("Authorization")
+		http.Redirect(w, r, otherServer.URL+r.URL.Path, http.StatusFound)
+	}))
+	defer repoServer.Close()
+
+	httpClient, err := newHTTPClient(repoServer.URL, RemoteOptions{Headers: map[string]string{"Authorization": "Bearer TOKEN"}})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	authorization = "unset"
+	resp, err := httpClient.Get(repoServer.URL + "/root.json")
+	if assert.Nil(t, err) {
+		_ = resp.Body.Close()
+	}
+
+	assert.Equal(t, "Bearer TOKEN", repoAuthorization)
+	assert.Equal(t, "", authorization)
+}
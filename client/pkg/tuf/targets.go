@@ -0,0 +1,167 @@
+package tuf
+
+import (
+	"encoding/json"
+	"fmt"
+
+	tufClient "github.com/theupdateframework/go-tuf/client"
+	"github.com/theupdateframework/go-tuf/data"
+	tufUtil "github.com/theupdateframework/go-tuf/util"
+	"github.com/theupdateframework/go-tuf/verify"
+)
+
+// GetTarget looks for the target in the top-level targets role and in the delegated roles trusted to sign the target path.
+func (c Client) GetTarget(targetName string) (data.TargetFileMeta, bool, error) {
+	targetMeta, err := c.Client.Target(tufUtil.NormalizeTarget(targetName))
+	if err != nil {
+		if _, ok := err.(tufClient.ErrNotFound); ok {
+			return data.TargetFileMeta{}, false, nil
+		}
+
+		return data.TargetFileMeta{}, false, err
+	}
+
+	return targetMeta, true, nil
+}
+
+// GetTargets returns targets of the top-level targets role along with targets of the delegated roles.
+// The target of the delegated role is returned only if all delegations leading to the role are trusted to sign the target path.
+func (c Client) GetTargets() (data.TargetFiles, error) {
+	// Targets verifies the local top-level metadata
+	topLevelTargetFiles, err := c.Client.Targets()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(data.TargetFiles)
+	for name, targetMeta := range topLevelTargetFiles {
+		result[name] = targetMeta
+	}
+
+	localMeta, err := c.ReadOnlyLocalStore.GetMeta()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get local meta: %w", err)
+	}
+
+	topLevelTargets := &data.Targets{}
+	if err := unmarshalSignedMeta(localMeta["targets.json"], topLevelTargets); err != nil {
+		return nil, fmt.Errorf("unable to decode targets.json: %w", err)
+	}
+
+	if topLevelTargets.Delegations == nil {
+		return result, nil
+	}
+
+	snapshot := &data.Snapshot{}
+	if err := unmarshalSignedMeta(localMeta["snapshot.json"], snapshot); err != nil {
+		return nil, fmt.Errorf("unable to decode snapshot.json: %w", err)
+	}
+
+	visited := map[string]bool{"targets": true}
+	if err := c.collectDelegatedTargets(result, topLevelTargets.Delegations, nil, snapshot, localMeta, visited); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c Client) collectDelegatedTargets(result data.TargetFiles, delegations *data.Delegations, chain []data.DelegatedRole, snapshot *data.Snapshot, localMeta map[string]json.RawMessage, visited map[string]bool) error {
+	db, err := verify.NewDBFromDelegations(delegations)
+	if err != nil {
+		return fmt.Errorf("unable to init delegations keys db: %w", err)
+	}
+
+	for _, role := range delegations.Roles {
+		if visited[role.Name] {
+			continue
+		}
+		visited[role.Name] = true
+
+		targets, err := c.loadDelegatedTargets(role.Name, db, snapshot, localMeta)
+		if err != nil {
+			return err
+		}
+
+		roleChain := append(chain[:len(chain):len(chain)], role)
+		for name, targetMeta := range targets.Targets {
+			if _, ok := result[name]; ok {
+				continue
+			}
+
+			if isTargetPathDelegated(roleChain, name) {
+				result[name] = targetMeta
+			}
+		}
+
+		if targets.Delegations != nil {
+			if err := c.collectDelegatedTargets(result, targets.Delegations, roleChain, snapshot, localMeta, visited); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadDelegatedTargets gets the delegated role metadata from the local store or downloads it,
+// the metadata is verified by the delegator keys and the snapshot.
+func (c Client) loadDelegatedTargets(role string, db *verify.DB, snapshot *data.Snapshot, localMeta map[string]json.RawMessage) (*data.Targets, error) {
+	fileName := role + ".json"
+
+	snapshotMeta, ok := snapshot.Meta[fileName]
+	if !ok {
+		return nil, fmt.Errorf("%q not found in snapshot.json", fileName)
+	}
+
+	if raw, ok := localMeta[fileName]; ok {
+		targets := &data.Targets{}
+		if err := db.Unmarshal(raw, targets, role, snapshotMeta.Version); err == nil && targets.Version == snapshotMeta.Version {
+			return targets, nil
+		}
+	}
+
+	raw, err := c.DownloadMeta(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download %q: %w", fileName, err)
+	}
+
+	if len(snapshotMeta.Hashes) > 0 {
+		if err := tufUtil.BytesMatchLenAndHashes(raw, snapshotMeta.Length, snapshotMeta.Hashes); err != nil {
+			return nil, fmt.Errorf("%q does not match snapshot.json: %w", fileName, err)
+		}
+	}
+
+	targets := &data.Targets{}
+	if err := db.Unmarshal(raw, targets, role, snapshotMeta.Version); err != nil {
+		return nil, fmt.Errorf("unable to verify %q: %w", fileName, err)
+	}
+
+	if targets.Version != snapshotMeta.Version {
+		return nil, fmt.Errorf("%q version %d does not match snapshot.json version %d", fileName, targets.Version, snapshotMeta.Version)
+	}
+
+	if err := c.ReadOnlyLocalStore.SetMeta(fileName, raw); err != nil {
+		return nil, fmt.Errorf("unable to set meta: %w", err)
+	}
+
+	return targets, nil
+}
+
+func isTargetPathDelegated(chain []data.DelegatedRole, targetPath string) bool {
+	for _, role := range chain {
+		if matched, err := role.MatchesPath(targetPath); err != nil || !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+func unmarshalSignedMeta(raw json.RawMessage, v interface{}) error {
+	signed := &data.Signed{}
+	if err := json.Unmarshal(raw, signed); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(signed.Signed, v)
+}
@@ -0,0 +1,107 @@
+package util
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteTarArchive writes the regular files of the directory into the tar archive.
+func WriteTarArchive(dir string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+
+	if err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return fmt.Errorf("unable to get relative path of %q: %w", path, err)
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return fmt.Errorf("unable to create tar header for %q: %w", path, err)
+		}
+		header.Name = filepath.ToSlash(relPath)
+
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("unable to write tar header for %q: %w", path, err)
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("unable to open file %q: %w", path, err)
+		}
+		defer func() { _ = f.Close() }()
+
+		if _, err := io.Copy(tw, f); err != nil {
+			return fmt.Errorf("unable to write file %q into tar archive: %w", path, err)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// ExtractTarArchive extracts the regular files of the tar archive into the directory,
+// the files outside the directory are not allowed.
+func ExtractTarArchive(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("unable to read tar archive: %w", err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeReg:
+		default:
+			return fmt.Errorf("unsupported tar archive entry %q type %q", header.Name, string(header.Typeflag))
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("tar archive entry %q is outside the destination directory", header.Name)
+		}
+
+		if err := extractTarArchiveFile(tr, path); err != nil {
+			return err
+		}
+	}
+}
+
+func extractTarArchiveFile(r io.Reader, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("unable to mkdir all %q: %w", filepath.Dir(path), err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to create file %q: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return fmt.Errorf("unable to write file %q: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tarEntry struct {
+	name     string
+	typeflag byte
+	data     string
+}
+
+func newTarArchive(t *testing.T, entries []tarEntry) *bytes.Buffer {
+	buf := bytes.NewBuffer(nil)
+	tw := tar.NewWriter(buf)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Typeflag: entry.typeflag, Mode: 0o644, Size: int64(len(entry.data))}
+		if entry.typeflag == tar.TypeSymlink {
+			header.Linkname, header.Size = entry.data, 0
+		}
+
+		assert.Nil(t, tw.WriteHeader(header))
+		if header.Size != 0 {
+			_, err := tw.Write([]byte(entry.data))
+			assert.Nil(t, err)
+		}
+	}
+	assert.Nil(t, tw.Close())
+
+	return buf
+}
+
+func TestExtractTarArchive(t *testing.T) {
+	dir := t.TempDir()
+	archive := newTarArchive(t, []tarEntry{
+		{name: "metadata/", typeflag: tar.TypeDir},
+		{name: "metadata/root.json", typeflag: tar.TypeReg, data: "root"},
+		{name: "./targets/a/b", typeflag: tar.TypeReg, data: "b"},
+	})
+
+	assert.Nil(t, ExtractTarArchive(archive, dir))
+
+	data, err := os.ReadFile(filepath.Join(dir, "metadata", "root.json"))
+	assert.Nil(t, err)
+	assert.Equal(t, "root", string(data))
+
+	data, err = os.ReadFile(filepath.Join(dir, "targets", "a", "b"))
+	assert.Nil(t, err)
+	assert.Equal(t, "b", string(data))
+}
+
+func TestExtractTarArchive_Rejected(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		entry tarEntry
+	}{
+		{name: "parent directory", entry: tarEntry{name: "../evil", typeflag: tar.TypeReg, data: "evil"}},
+		{name: "nested parent directory", entry: tarEntry{name: "targets/../../evil", typeflag: tar.TypeReg, data: "evil"}},
+		{name: "destination directory", entry: tarEntry{name: ".", typeflag: tar.TypeReg, data: "evil"}},
+		{name: "symlink", entry: tarEntry{name: "link", typeflag: tar.TypeSymlink, data: "/etc/passwd"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			parentDir := t.TempDir()
+			dir := filepath.Join(parentDir, "bundle")
+			assert.Nil(t, os.Mkdir(dir, os.ModePerm))
+
+			err := ExtractTarArchive(newTarArchive(t, []tarEntry{tc.entry}), dir)
+			assert.NotNil(t, err)
+
+			_, err = os.Lstat(filepath.Join(parentDir, "evil"))
+			assert.True(t, os.IsNotExist(err))
+		})
+	}
+}
+
+func TestWriteTarArchive(t *testing.T) {
+	srcDir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(srcDir, "metadata"), os.ModePerm))
+	assert.Nil(t, os.WriteFile(filepath.Join(srcDir, "metadata", "root.json"), []byte("root"), 0o644))
+
+	buf := bytes.NewBuffer(nil)
+	assert.Nil(t, WriteTarArchive(srcDir, buf))
+
+	dstDir := t.TempDir()
+	assert.Nil(t, ExtractTarArchive(buf, dstDir))
+
+	data, err := os.ReadFile(filepath.Join(dstDir, "metadata", "root.json"))
+	assert.Nil(t, err)
+	assert.Equal(t, "root", string(data))
+}
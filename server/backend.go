@@ -75,6 +75,11 @@ func (b *Backend) InitPaths(modules ...BackendModuleInterface) {
 			configurePath(b),
 			releasePath(b),
 			publishPath(b),
+			tufRootPath(b),
+			tufRootKeyPath(b),
+			tufRootPendingPath(b),
+			tufRootSignaturePath(b),
+			statusPath(b),
 		},
 		git.CredentialsPaths(),
 		pgp.Paths(),
@@ -103,6 +108,21 @@ func (b *Backend) InitPeriodicFunc(modules ...BackendModuleInterface) {
 	}
 }
 
+// discardStagedOnError wraps the task so that the changes staged by the failed task are discarded
+// and no staged objects are left in the repository storage.
+func (b *Backend) discardStagedOnError(repository publisher.RepositoryInterface, task func(ctx context.Context, storage logical.Storage) error) func(ctx context.Context, storage logical.Storage) error {
+	return func(ctx context.Context, storage logical.Storage) error {
+		err := task(ctx, storage)
+		if err != nil {
+			if discardErr := repository.DiscardStaged(ctx); discardErr != nil {
+				b.Logger().Error(fmt.Sprintf("Unable to discard staged changes of the failed task: %s", discardErr))
+			}
+		}
+
+		return err
+	}
+}
+
 const (
 	backendHelp = `
 The trdl plugin builds and releases software versions, publishes the release channels, and ensures object storage security via saving data signed by keys (no one has access to those keys) and continuously rotating TUF keys and metadata.
@@ -48,6 +48,9 @@ func (m *MockedTasksManager) RunTask(_ context.Context, _ logical.Storage, _ fun
 type MockedPublisher struct {
 	mock.Mock
 	publisher.Interface
+	Repository publisher.RepositoryInterface
+	// RepositoryOptions are the options of the last GetRepository call
+	RepositoryOptions publisher.RepositoryOptions
 }
 
 func (m *MockedPublisher) Paths() []*framework.Path {
@@ -60,9 +63,31 @@ func (m *MockedPublisher) PeriodicFunc(_ context.Context, _ *logical.Request) er
 	return nil
 }
 
-func (m *MockedPublisher) GetRepository(_ context.Context, _ logical.Storage, _ publisher.RepositoryOptions) (publisher.RepositoryInterface, error) {
+func (m *MockedPublisher) GetRepository(_ context.Context, _ logical.Storage, opts publisher.RepositoryOptions) (publisher.RepositoryInterface, error) {
 	m.Called()
-	return nil, nil
+	m.RepositoryOptions = opts
+	return m.Repository, nil
+}
+
+func (m *MockedPublisher) GetExistingReleases(_ context.Context, _ publisher.RepositoryInterface) ([]string, error) {
+	args := m.Called()
+	return args.Get(0).([]string), nil
+}
+
+type MockedRepository struct {
+	mock.Mock
+	publisher.RepositoryInterface
+	RolesStatus []*publisher.TufRoleStatus
+}
+
+func (m *MockedRepository) GetRootMeta(_ context.Context) ([]byte, int64, error) {
+	m.Called()
+	return []byte("{}"), 1, nil
+}
+
+func (m *MockedRepository) GetRolesStatus(_ context.Context) ([]*publisher.TufRoleStatus, error) {
+	m.Called()
+	return m.RolesStatus, nil
 }
 
 type MockedBackendPeriodic struct {
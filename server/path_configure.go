@@ -19,17 +19,54 @@ const (
 	fieldNameGitTrdlChannelsBranch                      = "git_trdl_channels_branch"
 	fieldNameInitialLastPublishedGitCommit              = "initial_last_published_git_commit"
 	fieldNameRequiredNumberOfVerifiedSignaturesOnCommit = "required_number_of_verified_signatures_on_commit"
+	fieldNameStorageType                                = "storage_type"
+	fieldNameLocalStoragePath                           = "local_storage_path"
 	fieldNameS3Endpoint                                 = "s3_endpoint"
 	fieldNameS3Region                                   = "s3_region"
 	fieldNameS3AccessKeyID                              = "s3_access_key_id"
 	fieldNameS3SecretAccessKey                          = "s3_secret_access_key"
 	fieldNameS3BucketName                               = "s3_bucket_name"
+	fieldNameAtomicTufStore                             = "atomic_tuf_store"
+	fieldNameTufRootExpiration                          = "tuf_root_expiration"
+	fieldNameTufRootRotationPeriod                      = "tuf_root_rotation_period"
+	fieldNameTufTargetsExpiration                       = "tuf_targets_expiration"
+	fieldNameTufTargetsRotationPeriod                   = "tuf_targets_rotation_period"
+	fieldNameTufSnapshotExpiration                      = "tuf_snapshot_expiration"
+	fieldNameTufSnapshotRotationPeriod                  = "tuf_snapshot_rotation_period"
+	fieldNameTufTimestampExpiration                     = "tuf_timestamp_expiration"
+	fieldNameTufTimestampRotationPeriod                 = "tuf_timestamp_rotation_period"
+	fieldNameTufRootKeys                                = "tuf_root_keys"
+	fieldNameTufRootThreshold                           = "tuf_root_threshold"
+	fieldNameTufTargetsKeys                             = "tuf_targets_keys"
+	fieldNameTufTargetsThreshold                        = "tuf_targets_threshold"
+	fieldNameTufSnapshotKeys                            = "tuf_snapshot_keys"
+	fieldNameTufSnapshotThreshold                       = "tuf_snapshot_threshold"
+	fieldNameTufTimestampKeys                           = "tuf_timestamp_keys"
+	fieldNameTufTimestampThreshold                      = "tuf_timestamp_threshold"
+	fieldNameTufSigner                                  = "tuf_signer"
+	fieldNameTufSignerVaultAddress                      = "tuf_signer_vault_address"
+	fieldNameTufSignerVaultToken                        = "tuf_signer_vault_token"
+	fieldNameTufSignerVaultTransitMountPath             = "tuf_signer_vault_transit_mount_path"
+	fieldNameTargetPlatforms                            = "target_platforms"
 
 	storageKeyConfiguration = "configuration"
 )
 
 var errorResponseConfigurationNotFound = logical.ErrorResponse("Configuration not found")
 
+var s3StorageRequiredFields = []string{
+	fieldNameS3BucketName,
+	fieldNameS3Endpoint,
+	fieldNameS3Region,
+	fieldNameS3AccessKeyID,
+	fieldNameS3SecretAccessKey,
+}
+
+var vaultTransitSignerRequiredFields = []string{
+	fieldNameTufSignerVaultAddress,
+	fieldNameTufSignerVaultToken,
+}
+
 func configurePath(b *Backend) *framework.Path {
 	return &framework.Path{
 		Pattern:      "configure/?",
@@ -65,30 +102,173 @@ func configurePath(b *Backend) *framework.Path {
 				Description: "The required number of verified signatures for a commit",
 				Required:    true,
 			},
+			fieldNameStorageType: {
+				Type:          framework.TypeString,
+				Description:   "The storage type of the TUF repository: s3 or local (the directory which can be served by any static web server)",
+				Default:       publisher.StorageTypeS3,
+				AllowedValues: []interface{}{publisher.StorageTypeS3, publisher.StorageTypeLocal},
+				Required:      false,
+			},
+			fieldNameLocalStoragePath: {
+				Type:        framework.TypeString,
+				Description: "The path to the TUF repository directory (required for the local storage type)",
+				Required:    false,
+			},
 			fieldNameS3BucketName: {
 				Type:        framework.TypeString,
-				Description: "The S3 storage bucket name",
-				Required:    true,
+				Description: "The S3 storage bucket name (required for the s3 storage type)",
+				Required:    false,
 			},
 			fieldNameS3Endpoint: {
 				Type:        framework.TypeString,
-				Description: "The S3 storage endpoint",
-				Required:    true,
+				Description: "The S3 storage endpoint (required for the s3 storage type)",
+				Required:    false,
 			},
 			fieldNameS3Region: {
 				Type:        framework.TypeString,
-				Description: "The S3 storage region",
-				Required:    true,
+				Description: "The S3 storage region (required for the s3 storage type)",
+				Required:    false,
 			},
 			fieldNameS3AccessKeyID: {
 				Type:        framework.TypeString,
-				Description: "The S3 storage access key id",
-				Required:    true,
+				Description: "The S3 storage access key id (required for the s3 storage type)",
+				Required:    false,
 			},
 			fieldNameS3SecretAccessKey: {
 				Type:        framework.TypeString,
-				Description: "The S3 storage access key id",
-				Required:    true,
+				Description: "The S3 storage access key id (required for the s3 storage type)",
+				Required:    false,
+			},
+			fieldNameAtomicTufStore: {
+				Type:        framework.TypeBool,
+				Description: "Stage release targets under content-addressed names and publish them together with the TUF metadata on commit, so that a failed release does not leave the repository in a half-published state. The new repository is initialized with consistent snapshots: the published targets and metadata are never overwritten, such repository cannot be switched back to the non-atomic store",
+				Default:     false,
+				Required:    false,
+			},
+			fieldNameTufRootExpiration: {
+				Type:        framework.TypeString,
+				Description: "The root.json TUF metadata expiration period (e.g. 1y, 3mo, 3w, 7d, 4h)",
+				Default:     "1y",
+				Required:    false,
+			},
+			fieldNameTufRootRotationPeriod: {
+				Type:        framework.TypeString,
+				Description: "The root.json TUF metadata rotation period, must be shorter than the expiration period",
+				Default:     "3mo",
+				Required:    false,
+			},
+			fieldNameTufTargetsExpiration: {
+				Type:        framework.TypeString,
+				Description: "The targets.json TUF metadata expiration period (e.g. 1y, 3mo, 3w, 7d, 4h)",
+				Default:     "3mo",
+				Required:    false,
+			},
+			fieldNameTufTargetsRotationPeriod: {
+				Type:        framework.TypeString,
+				Description: "The targets.json TUF metadata rotation period, must be shorter than the expiration period",
+				Default:     "21d",
+				Required:    false,
+			},
+			fieldNameTufSnapshotExpiration: {
+				Type:        framework.TypeString,
+				Description: "The snapshot.json TUF metadata expiration period (e.g. 1y, 3mo, 3w, 7d, 4h)",
+				Default:     "7d",
+				Required:    false,
+			},
+			fieldNameTufSnapshotRotationPeriod: {
+				Type:        framework.TypeString,
+				Description: "The snapshot.json TUF metadata rotation period, must be shorter than the expiration period",
+				Default:     "2d",
+				Required:    false,
+			},
+			fieldNameTufTimestampExpiration: {
+				Type:        framework.TypeString,
+				Description: "The timestamp.json TUF metadata expiration period (e.g. 1y, 3mo, 3w, 7d, 4h)",
+				Default:     "1d",
+				Required:    false,
+			},
+			fieldNameTufTimestampRotationPeriod: {
+				Type:        framework.TypeString,
+				Description: "The timestamp.json TUF metadata rotation period, must be shorter than the expiration period",
+				Default:     "4h",
+				Required:    false,
+			},
+			fieldNameTufRootKeys: {
+				Type:        framework.TypeInt,
+				Description: "The number of the root keys generated and kept online by the server",
+				Default:     1,
+				Required:    false,
+			},
+			fieldNameTufRootThreshold: {
+				Type:        framework.TypeInt,
+				Description: "The number of the root keys required to sign root.json. When it exceeds the number of online root keys, root.json changes are signed by the offline root keys added by the tuf/root/key path",
+				Default:     1,
+				Required:    false,
+			},
+			fieldNameTufTargetsKeys: {
+				Type:        framework.TypeInt,
+				Description: "The number of the targets keys generated and kept online by the server",
+				Default:     1,
+				Required:    false,
+			},
+			fieldNameTufTargetsThreshold: {
+				Type:        framework.TypeInt,
+				Description: "The number of the targets keys required to sign targets.json, must not exceed the number of targets keys",
+				Default:     1,
+				Required:    false,
+			},
+			fieldNameTufSnapshotKeys: {
+				Type:        framework.TypeInt,
+				Description: "The number of the snapshot keys generated and kept online by the server",
+				Default:     1,
+				Required:    false,
+			},
+			fieldNameTufSnapshotThreshold: {
+				Type:        framework.TypeInt,
+				Description: "The number of the snapshot keys required to sign snapshot.json, must not exceed the number of snapshot keys",
+				Default:     1,
+				Required:    false,
+			},
+			fieldNameTufTimestampKeys: {
+				Type:        framework.TypeInt,
+				Description: "The number of the timestamp keys generated and kept online by the server",
+				Default:     1,
+				Required:    false,
+			},
+			fieldNameTufTimestampThreshold: {
+				Type:        framework.TypeInt,
+				Description: "The number of the timestamp keys required to sign timestamp.json, must not exceed the number of timestamp keys",
+				Default:     1,
+				Required:    false,
+			},
+			fieldNameTufSigner: {
+				Type:          framework.TypeString,
+				Description:   "Where the new TUF keys are generated and used for signing: local (the private keys are kept in the plugin storage) or vault_transit (the keys never leave the Vault Transit secrets engine). The already generated keys are used until rotated",
+				Default:       publisher.SignerTypeLocal,
+				AllowedValues: []interface{}{publisher.SignerTypeLocal, publisher.SignerTypeVaultTransit},
+				Required:      false,
+			},
+			fieldNameTufSignerVaultAddress: {
+				Type:        framework.TypeString,
+				Description: "The address of the Vault server with the Transit secrets engine enabled (required for the vault_transit signer)",
+				Required:    false,
+			},
+			fieldNameTufSignerVaultToken: {
+				Type:        framework.TypeString,
+				Description: "The Vault token allowed to create and read keys and to sign data by the Transit secrets engine (required for the vault_transit signer, not returned on read)",
+				Required:    false,
+			},
+			fieldNameTufSignerVaultTransitMountPath: {
+				Type:        framework.TypeString,
+				Description: "The mount path of the Transit secrets engine",
+				Default:     "transit",
+				Required:    false,
+			},
+			fieldNameTargetPlatforms: {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "The platforms of the release artifacts in format <os>/<arch>[/<variant>] (e.g. linux/arm/v7), the artifacts are placed into the <os>-<arch>[-<variant>] directories",
+				Default:     publisher.DefaultTargetPlatforms,
+				Required:    false,
 			},
 		},
 		Operations: map[logical.Operation]framework.OperationHandler{
@@ -113,10 +293,80 @@ func configurePath(b *Backend) *framework.Path {
 }
 
 func (b *Backend) pathConfigureCreateOrUpdate(ctx context.Context, req *logical.Request, fields *framework.FieldData) (*logical.Response, error) {
-	if errResp := util.CheckRequiredFields(req, fields); errResp != nil {
+	if errResp := util.CheckRequiredFields(fields); errResp != nil {
 		return errResp, nil
 	}
 
+	storageType := fields.Get(fieldNameStorageType).(string)
+	switch storageType {
+	case publisher.StorageTypeS3:
+		for _, fieldName := range s3StorageRequiredFields {
+			if req.Get(fieldName) == nil {
+				return logical.ErrorResponse("Required field %q must be set", fieldName), nil
+			}
+		}
+	case publisher.StorageTypeLocal:
+		if req.Get(fieldNameLocalStoragePath) == nil {
+			return logical.ErrorResponse("Required field %q must be set", fieldNameLocalStoragePath), nil
+		}
+	default:
+		return logical.ErrorResponse("Field %q must be one of: %s, %s", fieldNameStorageType, publisher.StorageTypeS3, publisher.StorageTypeLocal), nil
+	}
+
+	tufSigner := fields.Get(fieldNameTufSigner).(string)
+	switch tufSigner {
+	case publisher.SignerTypeLocal:
+	case publisher.SignerTypeVaultTransit:
+		for _, fieldName := range vaultTransitSignerRequiredFields {
+			if req.Get(fieldName) == nil {
+				return logical.ErrorResponse("Required field %q must be set", fieldName), nil
+			}
+		}
+	default:
+		return logical.ErrorResponse("Field %q must be one of: %s, %s", fieldNameTufSigner, publisher.SignerTypeLocal, publisher.SignerTypeVaultTransit), nil
+	}
+
+	var tufRepoLifetimes publisher.TufRepoLifetimes
+	for _, period := range []struct {
+		fieldName string
+		dst       *publisher.Period
+	}{
+		{fieldNameTufRootExpiration, &tufRepoLifetimes.Root.Expiration},
+		{fieldNameTufRootRotationPeriod, &tufRepoLifetimes.Root.RotationPeriod},
+		{fieldNameTufTargetsExpiration, &tufRepoLifetimes.Targets.Expiration},
+		{fieldNameTufTargetsRotationPeriod, &tufRepoLifetimes.Targets.RotationPeriod},
+		{fieldNameTufSnapshotExpiration, &tufRepoLifetimes.Snapshot.Expiration},
+		{fieldNameTufSnapshotRotationPeriod, &tufRepoLifetimes.Snapshot.RotationPeriod},
+		{fieldNameTufTimestampExpiration, &tufRepoLifetimes.Timestamp.Expiration},
+		{fieldNameTufTimestampRotationPeriod, &tufRepoLifetimes.Timestamp.RotationPeriod},
+	} {
+		p, err := publisher.ParsePeriod(fields.Get(period.fieldName).(string))
+		if err != nil {
+			return logical.ErrorResponse("Field %q is invalid: %s", period.fieldName, err), nil
+		}
+		*period.dst = p
+	}
+
+	if err := tufRepoLifetimes.Validate(periodicRunPeriod); err != nil {
+		return logical.ErrorResponse("Invalid TUF metadata lifetimes: %s", err), nil
+	}
+
+	tufRepoRolesKeys := publisher.TufRepoRolesKeys{
+		Root:      publisher.TufRoleKeys{Keys: fields.Get(fieldNameTufRootKeys).(int), Threshold: fields.Get(fieldNameTufRootThreshold).(int)},
+		Targets:   publisher.TufRoleKeys{Keys: fields.Get(fieldNameTufTargetsKeys).(int), Threshold: fields.Get(fieldNameTufTargetsThreshold).(int)},
+		Snapshot:  publisher.TufRoleKeys{Keys: fields.Get(fieldNameTufSnapshotKeys).(int), Threshold: fields.Get(fieldNameTufSnapshotThreshold).(int)},
+		Timestamp: publisher.TufRoleKeys{Keys: fields.Get(fieldNameTufTimestampKeys).(int), Threshold: fields.Get(fieldNameTufTimestampThreshold).(int)},
+	}
+
+	if err := tufRepoRolesKeys.Validate(); err != nil {
+		return logical.ErrorResponse("Invalid TUF roles keys: %s", err), nil
+	}
+
+	targetPlatforms := fields.Get(fieldNameTargetPlatforms).([]string)
+	if _, err := publisher.ParsePlatforms(targetPlatforms); err != nil {
+		return logical.ErrorResponse("Field %q is invalid: %s", fieldNameTargetPlatforms, err), nil
+	}
+
 	cfg := &configuration{
 		GitRepoUrl:                    fields.Get(fieldNameGitRepoUrl).(string),
 		GitTrdlPath:                   fields.Get(fieldNameGitTrdlPath).(string),
@@ -124,11 +374,35 @@ func (b *Backend) pathConfigureCreateOrUpdate(ctx context.Context, req *logical.
 		GitTrdlChannelsBranch:         fields.Get(fieldNameGitTrdlChannelsBranch).(string),
 		InitialLastPublishedGitCommit: fields.Get(fieldNameInitialLastPublishedGitCommit).(string),
 		RequiredNumberOfVerifiedSignaturesOnCommit: fields.Get(fieldNameRequiredNumberOfVerifiedSignaturesOnCommit).(int),
-		S3Endpoint:        fields.Get(fieldNameS3Endpoint).(string),
-		S3Region:          fields.Get(fieldNameS3Region).(string),
-		S3AccessKeyID:     fields.Get(fieldNameS3AccessKeyID).(string),
-		S3SecretAccessKey: fields.Get(fieldNameS3SecretAccessKey).(string),
-		S3BucketName:      fields.Get(fieldNameS3BucketName).(string),
+		StorageType:                    storageType,
+		LocalStoragePath:               fields.Get(fieldNameLocalStoragePath).(string),
+		S3Endpoint:                     fields.Get(fieldNameS3Endpoint).(string),
+		S3Region:                       fields.Get(fieldNameS3Region).(string),
+		S3AccessKeyID:                  fields.Get(fieldNameS3AccessKeyID).(string),
+		S3SecretAccessKey:              fields.Get(fieldNameS3SecretAccessKey).(string),
+		S3BucketName:                   fields.Get(fieldNameS3BucketName).(string),
+		AtomicTufStore:                 fields.Get(fieldNameAtomicTufStore).(bool),
+		TufRootExpiration:              tufRepoLifetimes.Root.Expiration,
+		TufRootRotationPeriod:          tufRepoLifetimes.Root.RotationPeriod,
+		TufTargetsExpiration:           tufRepoLifetimes.Targets.Expiration,
+		TufTargetsRotationPeriod:       tufRepoLifetimes.Targets.RotationPeriod,
+		TufSnapshotExpiration:          tufRepoLifetimes.Snapshot.Expiration,
+		TufSnapshotRotationPeriod:      tufRepoLifetimes.Snapshot.RotationPeriod,
+		TufTimestampExpiration:         tufRepoLifetimes.Timestamp.Expiration,
+		TufTimestampRotationPeriod:     tufRepoLifetimes.Timestamp.RotationPeriod,
+		TufRootKeys:                    tufRepoRolesKeys.Root.Keys,
+		TufRootThreshold:               tufRepoRolesKeys.Root.Threshold,
+		TufTargetsKeys:                 tufRepoRolesKeys.Targets.Keys,
+		TufTargetsThreshold:            tufRepoRolesKeys.Targets.Threshold,
+		TufSnapshotKeys:                tufRepoRolesKeys.Snapshot.Keys,
+		TufSnapshotThreshold:           tufRepoRolesKeys.Snapshot.Threshold,
+		TufTimestampKeys:               tufRepoRolesKeys.Timestamp.Keys,
+		TufTimestampThreshold:          tufRepoRolesKeys.Timestamp.Threshold,
+		TufSigner:                      tufSigner,
+		TufSignerVaultAddress:          fields.Get(fieldNameTufSignerVaultAddress).(string),
+		TufSignerVaultToken:            fields.Get(fieldNameTufSignerVaultToken).(string),
+		TufSignerVaultTransitMountPath: fields.Get(fieldNameTufSignerVaultTransitMountPath).(string),
+		TargetPlatforms:                targetPlatforms,
 	}
 
 	if err := putConfiguration(ctx, req.Storage, cfg); err != nil {
@@ -148,7 +422,11 @@ func (b *Backend) pathConfigureRead(ctx context.Context, req *logical.Request, _
 		return errorResponseConfigurationNotFound, nil
 	}
 
-	return &logical.Response{Data: structs.Map(cfg)}, nil
+	// The vault token is write-only
+	data := structs.Map(cfg)
+	delete(data, fieldNameTufSignerVaultToken)
+
+	return &logical.Response{Data: data}, nil
 }
 
 func (b *Backend) pathConfigureDelete(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
@@ -160,26 +438,73 @@ func (b *Backend) pathConfigureDelete(ctx context.Context, req *logical.Request,
 }
 
 type configuration struct {
-	GitRepoUrl                                 string `structs:"git_repo_url" json:"git_repo_url"`
-	GitTrdlPath                                string `structs:"git_trdl_path" json:"git_trdl_path"`
-	GitTrdlChannelsPath                        string `structs:"git_trdl_channels_path" json:"git_trdl_channels_path"`
-	GitTrdlChannelsBranch                      string `structs:"git_trdl_channels_branch" json:"git_trdl_channels_branch"`
-	InitialLastPublishedGitCommit              string `structs:"initial_last_published_git_commit" json:"initial_last_published_git_commit"`
-	RequiredNumberOfVerifiedSignaturesOnCommit int    `structs:"required_number_of_verified_signatures_on_commit" json:"required_number_of_verified_signatures_on_commit"`
-	S3Endpoint                                 string `structs:"s3_endpoint" json:"s3_endpoint"`
-	S3Region                                   string `structs:"s3_region" json:"s3_region"`
-	S3AccessKeyID                              string `structs:"s3_access_key_id" json:"s3_access_key_id"`
-	S3SecretAccessKey                          string `structs:"s3_secret_access_key" json:"s3_secret_access_key"`
-	S3BucketName                               string `structs:"s3_bucket_name" json:"s3_bucket_name"`
+	GitRepoUrl                                 string           `structs:"git_repo_url" json:"git_repo_url"`
+	GitTrdlPath                                string           `structs:"git_trdl_path" json:"git_trdl_path"`
+	GitTrdlChannelsPath                        string           `structs:"git_trdl_channels_path" json:"git_trdl_channels_path"`
+	GitTrdlChannelsBranch                      string           `structs:"git_trdl_channels_branch" json:"git_trdl_channels_branch"`
+	InitialLastPublishedGitCommit              string           `structs:"initial_last_published_git_commit" json:"initial_last_published_git_commit"`
+	RequiredNumberOfVerifiedSignaturesOnCommit int              `structs:"required_number_of_verified_signatures_on_commit" json:"required_number_of_verified_signatures_on_commit"`
+	StorageType                                string           `structs:"storage_type" json:"storage_type"`
+	LocalStoragePath                           string           `structs:"local_storage_path" json:"local_storage_path"`
+	S3Endpoint                                 string           `structs:"s3_endpoint" json:"s3_endpoint"`
+	S3Region                                   string           `structs:"s3_region" json:"s3_region"`
+	S3AccessKeyID                              string           `structs:"s3_access_key_id" json:"s3_access_key_id"`
+	S3SecretAccessKey                          string           `structs:"s3_secret_access_key" json:"s3_secret_access_key"`
+	S3BucketName                               string           `structs:"s3_bucket_name" json:"s3_bucket_name"`
+	AtomicTufStore                             bool             `structs:"atomic_tuf_store" json:"atomic_tuf_store"`
+	TufRootExpiration                          publisher.Period `structs:"tuf_root_expiration,string" json:"tuf_root_expiration"`
+	TufRootRotationPeriod                      publisher.Period `structs:"tuf_root_rotation_period,string" json:"tuf_root_rotation_period"`
+	TufTargetsExpiration                       publisher.Period `structs:"tuf_targets_expiration,string" json:"tuf_targets_expiration"`
+	TufTargetsRotationPeriod                   publisher.Period `structs:"tuf_targets_rotation_period,string" json:"tuf_targets_rotation_period"`
+	TufSnapshotExpiration                      publisher.Period `structs:"tuf_snapshot_expiration,string" json:"tuf_snapshot_expiration"`
+	TufSnapshotRotationPeriod                  publisher.Period `structs:"tuf_snapshot_rotation_period,string" json:"tuf_snapshot_rotation_period"`
+	TufTimestampExpiration                     publisher.Period `structs:"tuf_timestamp_expiration,string" json:"tuf_timestamp_expiration"`
+	TufTimestampRotationPeriod                 publisher.Period `structs:"tuf_timestamp_rotation_period,string" json:"tuf_timestamp_rotation_period"`
+	TufRootKeys                                int              `structs:"tuf_root_keys" json:"tuf_root_keys"`
+	TufRootThreshold                           int              `structs:"tuf_root_threshold" json:"tuf_root_threshold"`
+	TufTargetsKeys                             int              `structs:"tuf_targets_keys" json:"tuf_targets_keys"`
+	TufTargetsThreshold                        int              `structs:"tuf_targets_threshold" json:"tuf_targets_threshold"`
+	TufSnapshotKeys                            int              `structs:"tuf_snapshot_keys" json:"tuf_snapshot_keys"`
+	TufSnapshotThreshold                       int              `structs:"tuf_snapshot_threshold" json:"tuf_snapshot_threshold"`
+	TufTimestampKeys                           int              `structs:"tuf_timestamp_keys" json:"tuf_timestamp_keys"`
+	TufTimestampThreshold                      int              `structs:"tuf_timestamp_threshold" json:"tuf_timestamp_threshold"`
+	TufSigner                                  string           `structs:"tuf_signer" json:"tuf_signer"`
+	TufSignerVaultAddress                      string           `structs:"tuf_signer_vault_address" json:"tuf_signer_vault_address"`
+	TufSignerVaultToken                        string           `structs:"tuf_signer_vault_token" json:"tuf_signer_vault_token"`
+	TufSignerVaultTransitMountPath             string           `structs:"tuf_signer_vault_transit_mount_path" json:"tuf_signer_vault_transit_mount_path"`
+	TargetPlatforms                            []string         `structs:"target_platforms" json:"target_platforms"`
 }
 
 func (cfg *configuration) RepositoryOptions() publisher.RepositoryOptions {
 	return publisher.RepositoryOptions{
+		StorageType:       cfg.StorageType,
+		LocalStoragePath:  cfg.LocalStoragePath,
 		S3Endpoint:        cfg.S3Endpoint,
 		S3Region:          cfg.S3Region,
 		S3AccessKeyID:     cfg.S3AccessKeyID,
 		S3SecretAccessKey: cfg.S3SecretAccessKey,
 		S3BucketName:      cfg.S3BucketName,
+		AtomicTufStore:    cfg.AtomicTufStore,
+		TufRepoLifetimes: publisher.TufRepoLifetimes{
+			Root:      publisher.TufRoleLifetime{Expiration: cfg.TufRootExpiration, RotationPeriod: cfg.TufRootRotationPeriod},
+			Targets:   publisher.TufRoleLifetime{Expiration: cfg.TufTargetsExpiration, RotationPeriod: cfg.TufTargetsRotationPeriod},
+			Snapshot:  publisher.TufRoleLifetime{Expiration: cfg.TufSnapshotExpiration, RotationPeriod: cfg.TufSnapshotRotationPeriod},
+			Timestamp: publisher.TufRoleLifetime{Expiration: cfg.TufTimestampExpiration, RotationPeriod: cfg.TufTimestampRotationPeriod},
+		},
+		TufRepoRolesKeys: publisher.TufRepoRolesKeys{
+			Root:      publisher.TufRoleKeys{Keys: cfg.TufRootKeys, Threshold: cfg.TufRootThreshold},
+			Targets:   publisher.TufRoleKeys{Keys: cfg.TufTargetsKeys, Threshold: cfg.TufTargetsThreshold},
+			Snapshot:  publisher.TufRoleKeys{Keys: cfg.TufSnapshotKeys, Threshold: cfg.TufSnapshotThreshold},
+			Timestamp: publisher.TufRoleKeys{Keys: cfg.TufTimestampKeys, Threshold: cfg.TufTimestampThreshold},
+		},
+		TufSigner: publisher.TufSignerOptions{
+			Type: cfg.TufSigner,
+			VaultTransit: publisher.VaultTransitOptions{
+				Address:   cfg.TufSignerVaultAddress,
+				Token:     cfg.TufSignerVaultToken,
+				MountPath: cfg.TufSignerVaultTransitMountPath,
+			},
+		},
 	}
 }
 
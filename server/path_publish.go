@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/go-git/go-git/v5"
@@ -11,6 +12,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/samber/lo"
 	"gopkg.in/yaml.v2"
 
 	"github.com/werf/logboek"
@@ -24,6 +26,8 @@ import (
 
 const (
 	storageKeyLastPublishedGitCommit = "last_published_git_commit"
+
+	rollbackCommitTrailer = "Trdl-Rollback"
 )
 
 func NewErrPublishingNonExistingReleases(releases []string) error {
@@ -61,7 +65,7 @@ func publishPath(b *Backend) *framework.Path {
 }
 
 func (b *Backend) pathPublish(ctx context.Context, req *logical.Request, fields *framework.FieldData) (*logical.Response, error) {
-	if errResp := util.CheckRequiredFields(req, fields); errResp != nil {
+	if errResp := util.CheckRequiredFields(fields); errResp != nil {
 		return errResp, nil
 	}
 
@@ -106,7 +110,7 @@ func (b *Backend) pathPublish(ctx context.Context, req *logical.Request, fields
 		return nil, fmt.Errorf("error getting publisher repository: %w", err)
 	}
 
-	taskUUID, err := b.TasksManager.RunTask(context.Background(), req.Storage, func(ctx context.Context, storage logical.Storage) error {
+	taskUUID, err := b.TasksManager.RunTask(context.Background(), req.Storage, b.discardStagedOnError(publisherRepository, func(ctx context.Context, storage logical.Storage) error {
 		logboek.Context(ctx).Default().LogF("Started task\n")
 		b.Logger().Debug("Started task")
 
@@ -173,16 +177,50 @@ func (b *Backend) pathPublish(ctx context.Context, req *logical.Request, fields
 		logboek.Context(ctx).Default().LogF("Got trdl channels config:\n%s\n---\n", cfgDump)
 		b.Logger().Debug(fmt.Sprintf("Got trdl channels config:\n%s\n---", cfgDump))
 
-		if err := ValidatePublishConfig(ctx, b.Publisher, publisherRepository, cfg, b.Logger()); err != nil {
+		headCommitMessage, err := trdlGit.GetCommitMessage(gitRepo, headCommit)
+		if err != nil {
+			return err
+		}
+		rollback := IsRollbackCommitMessage(headCommitMessage)
+
+		channelsHistory, err := b.Publisher.GetChannelsHistory(ctx, publisherRepository)
+		if err != nil {
+			return fmt.Errorf("unable to get channels history: %w", err)
+		}
+
+		if err := ValidatePublishConfig(ctx, b.Publisher, publisherRepository, cfg, channelsHistory, rollback, b.Logger()); err != nil {
 			return fmt.Errorf("unable to publish bad config: %w", err)
 		}
 
+		logboek.Context(ctx).Default().LogF("Updating TUF repository delegated roles\n")
+		b.Logger().Debug("Updating TUF repository delegated roles")
+
+		if err := b.Publisher.UpdateDelegatedRoles(ctx, storage, publisherRepository); err != nil {
+			return fmt.Errorf("unable to update TUF repository delegated roles: %w", err)
+		}
+
 		logboek.Context(ctx).Default().LogF("Publishing trdl channels config into the TUF repository\n")
 		b.Logger().Debug("Publishing trdl channels config into the TUF repository")
 		if err := b.Publisher.StageChannelsConfig(ctx, publisherRepository, cfg); err != nil {
 			return fmt.Errorf("error publishing trdl channels into the repository: %w", err)
 		}
 
+		if channelsHistory.Update(cfg, headCommit, rollback, SystemClock.Now()) {
+			logboek.Context(ctx).Default().LogF("Publishing channels history into the TUF repository\n")
+			b.Logger().Debug("Publishing channels history into the TUF repository")
+
+			if err := b.Publisher.StageChannelsHistory(ctx, publisherRepository, channelsHistory); err != nil {
+				return fmt.Errorf("error publishing channels history into the repository: %w", err)
+			}
+		}
+
+		logboek.Context(ctx).Default().LogF("Publishing releases index into the TUF repository\n")
+		b.Logger().Debug("Publishing releases index into the TUF repository")
+
+		if err := b.Publisher.SyncReleasesIndex(ctx, publisherRepository); err != nil {
+			return fmt.Errorf("error publishing releases index into the repository: %w", err)
+		}
+
 		logboek.Context(ctx).Default().LogF("Committing TUF repository state\n")
 		b.Logger().Debug("Committing TUF repository state")
 
@@ -201,7 +239,7 @@ func (b *Backend) pathPublish(ctx context.Context, req *logical.Request, fields
 		b.Logger().Debug("Task finished")
 
 		return nil
-	})
+	}))
 	if err != nil {
 		if err == tasks_manager.ErrBusy {
 			return logical.ErrorResponse("busy"), nil
@@ -221,7 +259,7 @@ func (b *Backend) pathPublish(ctx context.Context, req *logical.Request, fields
 	}, nil
 }
 
-func ValidatePublishConfig(ctx context.Context, publisher publisher.Interface, publisherRepository publisher.RepositoryInterface, config *config.TrdlChannels, logger hclog.Logger) error {
+func ValidatePublishConfig(ctx context.Context, publisher publisher.Interface, publisherRepository publisher.RepositoryInterface, config *config.TrdlChannels, channelsHistory *publisher.ChannelsHistory, rollback bool, logger hclog.Logger) error {
 	existingReleases, err := publisher.GetExistingReleases(ctx, publisherRepository)
 	if err != nil {
 		return fmt.Errorf("error getting existing targets: %w", err)
@@ -230,6 +268,10 @@ func ValidatePublishConfig(ctx context.Context, publisher publisher.Interface, p
 	logboek.Context(ctx).Default().LogF("Got existing releases list: %v\n", existingReleases)
 	logger.Debug(fmt.Sprintf("Got existing releases list: %v\n", existingReleases))
 
+	if err := config.ValidateChannelSets(); err != nil {
+		return err
+	}
+
 	var nonExistingReleases []string
 
 	processedGroups := map[string]bool{}
@@ -244,6 +286,7 @@ func ValidatePublishConfig(ctx context.Context, publisher publisher.Interface, p
 		}
 
 		processedChannels := map[string]bool{}
+		allowedChannels := config.GetGroupAllowedChannels(group)
 
 		for _, channel := range group.Channels {
 			logboek.Context(ctx).Default().LogF("Validating channel %q version %q\n", channel.Name, channel.Version)
@@ -252,10 +295,8 @@ func ValidatePublishConfig(ctx context.Context, publisher publisher.Interface, p
 				return fmt.Errorf("duplicate channel %q found within group %q", channel.Name, group.Name)
 			}
 
-			switch channel.Name {
-			case "alpha", "beta", "ea", "stable", "rock-solid":
-			default:
-				return NewErrIncorrectChannelName(channel.Name)
+			if !lo.Contains(allowedChannels, channel.Name) {
+				return NewErrIncorrectChannelName(channel.Name, allowedChannels)
 			}
 
 			if err := ValidateReleaseVersion(channel.Version); err != nil {
@@ -299,11 +340,97 @@ func ValidatePublishConfig(ctx context.Context, publisher publisher.Interface, p
 		return NewErrPublishingNonExistingReleases(nonExistingReleases)
 	}
 
+	return ValidatePromotionPolicy(ctx, config, channelsHistory, rollback, logger)
+}
+
+// ValidatePromotionPolicy checks the channels changes against the published channels history.
+// The policy is not enforced until the channels history is published for the first time.
+func ValidatePromotionPolicy(ctx context.Context, config *config.TrdlChannels, channelsHistory *publisher.ChannelsHistory, rollback bool, logger hclog.Logger) error {
+	policy := config.PromotionPolicy
+	if policy == nil {
+		return nil
+	}
+
+	if channelsHistory.IsEmpty() {
+		logboek.Context(ctx).Default().LogF("WARNING: No channels history published: promotion policy is not enforced\n")
+		logger.Warn("No channels history published: promotion policy is not enforced")
+		return nil
+	}
+
+	now := SystemClock.Now()
+
+	for _, group := range config.Groups {
+		for _, channel := range group.Channels {
+			current := channelsHistory.GetCurrentEntry(group.Name, channel.Name)
+			if current != nil && current.Version == channel.Version {
+				continue
+			}
+
+			if policy.ForbidDowngrade && current != nil {
+				newVersion, err := semver.NewVersion(channel.Version)
+				if err != nil {
+					return fmt.Errorf("bad version %q for channel %q: %w", channel.Version, channel.Name, err)
+				}
+
+				// the history is published by trdl, so the version is expected to be valid
+				currentVersion, err := semver.NewVersion(current.Version)
+				if err != nil {
+					return fmt.Errorf("bad published version %q for channel %q: %w", current.Version, channel.Name, err)
+				}
+
+				if newVersion.LessThan(currentVersion) {
+					if !rollback {
+						return NewErrChannelDowngrade(group.Name, channel.Name, current.Version, channel.Version)
+					}
+
+					logboek.Context(ctx).Default().LogF("Rolling back channel %q from %q to %q (group: %q)\n", channel.Name, current.Version, channel.Version, group.Name)
+					logger.Info(fmt.Sprintf("Rolling back channel %q from %q to %q (group: %q)", channel.Name, current.Version, channel.Version, group.Name))
+				}
+			}
+
+			for _, rule := range policy.Rules {
+				if rule.Channel != channel.Name {
+					continue
+				}
+
+				minDuration := time.Duration(rule.MinDays) * 24 * time.Hour
+				duration := channelsHistory.GetVersionDuration(group.Name, rule.FromChannel, channel.Version, now)
+				if duration == 0 || duration < minDuration {
+					return NewErrChannelPromotionPolicy(group.Name, channel.Name, channel.Version, rule)
+				}
+			}
+		}
+	}
+
 	return nil
 }
 
-func NewErrIncorrectChannelName(chnl string) error {
-	return fmt.Errorf(`got incorrect channel name %q: expected "alpha", "beta", "ea", "stable" or "rock-solid"`, chnl)
+func NewErrChannelDowngrade(group, channel, currentVersion, version string) error {
+	return fmt.Errorf("cannot move channel %q from %q to the lower version %q (group: %q): mark the commit with the %q trailer to roll back", channel, currentVersion, version, group, rollbackCommitTrailer+": true")
+}
+
+func NewErrChannelPromotionPolicy(group, channel, version string, rule config.TrdlPromotionRule) error {
+	return fmt.Errorf("cannot publish version %q to channel %q (group: %q): the version must be published to channel %q for at least %d days", version, channel, group, rule.FromChannel, rule.MinDays)
+}
+
+// IsRollbackCommitMessage checks the commit message has the rollback trailer, e.g. "Trdl-Rollback: true".
+func IsRollbackCommitMessage(message string) bool {
+	for _, line := range strings.Split(message, "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		if strings.EqualFold(strings.TrimSpace(parts[0]), rollbackCommitTrailer) && strings.EqualFold(strings.TrimSpace(parts[1]), "true") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewErrIncorrectChannelName(chnl string, allowedChannels []string) error {
+	return fmt.Errorf(`got incorrect channel name %q: expected one of the allowed channels "%s"`, chnl, strings.Join(allowedChannels, `", "`))
 }
 
 func cloneGitRepositoryBranch(url, gitBranch, username, password string) (*git.Repository, error) {
@@ -1,13 +1,19 @@
 package server
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/werf/trdl/server/pkg/config"
+	"github.com/werf/trdl/server/pkg/publisher"
 	"github.com/werf/trdl/server/pkg/tasks_manager"
+	"github.com/werf/trdl/server/pkg/util"
 )
 
 type PathPublishCallbackSuite struct {
@@ -67,6 +73,295 @@ func (suite *PathPublishCallbackSuite) TestBusy() {
 	suite.mockedTasksManager.AssertExpectations(suite.T())
 }
 
+func (suite *PathPublishCallbackSuite) TestValidatePublishConfig_Channels() {
+	suite.mockedPublisher.On("GetExistingReleases").Return([]string{"1.0.0", "1.1.0"})
+
+	for name, test := range map[string]struct {
+		config      string
+		expectedErr string
+	}{
+		"default channels": {
+			config: `
+groups:
+- name: "1"
+  channels:
+  - name: stable
+    version: 1.0.0
+  - name: rock-solid
+    version: 1.0.0
+`,
+		},
+		"default channels do not include undeclared channel": {
+			config: `
+groups:
+- name: "1"
+  channels:
+  - name: nightly
+    version: 1.0.0
+`,
+			expectedErr: `got incorrect channel name "nightly": expected one of the allowed channels "alpha", "beta", "ea", "stable", "rock-solid"`,
+		},
+		"declared channels": {
+			config: `
+allowedChannels: [nightly, stable, lts]
+defaultChannel: lts
+groups:
+- name: "1"
+  channels:
+  - name: nightly
+    version: 1.1.0
+  - name: lts
+    version: 1.0.0
+`,
+		},
+		"declared channels replace default channels": {
+			config: `
+allowedChannels: [nightly, lts]
+groups:
+- name: "1"
+  channels:
+  - name: stable
+    version: 1.0.0
+`,
+			expectedErr: `got incorrect channel name "stable": expected one of the allowed channels "nightly", "lts"`,
+		},
+		"group channels": {
+			config: `
+allowedChannels: [nightly, stable, lts]
+groups:
+- name: "1"
+  allowedChannels: [lts]
+  channels:
+  - name: nightly
+    version: 1.1.0
+`,
+			expectedErr: `got incorrect channel name "nightly": expected one of the allowed channels "lts"`,
+		},
+		"group channels not declared": {
+			config: `
+allowedChannels: [nightly, stable]
+groups:
+- name: "1"
+  allowedChannels: [lts]
+`,
+			expectedErr: `group "1" "allowedChannels" field validation failed: channel "lts" is not allowed in the repository`,
+		},
+		"default channel not declared": {
+			config: `
+allowedChannels: [nightly, lts]
+defaultChannel: stable
+`,
+			expectedErr: `"defaultChannel" field validation failed: channel "stable" is not allowed`,
+		},
+		"promotion policy channel not declared": {
+			config: `
+promotionPolicy:
+  rules:
+  - channel: stable
+    fromChannel: nightly
+    minDays: 7
+`,
+			expectedErr: `"promotionPolicy" field validation failed: channel "nightly" is not allowed`,
+		},
+		"invalid channel name": {
+			config: `
+allowedChannels: [Nightly]
+`,
+			expectedErr: `"allowedChannels" field validation failed: channel name "Nightly" must consist of lower case alphanumeric characters, '-' or '_', and must start and end with an alphanumeric character`,
+		},
+	} {
+		test := test
+		suite.Run(name, func() {
+			cfg, err := config.ParseTrdlChannels([]byte(test.config))
+			assert.Nil(suite.T(), err)
+
+			err = ValidatePublishConfig(suite.ctx, suite.mockedPublisher, nil, cfg, publisher.NewChannelsHistory(), false, hclog.NewNullLogger())
+			if test.expectedErr == "" {
+				assert.Nil(suite.T(), err)
+			} else {
+				assert.EqualError(suite.T(), err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestTrdlChannelsDeclaration(t *testing.T) {
+	cfg, err := config.ParseTrdlChannels([]byte(`
+allowedChannels: [nightly, lts]
+groups:
+- name: "1"
+  allowedChannels: [lts]
+- name: "2"
+`))
+	assert.Nil(t, err)
+
+	assert.Equal(t, config.TrdlChannelsDeclaration{
+		AllowedChannels: []string{"nightly", "lts"},
+		DefaultChannel:  "lts",
+		Groups:          map[string][]string{"1": {"lts"}},
+	}, cfg.Declaration())
+
+	cfg, err = config.ParseTrdlChannels([]byte(`groups: []`))
+	assert.Nil(t, err)
+
+	assert.Equal(t, config.TrdlChannelsDeclaration{
+		AllowedChannels: config.DefaultChannels,
+		DefaultChannel:  config.DefaultChannel,
+	}, cfg.Declaration())
+}
+
+func TestValidatePromotionPolicy(t *testing.T) {
+	now := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+	defer func(clock util.Clock) { SystemClock = clock }(SystemClock)
+	SystemClock = util.NewFixedClock(now)
+
+	history := publisher.NewChannelsHistory()
+	history.Update(parseTrdlChannels(t, `
+groups:
+- name: "1"
+  channels:
+  - name: ea
+    version: 1.1.0
+  - name: stable
+    version: 1.0.0
+`), "commit1", false, now.AddDate(0, 0, -10))
+	history.Update(parseTrdlChannels(t, `
+groups:
+- name: "1"
+  channels:
+  - name: ea
+    version: 1.2.0
+  - name: stable
+    version: 1.0.0
+`), "commit2", false, now.AddDate(0, 0, -3))
+
+	const policy = `
+promotionPolicy:
+  forbidDowngrade: true
+  rules:
+  - channel: stable
+    fromChannel: ea
+    minDays: 7
+`
+
+	for name, test := range map[string]struct {
+		config      string
+		history     *publisher.ChannelsHistory
+		rollback    bool
+		expectedErr string
+	}{
+		"unchanged channels": {
+			config: policy + `
+groups:
+- name: "1"
+  channels:
+  - name: ea
+    version: 1.2.0
+  - name: stable
+    version: 1.0.0
+`,
+		},
+		"promoted after the minimal days": {
+			config: policy + `
+groups:
+- name: "1"
+  channels:
+  - name: stable
+    version: 1.1.0
+`,
+		},
+		"promoted before the minimal days": {
+			config: policy + `
+groups:
+- name: "1"
+  channels:
+  - name: stable
+    version: 1.2.0
+`,
+			expectedErr: `cannot publish version "1.2.0" to channel "stable" (group: "1"): the version must be published to channel "ea" for at least 7 days`,
+		},
+		"never published to the previous channel": {
+			config: policy + `
+groups:
+- name: "1"
+  channels:
+  - name: stable
+    version: 1.0.1
+`,
+			expectedErr: `cannot publish version "1.0.1" to channel "stable" (group: "1"): the version must be published to channel "ea" for at least 7 days`,
+		},
+		"downgrade": {
+			config: policy + `
+groups:
+- name: "1"
+  channels:
+  - name: ea
+    version: 1.1.0
+`,
+			expectedErr: `cannot move channel "ea" from "1.2.0" to the lower version "1.1.0" (group: "1"): mark the commit with the "Trdl-Rollback: true" trailer to roll back`,
+		},
+		"rollback": {
+			config: policy + `
+groups:
+- name: "1"
+  channels:
+  - name: ea
+    version: 1.1.0
+`,
+			rollback: true,
+		},
+		"no policy": {
+			config: `
+groups:
+- name: "1"
+  channels:
+  - name: ea
+    version: 1.1.0
+  - name: stable
+    version: 1.2.0
+`,
+		},
+		"no history": {
+			config: policy + `
+groups:
+- name: "1"
+  channels:
+  - name: stable
+    version: 1.2.0
+`,
+			history: publisher.NewChannelsHistory(),
+		},
+	} {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			testHistory := history
+			if test.history != nil {
+				testHistory = test.history
+			}
+
+			err := ValidatePromotionPolicy(context.Background(), parseTrdlChannels(t, test.config), testHistory, test.rollback, hclog.NewNullLogger())
+			if test.expectedErr == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestIsRollbackCommitMessage(t *testing.T) {
+	assert.True(t, IsRollbackCommitMessage("Roll back stable\n\nTrdl-Rollback: true\n"))
+	assert.True(t, IsRollbackCommitMessage("Roll back stable\n\ntrdl-rollback: True"))
+	assert.False(t, IsRollbackCommitMessage("Roll back stable\n\nTrdl-Rollback: false\n"))
+	assert.False(t, IsRollbackCommitMessage("Trdl-Rollback"))
+}
+
+func parseTrdlChannels(t *testing.T, data string) *config.TrdlChannels {
+	cfg, err := config.ParseTrdlChannels([]byte(data))
+	assert.Nil(t, err)
+	return cfg
+}
+
 func TestBackendPathPublishCallback(t *testing.T) {
 	suite.Run(t, new(PathPublishCallbackSuite))
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/werf/trdl/server/pkg/docker"
 	trdlGit "github.com/werf/trdl/server/pkg/git"
 	"github.com/werf/trdl/server/pkg/pgp"
+	"github.com/werf/trdl/server/pkg/publisher"
 	"github.com/werf/trdl/server/pkg/tasks_manager"
 	"github.com/werf/trdl/server/pkg/util"
 )
@@ -80,7 +81,7 @@ func ValidateReleaseVersion(releaseVersion string) error {
 }
 
 func (b *Backend) pathRelease(ctx context.Context, req *logical.Request, fields *framework.FieldData) (*logical.Response, error) {
-	if errResp := util.CheckRequiredFields(req, fields); errResp != nil {
+	if errResp := util.CheckRequiredFields(fields); errResp != nil {
 		return errResp, nil
 	}
 
@@ -111,6 +112,11 @@ func (b *Backend) pathRelease(ctx context.Context, req *logical.Request, fields
 		gitPassword = gitCredentialFromStorage.Password
 	}
 
+	targetPlatforms, err := publisher.ParsePlatforms(cfg.TargetPlatforms)
+	if err != nil {
+		return logical.ErrorResponse("%s validation failed: %s", fieldNameTargetPlatforms, err), nil
+	}
+
 	opts := cfg.RepositoryOptions()
 	opts.InitializeTUFKeys = true
 	opts.InitializePGPSigningKey = true
@@ -119,7 +125,7 @@ func (b *Backend) pathRelease(ctx context.Context, req *logical.Request, fields
 		return nil, fmt.Errorf("error getting publisher repository: %w", err)
 	}
 
-	taskUUID, err := b.TasksManager.RunTask(context.Background(), req.Storage, func(ctx context.Context, storage logical.Storage) error {
+	taskUUID, err := b.TasksManager.RunTask(context.Background(), req.Storage, b.discardStagedOnError(publisherRepository, func(ctx context.Context, storage logical.Storage) error {
 		logboek.Context(ctx).Default().LogF("Started task\n")
 		b.Logger().Debug("Started task")
 
@@ -152,6 +158,13 @@ func (b *Backend) pathRelease(ctx context.Context, req *logical.Request, fields
 			return fmt.Errorf("unable to get trdl configuration: %w", err)
 		}
 
+		logboek.Context(ctx).Default().LogF("Updating TUF repository delegated roles\n")
+		b.Logger().Debug("Updating TUF repository delegated roles")
+
+		if err := b.Publisher.UpdateDelegatedRoles(ctx, storage, publisherRepository); err != nil {
+			return fmt.Errorf("unable to update TUF repository delegated roles: %w", err)
+		}
+
 		logboek.Context(ctx).Default().LogF("Starting release artifacts tar archive build\n")
 		b.Logger().Debug("Starting release artifacts tar archive build")
 
@@ -185,12 +198,29 @@ func (b *Backend) pathRelease(ctx context.Context, req *logical.Request, fields
 					logboek.Context(ctx).Default().LogF("Publishing %q into the tuf repo ...\n", hdr.Name)
 					b.Logger().Debug(fmt.Sprintf("Publishing %q into the tuf repo ...", hdr.Name))
 
-					if err := b.Publisher.StageReleaseTarget(ctx, publisherRepository, releaseName, hdr.Name, twArtifacts); err != nil {
+					if err := b.Publisher.StageReleaseTarget(ctx, publisherRepository, releaseName, hdr.Name, twArtifacts, targetPlatforms); err != nil {
 						return fmt.Errorf("unable to publish release target %q: %w", hdr.Name, err)
 					}
 				}
 			}
 
+			logboek.Context(ctx).Default().LogF("Publishing releases index into the TUF repository\n")
+			b.Logger().Debug("Publishing releases index into the TUF repository")
+
+			headRef, err := gitRepo.Head()
+			if err != nil {
+				return fmt.Errorf("error getting git tag %q head reference: %w", gitTag, err)
+			}
+
+			if err := b.Publisher.StageReleasesIndex(ctx, publisherRepository, publisher.ReleaseInfo{
+				Name:        releaseName,
+				GitTag:      gitTag,
+				GitCommit:   headRef.Hash().String(),
+				PublishedAt: SystemClock.Now(),
+			}); err != nil {
+				return fmt.Errorf("unable to publish releases index: %w", err)
+			}
+
 			logboek.Context(ctx).Default().LogF("Committing TUF repository state\n")
 			b.Logger().Debug("Committing TUF repository state")
 
@@ -203,7 +233,7 @@ func (b *Backend) pathRelease(ctx context.Context, req *logical.Request, fields
 		b.Logger().Debug("Task finished")
 
 		return nil
-	})
+	}))
 	if err != nil {
 		if err == tasks_manager.ErrBusy {
 			return logical.ErrorResponse("busy"), nil
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/werf/trdl/server/pkg/publisher"
+)
+
+func statusPath(b *Backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `status$`,
+		Fields:  map[string]*framework.FieldSchema{},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathStatusRead,
+				Summary:  pathStatusHelpSyn,
+			},
+		},
+
+		HelpSynopsis:    pathStatusHelpSyn,
+		HelpDescription: pathStatusHelpDesc,
+	}
+}
+
+func (b *Backend) pathStatusRead(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	// the status is read-only, thus neither TUF keys nor the PGP signing key are generated
+	publisherRepository, errResp, err := b.getTufRepository(ctx, req.Storage)
+	if errResp != nil || err != nil {
+		return errResp, err
+	}
+
+	if _, _, err := publisherRepository.GetRootMeta(ctx); err == publisher.ErrUninitializedRepositoryRoot {
+		return errorResponseRepositoryNotInitialized, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to get TUF repository root: %w", err)
+	}
+
+	rolesStatus, err := publisherRepository.GetRolesStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get TUF repository roles status: %w", err)
+	}
+
+	now := SystemClock.Now()
+	resp := &logical.Response{Data: map[string]interface{}{}}
+
+	rolesData := map[string]interface{}{}
+	for _, status := range rolesStatus {
+		nearExpiry := status.IsNearExpiry(now)
+
+		rolesData[status.Role] = map[string]interface{}{
+			"version":            status.Version,
+			"expires":            status.Expires.UTC().Format(time.RFC3339),
+			"expires_in_seconds": int64(status.Expires.Sub(now).Seconds()),
+			"next_rotation":      status.RotateAt.UTC().Format(time.RFC3339),
+			"near_expiry":        nearExpiry,
+		}
+
+		switch {
+		case !status.Expires.After(now):
+			resp.AddWarning(fmt.Sprintf("%s.json metadata expired at %s", status.Role, status.Expires.UTC().Format(time.RFC3339)))
+		case nearExpiry:
+			resp.AddWarning(fmt.Sprintf("%s.json metadata expires soon at %s, but has not been rotated since %s", status.Role, status.Expires.UTC().Format(time.RFC3339), status.RotateAt.UTC().Format(time.RFC3339)))
+		}
+	}
+	resp.Data["roles"] = rolesData
+
+	lastRunAt, err := getLastPeriodicRunTimestamp(ctx, req.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get last periodic run timestamp: %w", err)
+	}
+
+	lastResult, err := getLastPeriodicTaskResult(ctx, req.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get last periodic task result: %w", err)
+	}
+
+	periodicData := map[string]interface{}{}
+	if !lastRunAt.IsZero() {
+		periodicData["last_run_at"] = lastRunAt.UTC().Format(time.RFC3339)
+	}
+	if lastResult != nil {
+		periodicData["last_finished_at"] = lastResult.FinishedAt.UTC().Format(time.RFC3339)
+		periodicData["last_succeeded"] = lastResult.Error == ""
+		periodicData["last_error"] = lastResult.Error
+
+		if lastResult.Error != "" {
+			resp.AddWarning(fmt.Sprintf("last periodic task failed at %s: %s", lastResult.FinishedAt.UTC().Format(time.RFC3339), lastResult.Error))
+		}
+	}
+	resp.Data["periodic_task"] = periodicData
+
+	resp.Data["healthy"] = len(resp.Warnings) == 0
+
+	return resp, nil
+}
+
+const (
+	pathStatusHelpSyn  = "Get the TUF repository health and expiry status"
+	pathStatusHelpDesc = "Get the version, expiration time and the next planned rotation time of each TUF repository role along with the result of the last periodic task. Warnings are returned when any role metadata is expired or close to expiry, or when the last periodic task failed"
+)
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/werf/trdl/server/pkg/publisher"
+	"github.com/werf/trdl/server/pkg/util"
+)
+
+type PathStatusCallbackSuite struct {
+	CommonSuite
+	now              time.Time
+	mockedRepository *MockedRepository
+}
+
+func (suite *PathStatusCallbackSuite) SetupTest() {
+	suite.CommonSuite.SetupTest()
+	suite.req.Path = "status"
+	suite.req.Operation = logical.ReadOperation
+
+	suite.now = time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	SystemClock = util.NewFixedClock(suite.now)
+
+	lifetimes := publisher.DefaultTufRepoLifetimes()
+	suite.mockedRepository = &MockedRepository{
+		RolesStatus: []*publisher.TufRoleStatus{
+			{Role: "root", Version: 2, Expires: suite.now.AddDate(1, 0, 0), RotateAt: suite.now.AddDate(0, 3, 0), Lifetime: lifetimes.Root},
+			{Role: "timestamp", Version: 10, Expires: suite.now.Add(24 * time.Hour), RotateAt: suite.now.Add(4 * time.Hour), Lifetime: lifetimes.Timestamp},
+		},
+	}
+	suite.mockedPublisher.Repository = suite.mockedRepository
+}
+
+func (suite *PathStatusCallbackSuite) TearDownTest() {
+	SystemClock = util.NewSystemClock()
+}
+
+func (suite *PathStatusCallbackSuite) TestConfigurationNotFound() {
+	resp, err := suite.backend.HandleRequest(suite.ctx, suite.req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), errorResponseConfigurationNotFound, resp)
+}
+
+func (suite *PathStatusCallbackSuite) TestHealthy() {
+	assert.Nil(suite.T(), putConfiguration(suite.ctx, suite.storage, completeConfiguration()))
+	assert.Nil(suite.T(), putLastPeriodicTaskResult(suite.ctx, suite.storage, &periodicTaskResult{FinishedAt: suite.now.Add(-time.Minute)}))
+
+	suite.mockedPublisher.On("GetRepository").Return(nil)
+	suite.mockedRepository.On("GetRootMeta").Return(nil)
+	suite.mockedRepository.On("GetRolesStatus").Return(nil)
+
+	resp, err := suite.backend.HandleRequest(suite.ctx, suite.req)
+	assert.Nil(suite.T(), err)
+	if assert.NotNil(suite.T(), resp) {
+		assert.Empty(suite.T(), resp.Warnings)
+		assert.Equal(suite.T(), true, resp.Data["healthy"])
+		assert.Equal(suite.T(), map[string]interface{}{
+			"version":            int64(10),
+			"expires":            "2022-10-02T12:00:00Z",
+			"expires_in_seconds": int64(24 * 60 * 60),
+			"next_rotation":      "2022-10-01T16:00:00Z",
+			"near_expiry":        false,
+		}, resp.Data["roles"].(map[string]interface{})["timestamp"])
+		assert.Equal(suite.T(), true, resp.Data["periodic_task"].(map[string]interface{})["last_succeeded"])
+	}
+
+	// the read-only status must not generate the signing keys
+	assert.False(suite.T(), suite.mockedPublisher.RepositoryOptions.InitializeTUFKeys)
+	assert.False(suite.T(), suite.mockedPublisher.RepositoryOptions.InitializePGPSigningKey)
+
+	suite.mockedPublisher.AssertExpectations(suite.T())
+	suite.mockedRepository.AssertExpectations(suite.T())
+}
+
+func (suite *PathStatusCallbackSuite) TestNearExpiryAndFailedPeriodicTask() {
+	assert.Nil(suite.T(), putConfiguration(suite.ctx, suite.storage, completeConfiguration()))
+	assert.Nil(suite.T(), putLastPeriodicTaskResult(suite.ctx, suite.storage, &periodicTaskResult{FinishedAt: suite.now.Add(-time.Minute), Error: "s3 is down"}))
+
+	suite.mockedRepository.RolesStatus[1].Expires = suite.now.Add(3 * time.Hour)
+	suite.mockedRepository.RolesStatus[1].RotateAt = suite.now.Add(-17 * time.Hour)
+
+	suite.mockedPublisher.On("GetRepository").Return(nil)
+	suite.mockedRepository.On("GetRootMeta").Return(nil)
+	suite.mockedRepository.On("GetRolesStatus").Return(nil)
+
+	resp, err := suite.backend.HandleRequest(suite.ctx, suite.req)
+	assert.Nil(suite.T(), err)
+	if assert.NotNil(suite.T(), resp) {
+		assert.Equal(suite.T(), false, resp.Data["healthy"])
+		assert.Equal(suite.T(), true, resp.Data["roles"].(map[string]interface{})["timestamp"].(map[string]interface{})["near_expiry"])
+		assert.Equal(suite.T(), false, resp.Data["roles"].(map[string]interface{})["root"].(map[string]interface{})["near_expiry"])
+		if assert.Len(suite.T(), resp.Warnings, 2) {
+			assert.Contains(suite.T(), resp.Warnings[0], "timestamp.json metadata expires soon")
+			assert.Contains(suite.T(), resp.Warnings[1], "s3 is down")
+		}
+	}
+}
+
+func TestBackendPathStatusCallback(t *testing.T) {
+	suite.Run(t, new(PathStatusCallbackSuite))
+}
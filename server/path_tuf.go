@@ -0,0 +1,343 @@
+package server
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/theupdateframework/go-tuf/data"
+	"github.com/theupdateframework/go-tuf/pkg/keys"
+
+	"github.com/werf/logboek"
+	"github.com/werf/trdl/server/pkg/publisher"
+	"github.com/werf/trdl/server/pkg/tasks_manager"
+	"github.com/werf/trdl/server/pkg/util"
+)
+
+func tufRootPath(b *Backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `tuf/root$`,
+		Fields:  map[string]*framework.FieldSchema{},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathTufRootRead,
+				Summary:  pathTufRootHelpSyn,
+			},
+		},
+
+		HelpSynopsis:    pathTufRootHelpSyn,
+		HelpDescription: pathTufRootHelpDesc,
+	}
+}
+
+func tufRootKeyPath(b *Backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `tuf/root/key$`,
+		Fields: map[string]*framework.FieldSchema{
+			fieldNameTufRootPublicKey: {
+				Type:        framework.TypeString,
+				Description: `The public key in the TUF format (e.g. {"keytype":"ed25519","scheme":"ed25519","keyval":{"public":"..."}})`,
+				Required:    true,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.CreateOperation: &framework.PathOperation{
+				Callback: b.pathTufRootKeyCreateOrUpdate,
+				Summary:  pathTufRootKeyHelpSyn,
+			},
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathTufRootKeyCreateOrUpdate,
+				Summary:  pathTufRootKeyHelpSyn,
+			},
+		},
+
+		HelpSynopsis:    pathTufRootKeyHelpSyn,
+		HelpDescription: pathTufRootKeyHelpDesc,
+	}
+}
+
+func tufRootPendingPath(b *Backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `tuf/root/pending$`,
+		Fields:  map[string]*framework.FieldSchema{},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathTufRootPendingRead,
+				Summary:  pathTufRootPendingHelpSyn,
+			},
+		},
+
+		HelpSynopsis:    pathTufRootPendingHelpSyn,
+		HelpDescription: pathTufRootPendingHelpDesc,
+	}
+}
+
+func tufRootSignaturePath(b *Backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `tuf/root/signature$`,
+		Fields: map[string]*framework.FieldSchema{
+			fieldNameTufRootSignatureKeyID: {
+				Type:        framework.TypeString,
+				Description: "The id of the root key the payload is signed by",
+				Required:    true,
+			},
+			fieldNameTufRootSignature: {
+				Type:        framework.TypeString,
+				Description: "The hex-encoded signature of the pending root.json payload",
+				Required:    true,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.CreateOperation: &framework.PathOperation{
+				Callback: b.pathTufRootSignatureCreateOrUpdate,
+				Summary:  pathTufRootSignatureHelpSyn,
+			},
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathTufRootSignatureCreateOrUpdate,
+				Summary:  pathTufRootSignatureHelpSyn,
+			},
+		},
+
+		HelpSynopsis:    pathTufRootSignatureHelpSyn,
+		HelpDescription: pathTufRootSignatureHelpDesc,
+	}
+}
+
+// getTufRepository returns the publisher repository or the error response if the repository is not available.
+func (b *Backend) getTufRepository(ctx context.Context, storage logical.Storage) (publisher.RepositoryInterface, *logical.Response, error) {
+	cfg, err := getConfiguration(ctx, storage)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get configuration from storage: %w", err)
+	}
+
+	if cfg == nil {
+		return nil, errorResponseConfigurationNotFound, nil
+	}
+
+	opts := cfg.RepositoryOptions()
+	opts.InitializeTUFKeys = false
+	opts.InitializePGPSigningKey = false
+	publisherRepository, err := b.Publisher.GetRepository(ctx, storage, opts)
+	if err == publisher.ErrUninitializedRepositoryKeys {
+		return nil, errorResponseRepositoryNotInitialized, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting publisher repository: %w", err)
+	}
+
+	return publisherRepository, nil, nil
+}
+
+func (b *Backend) pathTufRootRead(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	publisherRepository, errResp, err := b.getTufRepository(ctx, req.Storage)
+	if errResp != nil || err != nil {
+		return errResp, err
+	}
+
+	rootData, rootVersion, err := publisherRepository.GetRootMeta(ctx)
+	if err == publisher.ErrUninitializedRepositoryRoot {
+		return errorResponseRepositoryNotInitialized, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get TUF repository root: %w", err)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"root_version": rootVersion,
+			"root_sha512":  fmt.Sprintf("%x", sha512.Sum512(rootData)),
+		},
+	}, nil
+}
+
+const (
+	fieldNameTufRootPublicKey      = "public_key"
+	fieldNameTufRootSignatureKeyID = "key_id"
+	fieldNameTufRootSignature      = "signature"
+)
+
+var errorResponseRepositoryNotInitialized = logical.ErrorResponse("TUF repository is not initialized: publish the first release")
+
+const (
+	pathTufRootHelpSyn  = "Get the current TUF repository root"
+	pathTufRootHelpDesc = "Get the current TUF repository root version and root.json sha512 checksum which are required to add the repository on the client side (trdl add REPO URL ROOT_VERSION ROOT_SHA512)"
+
+	pathTufRootKeyHelpSyn  = "Add the offline TUF repository root key"
+	pathTufRootKeyHelpDesc = "Add the public key to the TUF repository root role and apply the configured root threshold in the background task. When the root threshold exceeds the number of online root keys, root.json changes are not published until they are signed by the offline root keys (see tuf/root/pending and tuf/root/signature)"
+
+	pathTufRootPendingHelpSyn  = "Get the pending TUF repository root"
+	pathTufRootPendingHelpDesc = "Get the root.json change waiting for the signatures of the offline root keys: the payload to sign, the root keys and the keys already signed it"
+
+	pathTufRootSignatureHelpSyn  = "Import the pending TUF repository root signature"
+	pathTufRootSignatureHelpDesc = "Import the signature of the pending root.json payload made by the offline root key in the background task. The pending root.json is published as soon as it is signed by the thresholds of both the new and the currently published root keys"
+)
+
+func (b *Backend) pathTufRootKeyCreateOrUpdate(ctx context.Context, req *logical.Request, fields *framework.FieldData) (*logical.Response, error) {
+	if errResp := util.CheckRequiredFields(fields); errResp != nil {
+		return errResp, nil
+	}
+
+	key := &data.PublicKey{}
+	if err := json.Unmarshal([]byte(fields.Get(fieldNameTufRootPublicKey).(string)), key); err != nil {
+		return logical.ErrorResponse("Field %q is invalid: %s", fieldNameTufRootPublicKey, err), nil
+	}
+
+	if _, err := keys.GetVerifier(key); err != nil {
+		return logical.ErrorResponse("Field %q is invalid: %s", fieldNameTufRootPublicKey, err), nil
+	}
+	keyID := key.IDs()[0]
+
+	publisherRepository, errResp, err := b.getTufRepository(ctx, req.Storage)
+	if errResp != nil || err != nil {
+		return errResp, err
+	}
+
+	taskUUID, err := b.TasksManager.RunTask(context.Background(), req.Storage, b.discardStagedOnError(publisherRepository, func(ctx context.Context, storage logical.Storage) error {
+		logboek.Context(ctx).Default().LogF("Adding TUF repository root key %q\n", keyID)
+		b.Logger().Debug(fmt.Sprintf("Adding TUF repository root key %q", keyID))
+
+		if err := b.Publisher.AddRootKey(ctx, storage, publisherRepository, key); err != nil {
+			return fmt.Errorf("unable to add root key: %w", err)
+		}
+
+		pendingRoot, err := publisher.GetPendingRoot(ctx, storage)
+		if err != nil {
+			return err
+		}
+
+		if pendingRoot != nil {
+			logboek.Context(ctx).Default().LogF("The root.json change is pending the signatures of the offline root keys (see tuf/root/pending)\n")
+			b.Logger().Debug("The root.json change is pending the signatures of the offline root keys")
+		}
+
+		logboek.Context(ctx).Default().LogF("Task finished\n")
+		b.Logger().Debug("Task finished")
+
+		return nil
+	}))
+	if err != nil {
+		if err == tasks_manager.ErrBusy {
+			return logical.ErrorResponse("busy"), nil
+		}
+
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"task_uuid": taskUUID,
+			"key_id":    keyID,
+		},
+	}, nil
+}
+
+func (b *Backend) pathTufRootPendingRead(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	pendingRoot, err := publisher.GetPendingRoot(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	if pendingRoot == nil {
+		return logical.ErrorResponse("No pending root.json changes"), nil
+	}
+
+	signed, root, err := pendingRoot.GetRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := pendingRoot.Payload()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get pending root.json payload: %w", err)
+	}
+
+	signedKeyIDs := []string{}
+	for _, sig := range signed.Signatures {
+		signedKeyIDs = append(signedKeyIDs, sig.KeyID)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"root_version":   root.Version,
+			"root_expires":   root.Expires,
+			"root_key_ids":   root.Roles["root"].KeyIDs,
+			"root_threshold": root.Roles["root"].Threshold,
+			"signed_key_ids": signedKeyIDs,
+			"payload":        string(payload),
+			"root":           string(pendingRoot.Root),
+		},
+	}, nil
+}
+
+func (b *Backend) pathTufRootSignatureCreateOrUpdate(ctx context.Context, req *logical.Request, fields *framework.FieldData) (*logical.Response, error) {
+	if errResp := util.CheckRequiredFields(fields); errResp != nil {
+		return errResp, nil
+	}
+
+	sig, err := hex.DecodeString(fields.Get(fieldNameTufRootSignature).(string))
+	if err != nil {
+		return logical.ErrorResponse("Field %q is invalid: %s", fieldNameTufRootSignature, err), nil
+	}
+	signature := data.Signature{
+		KeyID:     fields.Get(fieldNameTufRootSignatureKeyID).(string),
+		Signature: sig,
+	}
+
+	pendingRoot, err := publisher.GetPendingRoot(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	if pendingRoot == nil {
+		return logical.ErrorResponse("No pending root.json changes"), nil
+	}
+
+	publisherRepository, errResp, err := b.getTufRepository(ctx, req.Storage)
+	if errResp != nil || err != nil {
+		return errResp, err
+	}
+
+	taskUUID, err := b.TasksManager.RunTask(context.Background(), req.Storage, b.discardStagedOnError(publisherRepository, func(ctx context.Context, storage logical.Storage) error {
+		logboek.Context(ctx).Default().LogF("Adding root.json signature of the key %q\n", signature.KeyID)
+		b.Logger().Debug(fmt.Sprintf("Adding root.json signature of the key %q", signature.KeyID))
+
+		committed, err := b.Publisher.AddRootSignature(ctx, storage, publisherRepository, signature)
+		if err != nil {
+			return fmt.Errorf("unable to add root.json signature: %w", err)
+		}
+
+		if committed {
+			logboek.Context(ctx).Default().LogF("The pending root.json is signed by the required keys and published\n")
+			b.Logger().Debug("The pending root.json is signed by the required keys and published")
+		} else {
+			logboek.Context(ctx).Default().LogF("The pending root.json requires more signatures (see tuf/root/pending)\n")
+			b.Logger().Debug("The pending root.json requires more signatures")
+		}
+
+		logboek.Context(ctx).Default().LogF("Task finished\n")
+		b.Logger().Debug("Task finished")
+
+		return nil
+	}))
+	if err != nil {
+		if err == tasks_manager.ErrBusy {
+			return logical.ErrorResponse("busy"), nil
+		}
+
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"task_uuid": taskUUID,
+		},
+	}, nil
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/theupdateframework/go-tuf/pkg/keys"
+
+	"github.com/werf/trdl/server/pkg/publisher"
+	"github.com/werf/trdl/server/pkg/tasks_manager"
+)
+
+type PathTufRootCallbacksSuite struct {
+	CommonSuite
+}
+
+func (suite *PathTufRootCallbacksSuite) SetupTest() {
+	suite.CommonSuite.SetupTest()
+	suite.req.Operation = logical.CreateOperation
+}
+
+func (suite *PathTufRootCallbacksSuite) rootPublicKey() string {
+	signer, err := keys.GenerateEd25519Key()
+	assert.Nil(suite.T(), err)
+
+	key, err := json.Marshal(signer.PublicData())
+	assert.Nil(suite.T(), err)
+
+	return string(key)
+}
+
+func (suite *PathTufRootCallbacksSuite) TestRootKey_Basic() {
+	err := putConfiguration(suite.ctx, suite.storage, completeConfiguration())
+	assert.Nil(suite.T(), err)
+
+	suite.mockedPublisher.On("GetRepository").Return(nil)
+	suite.mockedTasksManager.On("RunTask").Return("UUID", nil)
+
+	suite.req.Path = "tuf/root/key"
+	suite.req.Data = map[string]interface{}{fieldNameTufRootPublicKey: suite.rootPublicKey()}
+
+	resp, err := suite.backend.HandleRequest(suite.ctx, suite.req)
+	assert.Nil(suite.T(), err)
+	if assert.NotNil(suite.T(), resp) {
+		assert.False(suite.T(), resp.IsError())
+		assert.Equal(suite.T(), "UUID", resp.Data["task_uuid"])
+		assert.NotEmpty(suite.T(), resp.Data["key_id"])
+	}
+
+	suite.mockedPublisher.AssertExpectations(suite.T())
+	suite.mockedTasksManager.AssertExpectations(suite.T())
+}
+
+func (suite *PathTufRootCallbacksSuite) TestRootKey_Busy() {
+	err := putConfiguration(suite.ctx, suite.storage, completeConfiguration())
+	assert.Nil(suite.T(), err)
+
+	suite.mockedTasksManager.IsBusy = true
+
+	suite.mockedPublisher.On("GetRepository").Return(nil)
+	suite.mockedTasksManager.On("RunTask").Return("", tasks_manager.ErrBusy)
+
+	suite.req.Path = "tuf/root/key"
+	suite.req.Data = map[string]interface{}{fieldNameTufRootPublicKey: suite.rootPublicKey()}
+
+	resp, err := suite.backend.HandleRequest(suite.ctx, suite.req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), logical.ErrorResponse("busy"), resp)
+}
+
+func (suite *PathTufRootCallbacksSuite) TestRootKey_InvalidKey() {
+	suite.req.Path = "tuf/root/key"
+	suite.req.Data = map[string]interface{}{fieldNameTufRootPublicKey: `{"keytype":"unknown"}`}
+
+	resp, err := suite.backend.HandleRequest(suite.ctx, suite.req)
+	assert.Nil(suite.T(), err)
+	if assert.NotNil(suite.T(), resp) {
+		assert.True(suite.T(), resp.IsError())
+	}
+
+	suite.mockedTasksManager.AssertNotCalled(suite.T(), "RunTask")
+}
+
+func (suite *PathTufRootCallbacksSuite) TestRootSignature_Basic() {
+	err := putConfiguration(suite.ctx, suite.storage, completeConfiguration())
+	assert.Nil(suite.T(), err)
+
+	err = publisher.PutPendingRoot(suite.ctx, suite.storage, &publisher.PendingRoot{Root: json.RawMessage(`{}`)})
+	assert.Nil(suite.T(), err)
+
+	suite.mockedPublisher.On("GetRepository").Return(nil)
+	suite.mockedTasksManager.On("RunTask").Return("UUID", nil)
+
+	suite.req.Path = "tuf/root/signature"
+	suite.req.Data = map[string]interface{}{
+		fieldNameTufRootSignatureKeyID: "KEY_ID",
+		fieldNameTufRootSignature:      "00ff",
+	}
+
+	resp, err := suite.backend.HandleRequest(suite.ctx, suite.req)
+	assert.Nil(suite.T(), err)
+	if assert.NotNil(suite.T(), resp) {
+		assert.Equal(suite.T(), map[string]interface{}{"task_uuid": "UUID"}, resp.Data)
+	}
+
+	suite.mockedPublisher.AssertExpectations(suite.T())
+	suite.mockedTasksManager.AssertExpectations(suite.T())
+}
+
+func (suite *PathTufRootCallbacksSuite) TestRootSignature_NoPendingRoot() {
+	err := putConfiguration(suite.ctx, suite.storage, completeConfiguration())
+	assert.Nil(suite.T(), err)
+
+	suite.req.Path = "tuf/root/signature"
+	suite.req.Data = map[string]interface{}{
+		fieldNameTufRootSignatureKeyID: "KEY_ID",
+		fieldNameTufRootSignature:      "00ff",
+	}
+
+	resp, err := suite.backend.HandleRequest(suite.ctx, suite.req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), logical.ErrorResponse("No pending root.json changes"), resp)
+
+	suite.mockedTasksManager.AssertNotCalled(suite.T(), "RunTask")
+}
+
+func TestBackendPathTufRootCallbacks(t *testing.T) {
+	suite.Run(t, new(PathTufRootCallbacksSuite))
+}
@@ -20,17 +20,64 @@ var SystemClock util.Clock = util.NewSystemClock()
 const (
 	// TODO: Do not use global run timestamp to specify repository operations period.
 	// TODO: Instead let publisher.Repository decide whether or not it is appropriate time
-	// TODO: to update timestamp, or to rotate private keys.
-	// TODO: Publisher.RotatePrivKeys may be called every minute, but it will actually rotate keys
-	// TODO: when it is appropriate time to rotate (based on each private key expiration date — which is internal data of publisher package).
-	// TODO: The same with publisher.UpdateTimestamps — let this procedure and internal Repository object decide when to update timestamps
-	// TODO: based on timestamp.json data.
+	// TODO: to update timestamps based on timestamp.json data.
+	// TODO: Publisher.RotateRepositoryKeys already may be called every minute: it actually rotates keys
+	// TODO: only when it is appropriate time to rotate (based on each private key expiration date — which is internal data of publisher package).
 	// TODO:
-	// TODO: For now timestamps being updated every hour forcefully and private keys are not rotated yet (will be implemented soon).
+	// TODO: For now the periodic task runs every hour forcefully.
 	lastPeriodicRunTimestampKey = "last_periodic_run_timestamp"
+	lastPeriodicTaskResultKey   = "last_periodic_task_result"
 	periodicRunPeriod           = 1 * time.Hour
 )
 
+type periodicTaskResult struct {
+	FinishedAt time.Time `json:"finished_at"`
+	Error      string    `json:"error,omitempty"`
+}
+
+func putLastPeriodicTaskResult(ctx context.Context, storage logical.Storage, result *periodicTaskResult) error {
+	entry, err := logical.StorageEntryJSON(lastPeriodicTaskResultKey, result)
+	if err != nil {
+		return fmt.Errorf("error creating storage json entry by key %q: %w", lastPeriodicTaskResultKey, err)
+	}
+
+	return storage.Put(ctx, entry)
+}
+
+func getLastPeriodicTaskResult(ctx context.Context, storage logical.Storage) (*periodicTaskResult, error) {
+	entry, err := storage.Get(ctx, lastPeriodicTaskResultKey)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	result := new(periodicTaskResult)
+	if err := entry.DecodeJSON(result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func getLastPeriodicRunTimestamp(ctx context.Context, storage logical.Storage) (time.Time, error) {
+	entry, err := storage.Get(ctx, lastPeriodicRunTimestampKey)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if entry == nil {
+		return time.Time{}, nil
+	}
+
+	lastRunTimestamp, err := strconv.ParseInt(string(entry.Value), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to parse %q: %w", lastPeriodicRunTimestampKey, err)
+	}
+
+	return time.Unix(lastRunTimestamp, 0), nil
+}
+
 func (b *Backend) Periodic(ctx context.Context, req *logical.Request) error {
 	entry, err := req.Storage.Get(ctx, lastPeriodicRunTimestampKey)
 	if err != nil {
@@ -72,15 +119,23 @@ func (b *Backend) Periodic(ctx context.Context, req *logical.Request) error {
 	}
 
 	now := SystemClock.Now()
-	uuid, err := b.TasksManager.RunTask(ctx, req.Storage, func(ctx context.Context, storage logical.Storage) error {
+	uuid, err := b.TasksManager.RunTask(ctx, req.Storage, b.discardStagedOnError(publisherRepository, func(ctx context.Context, storage logical.Storage) error {
 		err := b.periodicTask(ctx, storage, config, publisherRepository)
+
+		result := &periodicTaskResult{FinishedAt: SystemClock.Now()}
 		if err != nil {
 			b.Logger().Error(fmt.Sprintf("Periodic task failed: %s", err))
+			result.Error = err.Error()
 		} else {
 			b.Logger().Info("Periodic task succeeded")
 		}
+
+		if err := putLastPeriodicTaskResult(ctx, storage, result); err != nil {
+			b.Logger().Error(fmt.Sprintf("Unable to save periodic task result: %s", err))
+		}
+
 		return err
-	})
+	}))
 
 	if err == tasks_manager.ErrBusy {
 		b.Logger().Debug(fmt.Sprintf("Will not add new periodic task: there is currently running task which took more than %s", periodicRunPeriod))
@@ -101,6 +156,13 @@ func (b *Backend) Periodic(ctx context.Context, req *logical.Request) error {
 }
 
 func (b *Backend) periodicTask(ctx context.Context, storage logical.Storage, _ *configuration, publisherRepository publisher.RepositoryInterface) error {
+	logboek.Context(ctx).Default().LogF("Started TUF repository delegated roles update\n")
+	b.Logger().Debug("Started TUF repository delegated roles update")
+
+	if err := b.Publisher.UpdateDelegatedRoles(ctx, storage, publisherRepository); err != nil {
+		return fmt.Errorf("unable to update TUF repository delegated roles: %w", err)
+	}
+
 	logboek.Context(ctx).Default().LogF("Started TUF repository keys rotation\n")
 	b.Logger().Debug("Started TUF repository keys rotation")
 
@@ -2,21 +2,50 @@ package config
 
 import (
 	"fmt"
+	"regexp"
 
+	"github.com/samber/lo"
 	"gopkg.in/yaml.v2"
 )
 
 const (
 	DefaultTrdlChannelsPath = "trdl_channels.yaml"
+	DefaultChannel          = "stable"
 )
 
+// DefaultChannels are allowed when trdl_channels.yaml does not declare the allowed channels.
+var DefaultChannels = []string{"alpha", "beta", "ea", "stable", "rock-solid"}
+
+var channelNameRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9_-]*[a-z0-9])?$`)
+
 type TrdlChannels struct {
-	Groups []TrdlGroup `yaml:"groups,omitempty"`
+	// AllowedChannels in order from the least to the most stable one
+	AllowedChannels []string             `yaml:"allowedChannels,omitempty"`
+	DefaultChannel  string               `yaml:"defaultChannel,omitempty"`
+	PromotionPolicy *TrdlPromotionPolicy `yaml:"promotionPolicy,omitempty"`
+	Groups          []TrdlGroup          `yaml:"groups,omitempty"`
+}
+
+// TrdlPromotionPolicy is checked against the published channels history before publishing.
+type TrdlPromotionPolicy struct {
+	// ForbidDowngrade forbids moving a channel to a lower version unless the commit is marked as a rollback
+	ForbidDowngrade bool                `yaml:"forbidDowngrade,omitempty"`
+	Rules           []TrdlPromotionRule `yaml:"rules,omitempty"`
+}
+
+// TrdlPromotionRule requires the version to be in the FromChannel of the same group
+// for at least MinDays before it can be published to the Channel.
+type TrdlPromotionRule struct {
+	Channel     string `yaml:"channel"`
+	FromChannel string `yaml:"fromChannel"`
+	MinDays     int    `yaml:"minDays,omitempty"`
 }
 
 type TrdlGroup struct {
-	Name     string             `yaml:"name"`
-	Channels []TrdlGroupChannel `yaml:"channels,omitempty"`
+	Name string `yaml:"name"`
+	// AllowedChannels of the group, a subset of the allowed channels of the repository
+	AllowedChannels []string           `yaml:"allowedChannels,omitempty"`
+	Channels        []TrdlGroupChannel `yaml:"channels,omitempty"`
 }
 
 type TrdlGroupChannel struct {
@@ -24,6 +53,139 @@ type TrdlGroupChannel struct {
 	Version string `yaml:"version"`
 }
 
+// TrdlChannelsDeclaration is published into the TUF repository,
+// so that clients validate channels and choose the default channel as declared by the repository.
+type TrdlChannelsDeclaration struct {
+	AllowedChannels []string            `json:"allowedChannels"`
+	DefaultChannel  string              `json:"defaultChannel"`
+	Groups          map[string][]string `json:"groups,omitempty"`
+}
+
+func (c *TrdlChannels) GetAllowedChannels() []string {
+	if len(c.AllowedChannels) == 0 {
+		return DefaultChannels
+	}
+
+	return c.AllowedChannels
+}
+
+func (c *TrdlChannels) GetDefaultChannel() string {
+	if c.DefaultChannel != "" {
+		return c.DefaultChannel
+	}
+
+	allowedChannels := c.GetAllowedChannels()
+	if lo.Contains(allowedChannels, DefaultChannel) {
+		return DefaultChannel
+	}
+
+	// The most stable declared channel
+	return allowedChannels[len(allowedChannels)-1]
+}
+
+func (c *TrdlChannels) GetGroupAllowedChannels(group TrdlGroup) []string {
+	if len(group.AllowedChannels) == 0 {
+		return c.GetAllowedChannels()
+	}
+
+	return group.AllowedChannels
+}
+
+// ValidateChannelSets checks the declared channel names, groups channels are validated against the declaration separately.
+func (c *TrdlChannels) ValidateChannelSets() error {
+	if err := validateChannelSet(c.AllowedChannels); err != nil {
+		return fmt.Errorf(`"allowedChannels" field validation failed: %w`, err)
+	}
+
+	allowedChannels := c.GetAllowedChannels()
+
+	if !lo.Contains(allowedChannels, c.GetDefaultChannel()) {
+		return fmt.Errorf(`"defaultChannel" field validation failed: channel %q is not allowed`, c.GetDefaultChannel())
+	}
+
+	for _, group := range c.Groups {
+		if err := validateChannelSet(group.AllowedChannels); err != nil {
+			return fmt.Errorf(`group %q "allowedChannels" field validation failed: %w`, group.Name, err)
+		}
+
+		for _, channel := range group.AllowedChannels {
+			if !lo.Contains(allowedChannels, channel) {
+				return fmt.Errorf(`group %q "allowedChannels" field validation failed: channel %q is not allowed in the repository`, group.Name, channel)
+			}
+		}
+	}
+
+	return c.validatePromotionPolicy()
+}
+
+func (c *TrdlChannels) validatePromotionPolicy() error {
+	if c.PromotionPolicy == nil {
+		return nil
+	}
+
+	allowedChannels := c.GetAllowedChannels()
+	for _, rule := range c.PromotionPolicy.Rules {
+		for _, channel := range []string{rule.Channel, rule.FromChannel} {
+			if !lo.Contains(allowedChannels, channel) {
+				return fmt.Errorf(`"promotionPolicy" field validation failed: channel %q is not allowed`, channel)
+			}
+		}
+
+		if rule.Channel == rule.FromChannel {
+			return fmt.Errorf(`"promotionPolicy" field validation failed: channel %q cannot be promoted from itself`, rule.Channel)
+		}
+
+		if rule.MinDays < 0 {
+			return fmt.Errorf(`"promotionPolicy" field validation failed: negative "minDays" %d for channel %q`, rule.MinDays, rule.Channel)
+		}
+	}
+
+	return nil
+}
+
+func (c *TrdlChannels) Declaration() TrdlChannelsDeclaration {
+	declaration := TrdlChannelsDeclaration{
+		AllowedChannels: c.GetAllowedChannels(),
+		DefaultChannel:  c.GetDefaultChannel(),
+	}
+
+	for _, group := range c.Groups {
+		if len(group.AllowedChannels) == 0 {
+			continue
+		}
+
+		if declaration.Groups == nil {
+			declaration.Groups = make(map[string][]string)
+		}
+
+		declaration.Groups[group.Name] = group.AllowedChannels
+	}
+
+	return declaration
+}
+
+func ValidateChannelName(name string) error {
+	if !channelNameRegexp.MatchString(name) {
+		return fmt.Errorf("channel name %q must consist of lower case alphanumeric characters, '-' or '_', and must start and end with an alphanumeric character", name)
+	}
+
+	return nil
+}
+
+func validateChannelSet(channels []string) error {
+	for i, channel := range channels {
+		if err := ValidateChannelName(channel); err != nil {
+			return err
+		}
+
+		if lo.Contains(channels[:i], channel) {
+			return fmt.Errorf("duplicate channel %q found", channel)
+		}
+	}
+
+	return nil
+}
+
 func ParseTrdlChannels(data []byte) (*TrdlChannels, error) {
 	var res *TrdlChannels
 
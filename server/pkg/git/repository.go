@@ -187,3 +187,12 @@ func IsAncestor(gitRepo *git.Repository, ancestorCommit, descendantCommit string
 
 	return isAncestor, nil
 }
+
+func GetCommitMessage(gitRepo *git.Repository, commit string) (string, error) {
+	commitObj, err := gitRepo.CommitObject(plumbing.NewHash(commit))
+	if err != nil {
+		return "", fmt.Errorf("unable to get commit %q object: %w", commit, err)
+	}
+
+	return commitObj.Message, nil
+}
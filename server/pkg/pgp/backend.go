@@ -82,7 +82,7 @@ func Paths() []*framework.Path {
 }
 
 func pathConfigureTrustedPGPPublicKeyCreateOrUpdate(ctx context.Context, req *logical.Request, fields *framework.FieldData) (*logical.Response, error) {
-	if errResp := util.CheckRequiredFields(req, fields); errResp != nil {
+	if errResp := util.CheckRequiredFields(fields); errResp != nil {
 		return errResp, nil
 	}
 
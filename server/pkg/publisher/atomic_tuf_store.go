@@ -0,0 +1,242 @@
+package publisher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/theupdateframework/go-tuf"
+	"github.com/theupdateframework/go-tuf/data"
+	"github.com/theupdateframework/go-tuf/util"
+)
+
+const stagedTargetsDir = "staged/targets"
+
+// AtomicTufStore stages target files under content-addressed names outside the targets directory,
+// so that nothing visible to clients is changed until Commit.
+// On Commit target files are published first, then the metadata is written in a safe order
+// (root, targets, snapshot and timestamp last), so clients never see a half-published state.
+// With consistent snapshots the targets are published under the hash-prefixed names and the metadata under the versioned names,
+// thus the files the published timestamp.json points to are never overwritten and an interrupted commit changes nothing for clients.
+// Without consistent snapshots (repositories initialized by the non-atomic store) the targets are overwritten in place.
+type AtomicTufStore struct {
+	*NonAtomicTufStore
+
+	stagedTargets map[string]string
+	stagedObjects []string
+}
+
+func NewAtomicTufStore(privKeys TufRepoPrivKeys, filesystem Filesystem, logger hclog.Logger) *AtomicTufStore {
+	return &AtomicTufStore{
+		NonAtomicTufStore: NewNonAtomicTufStore(privKeys, filesystem, logger),
+		stagedTargets:     make(map[string]string),
+	}
+}
+
+var metadataCommitOrder = []string{
+	"root.json",
+	"targets.json",
+	"snapshot.json",
+	"timestamp.json",
+}
+
+func (store *AtomicTufStore) StageTargetFile(ctx context.Context, targetPath string, data io.Reader) error {
+	store.logger.Debug(fmt.Sprintf("-- AtomicTufStore.StageTargetFile %q", targetPath))
+
+	tmpFile, err := os.CreateTemp("", "trdl-staged-target-")
+	if err != nil {
+		return fmt.Errorf("unable to create temporary file: %w", err)
+	}
+	defer func() {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+	}()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmpFile, hasher), data); err != nil {
+		return fmt.Errorf("error reading %q: %w", targetPath, err)
+	}
+
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("unable to seek temporary file: %w", err)
+	}
+
+	stagedObjectPath := path.Join(stagedTargetsDir, fmt.Sprintf("%x", hasher.Sum(nil)))
+
+	if !store.isObjectStaged(stagedObjectPath) {
+		if err := store.Filesystem.WriteFileStream(ctx, stagedObjectPath, tmpFile); err != nil {
+			return fmt.Errorf("error writing %q into the store filesystem: %w", stagedObjectPath, err)
+		}
+
+		store.stagedObjects = append(store.stagedObjects, stagedObjectPath)
+	}
+
+	if _, isStaged := store.stagedTargets[targetPath]; !isStaged {
+		store.stagedFiles = append(store.stagedFiles, targetPath)
+	}
+	store.stagedTargets[targetPath] = stagedObjectPath
+
+	return nil
+}
+
+func (store *AtomicTufStore) isObjectStaged(stagedObjectPath string) bool {
+	for _, p := range store.stagedObjects {
+		if p == stagedObjectPath {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (store *AtomicTufStore) WalkStagedTargets(targetPathList []string, targetsFn tuf.TargetsWalkFunc) error {
+	store.logger.Debug(fmt.Sprintf("-- AtomicTufStore.WalkStagedTargets %v", targetPathList))
+
+	return walkStagedTargets(store.stagedFiles, targetPathList, func(targetPath string) io.Reader {
+		return store.pipedFileReader(context.Background(), store.stagedTargets[targetPath])
+	}, targetsFn)
+}
+
+func (store *AtomicTufStore) Commit(consistentSnapshot bool, versions map[string]int64, _ map[string]data.Hashes) error {
+	store.logger.Debug("-- AtomicTufStore.Commit")
+	ctx := context.Background()
+
+	for _, targetPath := range store.stagedFiles {
+		stagedObjectPath := store.stagedTargets[targetPath]
+
+		publishedPaths, err := store.computeTargetPaths(consistentSnapshot, targetPath)
+		if err != nil {
+			return err
+		}
+
+		for _, publishedPath := range publishedPaths {
+			store.logger.Debug(fmt.Sprintf("-- AtomicTufStore.Commit publishing staged target %q as %q", stagedObjectPath, publishedPath))
+
+			if err := store.Filesystem.WriteFileStream(ctx, publishedPath, store.pipedFileReader(ctx, stagedObjectPath)); err != nil {
+				return fmt.Errorf("error publishing staged target %q: %w", targetPath, err)
+			}
+		}
+	}
+
+	for _, name := range sortMetadataForCommit(store.stagedMeta) {
+		for _, metadataPath := range computeMetadataPaths(consistentSnapshot, name, versions) {
+			store.logger.Debug(fmt.Sprintf("-- AtomicTufStore.Commit storing metadata path %q into the filesystem", metadataPath))
+
+			if err := store.Filesystem.WriteFileBytes(ctx, metadataPath, store.stagedMeta[name]); err != nil {
+				return fmt.Errorf("error writing metadata path %q into the filesystem: %w", metadataPath, err)
+			}
+		}
+	}
+
+	// Published state is consistent at this point: staged objects cleanup errors are not fatal
+	store.deleteStagedObjects(ctx)
+	store.resetStaged()
+
+	return nil
+}
+
+// computeTargetPaths returns the paths the staged target is published to:
+// the hash-prefixed paths with consistent snapshots, the target path itself otherwise.
+func (store *AtomicTufStore) computeTargetPaths(consistentSnapshot bool, targetPath string) ([]string, error) {
+	publishedPath := path.Join("targets", targetPath)
+	if !consistentSnapshot {
+		return []string{publishedPath}, nil
+	}
+
+	for name, meta := range store.stagedMeta {
+		if !isTargetsMetadata(name) {
+			continue
+		}
+
+		t, err := decodeTargetsMeta(meta)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode %s: %w", name, err)
+		}
+
+		if fileMeta, ok := t.Targets[targetPath]; ok {
+			return util.HashedPaths(publishedPath, fileMeta.Hashes), nil
+		}
+	}
+
+	return nil, fmt.Errorf("staged target %q not found in the staged targets metadata", targetPath)
+}
+
+// isTargetsMetadata returns true for targets.json and the delegated targets metadata.
+func isTargetsMetadata(name string) bool {
+	switch name {
+	case "root.json", "snapshot.json", "timestamp.json":
+		return false
+	default:
+		return true
+	}
+}
+
+// DiscardStaged removes staged objects from the store filesystem and forgets staged changes.
+func (store *AtomicTufStore) DiscardStaged(ctx context.Context) error {
+	store.logger.Debug("-- AtomicTufStore.DiscardStaged")
+
+	failedObjects := store.deleteStagedObjects(ctx)
+	store.resetStaged()
+
+	if len(failedObjects) > 0 {
+		return fmt.Errorf("unable to delete staged objects %v", failedObjects)
+	}
+
+	return nil
+}
+
+func (store *AtomicTufStore) deleteStagedObjects(ctx context.Context) []string {
+	var failedObjects []string
+	for _, stagedObjectPath := range store.stagedObjects {
+		if err := store.Filesystem.DeleteFile(ctx, stagedObjectPath); err != nil {
+			store.logger.Warn(fmt.Sprintf("Unable to delete staged object %q: %s", stagedObjectPath, err))
+			failedObjects = append(failedObjects, stagedObjectPath)
+		}
+	}
+
+	return failedObjects
+}
+
+func (store *AtomicTufStore) resetStaged() {
+	store.stagedFiles = nil
+	store.stagedTargets = make(map[string]string)
+	store.stagedObjects = nil
+	store.stagedMeta = make(map[string]json.RawMessage)
+}
+
+// sortMetadataForCommit returns staged metadata names in the order they should be written:
+// root first, then unknown (delegated) metadata, then targets, snapshot and timestamp.
+func sortMetadataForCommit(stagedMeta map[string]json.RawMessage) []string {
+	orderOf := func(name string) int {
+		for i, n := range metadataCommitOrder {
+			if n == name {
+				// leave the place after root for the unknown metadata
+				if i == 0 {
+					return 0
+				}
+				return i + 1
+			}
+		}
+		return 1
+	}
+
+	var names []string
+	for name := range stagedMeta {
+		names = append(names, name)
+	}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		if orderOf(names[i]) != orderOf(names[j]) {
+			return orderOf(names[i]) < orderOf(names[j])
+		}
+		return names[i] < names[j]
+	})
+
+	return names
+}
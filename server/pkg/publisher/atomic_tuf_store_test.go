@@ -0,0 +1,141 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theupdateframework/go-tuf"
+)
+
+var _ = Describe("AtomicTufStore", func() {
+	var ctx context.Context
+	var fs *testMemoryFilesystem
+	var repository *S3Repository
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fs = newTestMemoryFilesystem()
+
+		tufStore := NewAtomicTufStore(TufRepoPrivKeys{}, fs, hclog.NewNullLogger())
+		tufRepo, err := tuf.NewRepo(tufStore)
+		Expect(err).To(Succeed())
+
+		repository = NewRepository(fs, tufStore.NonAtomicTufStore, tufRepo, hclog.NewNullLogger())
+		repository.StagingStore = tufStore
+		Expect(repository.Init()).To(Succeed())
+		Expect(repository.GenPrivKeys()).To(Succeed())
+	})
+
+	It("should not publish staged targets before commit", func() {
+		Expect(repository.StageTarget(ctx, "releases/1.0.0/any-any/bin/app", bytes.NewBufferString("app"))).To(Succeed())
+
+		Expect(fs.listFiles("targets/")).To(BeEmpty())
+		Expect(fs.listFiles(stagedTargetsDir)).To(HaveLen(1))
+
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		data, exist, err := repository.ReadTarget(ctx, "releases/1.0.0/any-any/bin/app")
+		Expect(err).To(Succeed())
+		Expect(exist).To(BeTrue())
+		Expect(string(data)).To(Equal("app"))
+
+		targets, err := repository.GetTargets(ctx)
+		Expect(err).To(Succeed())
+		Expect(targets).To(ConsistOf("releases/1.0.0/any-any/bin/app"))
+
+		Expect(fs.listFiles(stagedTargetsDir)).To(BeEmpty())
+	})
+
+	It("should stage targets with the same content once", func() {
+		Expect(repository.StageTarget(ctx, "releases/1.0.0/linux-amd64/bin/app", bytes.NewBufferString("app"))).To(Succeed())
+		Expect(repository.StageTarget(ctx, "releases/1.0.0/darwin-amd64/bin/app", bytes.NewBufferString("app"))).To(Succeed())
+		Expect(fs.listFiles(stagedTargetsDir)).To(HaveLen(1))
+
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+		Expect(fs.listFiles("targets/")).To(HaveLen(2))
+	})
+
+	It("should write metadata in the safe order", func() {
+		Expect(repository.StageTarget(ctx, "channels/0/stable", bytes.NewBufferString("1.0.0\n"))).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		var metadataWrites []string
+		for _, p := range fs.writes {
+			if strings.HasSuffix(p, ".json") {
+				metadataWrites = append(metadataWrites, p)
+			}
+		}
+		Expect(metadataWrites).To(Equal([]string{"root.json", "1.root.json", "targets.json", "1.targets.json", "snapshot.json", "1.snapshot.json", "timestamp.json"}))
+	})
+
+	It("should publish targets under the hash-prefixed names", func() {
+		Expect(repository.StageTarget(ctx, "channels/0/stable", bytes.NewBufferString("1.0.0\n"))).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		publishedFiles := fs.listFiles("targets/")
+		Expect(publishedFiles).To(HaveLen(1))
+		Expect(publishedFiles[0]).To(MatchRegexp(`^targets/channels/0/[0-9a-f]+\.stable$`))
+
+		Expect(repository.StageTarget(ctx, "channels/0/stable", bytes.NewBufferString("1.0.1\n"))).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		// the file the previous metadata points to is not overwritten
+		data, err := fs.ReadFileBytes(ctx, publishedFiles[0])
+		Expect(err).To(Succeed())
+		Expect(string(data)).To(Equal("1.0.0\n"))
+
+		data, exist, err := repository.ReadTarget(ctx, "channels/0/stable")
+		Expect(err).To(Succeed())
+		Expect(exist).To(BeTrue())
+		Expect(string(data)).To(Equal("1.0.1\n"))
+	})
+
+	It("should overwrite targets in place in the repository without consistent snapshots", func() {
+		fs = newTestMemoryFilesystem()
+
+		nonAtomicStore := NewNonAtomicTufStore(TufRepoPrivKeys{}, fs, hclog.NewNullLogger())
+		nonAtomicTufRepo, err := tuf.NewRepo(nonAtomicStore)
+		Expect(err).To(Succeed())
+
+		nonAtomicRepository := NewRepository(fs, nonAtomicStore, nonAtomicTufRepo, hclog.NewNullLogger())
+		Expect(nonAtomicRepository.Init()).To(Succeed())
+		Expect(nonAtomicRepository.GenPrivKeys()).To(Succeed())
+		Expect(nonAtomicRepository.StageTarget(ctx, "channels/0/stable", bytes.NewBufferString("1.0.0\n"))).To(Succeed())
+		Expect(nonAtomicRepository.CommitStaged(ctx)).To(Succeed())
+
+		tufStore := NewAtomicTufStore(nonAtomicRepository.GetPrivKeys(), fs, hclog.NewNullLogger())
+		Expect(tufStore.PrivKeys.SetupStoreSigners(tufStore)).To(Succeed())
+		tufRepo, err := tuf.NewRepo(tufStore)
+		Expect(err).To(Succeed())
+
+		repository = NewRepository(fs, tufStore.NonAtomicTufStore, tufRepo, hclog.NewNullLogger())
+		repository.StagingStore = tufStore
+		Expect(repository.Init()).To(Succeed())
+
+		Expect(repository.StageTarget(ctx, "channels/0/stable", bytes.NewBufferString("1.0.1\n"))).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		Expect(fs.listFiles("targets/")).To(ConsistOf("targets/channels/0/stable"))
+
+		data, exist, err := repository.ReadTarget(ctx, "channels/0/stable")
+		Expect(err).To(Succeed())
+		Expect(exist).To(BeTrue())
+		Expect(string(data)).To(Equal("1.0.1\n"))
+	})
+
+	It("should clean up staged objects when commit fails", func() {
+		Expect(repository.StageTarget(ctx, "channels/0/stable", bytes.NewBufferString("1.0.0\n"))).To(Succeed())
+
+		fs.writeErr = errors.New("broken filesystem")
+		Expect(repository.CommitStaged(ctx)).NotTo(Succeed())
+
+		Expect(fs.listFiles(stagedTargetsDir)).To(BeEmpty())
+		Expect(fs.listFiles("targets/")).To(BeEmpty())
+		Expect(fs.listFiles("timestamp.json")).To(BeEmpty())
+	})
+})
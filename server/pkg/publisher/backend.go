@@ -4,9 +4,25 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/werf/trdl/server/pkg/util"
+)
+
+const (
+	fieldNameMirrorName              = "name"
+	fieldNameMirrorS3Endpoint        = "s3_endpoint"
+	fieldNameMirrorS3Region          = "s3_region"
+	fieldNameMirrorS3AccessKeyID     = "s3_access_key_id"
+	fieldNameMirrorS3SecretAccessKey = "s3_secret_access_key"
+	fieldNameMirrorS3BucketName      = "s3_bucket_name"
+
+	fieldNameDelegatedRoleName      = "name"
+	fieldNameDelegatedRolePaths     = "paths"
+	fieldNameDelegatedRoleThreshold = "threshold"
 )
 
 func (publisher *Publisher) Paths() []*framework.Path {
@@ -27,6 +43,159 @@ func (publisher *Publisher) Paths() []*framework.Path {
 				},
 			},
 		},
+		{
+			Pattern:         "pgp_signing_key$",
+			HelpSynopsis:    "Get the public part of the PGP key for signing release artifacts",
+			HelpDescription: "Get the public part of the PGP key for signing release artifacts to verify release artifacts signatures on the client side",
+			Fields:          map[string]*framework.FieldSchema{},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Description: "Get the public part of the current PGP signing key",
+					Callback:    publisher.pathPGPSigningKeyRead,
+				},
+			},
+		},
+		{
+			Pattern:         "configure/mirror/?",
+			HelpSynopsis:    "Configure TUF repository mirrors",
+			HelpDescription: "Configure additional S3 storages the TUF repository is replicated to on every commit",
+			Fields:          map[string]*framework.FieldSchema{},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Description: "Get the list of mirrors",
+					Callback:    publisher.pathConfigureMirrorList,
+				},
+				logical.ListOperation: &framework.PathOperation{
+					Description: "Get the list of mirrors",
+					Callback:    publisher.pathConfigureMirrorList,
+				},
+			},
+		},
+		{
+			Pattern:         "configure/mirror/" + framework.GenericNameRegex(fieldNameMirrorName) + "$",
+			HelpSynopsis:    "Configure the TUF repository mirror",
+			HelpDescription: "Configure the S3 storage the TUF repository is replicated to on every commit (the whole repository is replicated on the next commit after the mirror is configured)",
+			Fields: map[string]*framework.FieldSchema{
+				fieldNameMirrorName: {
+					Type:        framework.TypeNameString,
+					Description: "Mirror name",
+					Required:    true,
+				},
+				fieldNameMirrorS3BucketName: {
+					Type:        framework.TypeString,
+					Description: "The S3 storage bucket name",
+					Required:    true,
+				},
+				fieldNameMirrorS3Endpoint: {
+					Type:        framework.TypeString,
+					Description: "The S3 storage endpoint",
+					Required:    true,
+				},
+				fieldNameMirrorS3Region: {
+					Type:        framework.TypeString,
+					Description: "The S3 storage region",
+					Required:    true,
+				},
+				fieldNameMirrorS3AccessKeyID: {
+					Type:        framework.TypeString,
+					Description: "The S3 storage access key id",
+					Required:    true,
+				},
+				fieldNameMirrorS3SecretAccessKey: {
+					Type:        framework.TypeString,
+					Description: "The S3 storage secret access key",
+					Required:    true,
+				},
+			},
+			ExistenceCheck: publisher.pathConfigureMirrorExistenceCheck,
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.CreateOperation: &framework.PathOperation{
+					Description: "Add the mirror",
+					Callback:    publisher.pathConfigureMirrorCreateOrUpdate,
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Description: "Update the mirror",
+					Callback:    publisher.pathConfigureMirrorCreateOrUpdate,
+				},
+				logical.ReadOperation: &framework.PathOperation{
+					Description: "Get the mirror configuration",
+					Callback:    publisher.pathConfigureMirrorRead,
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Description: "Delete the mirror",
+					Callback:    publisher.pathConfigureMirrorDelete,
+				},
+			},
+		},
+		{
+			Pattern:         "configure/delegated_role/?",
+			HelpSynopsis:    "Configure TUF repository delegated targets roles",
+			HelpDescription: "Configure targets roles with their own keys, the top-level targets role delegates signing of the targets matching the role paths to them",
+			Fields:          map[string]*framework.FieldSchema{},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Description: "Get the list of delegated roles",
+					Callback:    publisher.pathConfigureDelegatedRoleList,
+				},
+				logical.ListOperation: &framework.PathOperation{
+					Description: "Get the list of delegated roles",
+					Callback:    publisher.pathConfigureDelegatedRoleList,
+				},
+			},
+		},
+		{
+			Pattern:         "configure/delegated_role/" + framework.GenericNameRegex(fieldNameDelegatedRoleName) + "$",
+			HelpSynopsis:    "Configure the TUF repository delegated targets role",
+			HelpDescription: "Configure the targets role with its own keys, the top-level targets role delegates signing of the targets matching the role paths to it (changes are published with the next publish, release or periodic task)",
+			Fields: map[string]*framework.FieldSchema{
+				fieldNameDelegatedRoleName: {
+					Type:        framework.TypeNameString,
+					Description: "Delegated role name",
+					Required:    true,
+				},
+				fieldNameDelegatedRolePaths: {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Target path patterns the role is trusted to sign, where * matches any sequence of characters except /, thus the pattern matches the targets of the same depth only (e.g. channels/*/*). The trailing /** matches the targets of any depth up to 10 under the prefix (e.g. releases/**)",
+					Required:    true,
+				},
+				fieldNameDelegatedRoleThreshold: {
+					Type:        framework.TypeInt,
+					Description: "The number of role keys required to sign the role metadata",
+					Default:     1,
+				},
+			},
+			ExistenceCheck: publisher.pathConfigureDelegatedRoleExistenceCheck,
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.CreateOperation: &framework.PathOperation{
+					Description: "Add the delegated role",
+					Callback:    publisher.pathConfigureDelegatedRoleCreateOrUpdate,
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Description: "Update the delegated role",
+					Callback:    publisher.pathConfigureDelegatedRoleCreateOrUpdate,
+				},
+				logical.ReadOperation: &framework.PathOperation{
+					Description: "Get the delegated role configuration",
+					Callback:    publisher.pathConfigureDelegatedRoleRead,
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Description: "Delete the delegated role (its targets are moved back to the top-level targets role)",
+					Callback:    publisher.pathConfigureDelegatedRoleDelete,
+				},
+			},
+		},
+		{
+			Pattern:         "mirror/status$",
+			HelpSynopsis:    "Get TUF repository mirrors sync status",
+			HelpDescription: "Get TUF repository mirrors sync status and lag behind the primary storage",
+			Fields:          map[string]*framework.FieldSchema{},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Description: "Get mirrors sync status",
+					Callback:    publisher.pathMirrorStatusRead,
+				},
+			},
+		},
 	}
 }
 
@@ -48,9 +217,208 @@ func (publisher *Publisher) pathConfigurePGPSigningKeyRead(ctx context.Context,
 	}, nil
 }
 
+func (publisher *Publisher) pathPGPSigningKeyRead(ctx context.Context, req *logical.Request, fields *framework.FieldData) (*logical.Response, error) {
+	key, err := publisher.fetchPGPSigningKey(ctx, req.Storage, false)
+	if err == ErrUninitializedPGPSigningKey {
+		return logical.ErrorResponse("PGP signing key is not initialized"), nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error fetching pgp signing key: %w", err)
+	}
+
+	pk := bytes.NewBuffer(nil)
+	if err := key.SerializePublicKey(pk); err != nil {
+		return nil, fmt.Errorf("unable to get public key text: %w", err)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"public_key": pk.String(),
+		},
+	}, nil
+}
+
 func (publisher *Publisher) pathConfigurePGPSigningKeyDelete(ctx context.Context, req *logical.Request, fields *framework.FieldData) (*logical.Response, error) {
 	if err := publisher.deletePGPSigningKey(ctx, req.Storage); err != nil {
 		return nil, fmt.Errorf("error deleting pgp signing key: %w", err)
 	}
 	return &logical.Response{Data: map[string]interface{}{}}, nil
 }
+
+func (publisher *Publisher) pathConfigureMirrorList(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	list, err := req.Storage.List(ctx, storageKeyPrefixMirror)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list %q in storage: %w", storageKeyPrefixMirror, err)
+	}
+
+	return logical.ListResponse(list), nil
+}
+
+func (publisher *Publisher) pathConfigureMirrorExistenceCheck(ctx context.Context, req *logical.Request, fields *framework.FieldData) (bool, error) {
+	mirror, err := GetMirror(ctx, req.Storage, fields.Get(fieldNameMirrorName).(string))
+	if err != nil {
+		return false, err
+	}
+
+	return mirror != nil, nil
+}
+
+func (publisher *Publisher) pathConfigureMirrorCreateOrUpdate(ctx context.Context, req *logical.Request, fields *framework.FieldData) (*logical.Response, error) {
+	if errResp := util.CheckRequiredFields(fields); errResp != nil {
+		return errResp, nil
+	}
+
+	mirror := &Mirror{
+		Name:              fields.Get(fieldNameMirrorName).(string),
+		S3Endpoint:        fields.Get(fieldNameMirrorS3Endpoint).(string),
+		S3Region:          fields.Get(fieldNameMirrorS3Region).(string),
+		S3AccessKeyID:     fields.Get(fieldNameMirrorS3AccessKeyID).(string),
+		S3SecretAccessKey: fields.Get(fieldNameMirrorS3SecretAccessKey).(string),
+		S3BucketName:      fields.Get(fieldNameMirrorS3BucketName).(string),
+	}
+
+	if err := PutMirror(ctx, req.Storage, mirror, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (publisher *Publisher) pathConfigureMirrorRead(ctx context.Context, req *logical.Request, fields *framework.FieldData) (*logical.Response, error) {
+	name := fields.Get(fieldNameMirrorName).(string)
+
+	mirror, err := GetMirror(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if mirror == nil {
+		return logical.ErrorResponse("Mirror %q not found in storage", name), nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			fieldNameMirrorName:          mirror.Name,
+			fieldNameMirrorS3Endpoint:    mirror.S3Endpoint,
+			fieldNameMirrorS3Region:      mirror.S3Region,
+			fieldNameMirrorS3AccessKeyID: mirror.S3AccessKeyID,
+			fieldNameMirrorS3BucketName:  mirror.S3BucketName,
+		},
+	}, nil
+}
+
+func (publisher *Publisher) pathConfigureMirrorDelete(ctx context.Context, req *logical.Request, fields *framework.FieldData) (*logical.Response, error) {
+	if err := DeleteMirror(ctx, req.Storage, fields.Get(fieldNameMirrorName).(string)); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (publisher *Publisher) pathMirrorStatusRead(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	mirrors, err := GetMirrors(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	mirrorsData := make(map[string]interface{})
+	for _, mirror := range mirrors {
+		status, err := GetMirrorStatus(ctx, req.Storage, mirror.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if status == nil {
+			status = &MirrorStatus{}
+		}
+
+		mirrorData := map[string]interface{}{
+			"in_sync":         status.InSync(),
+			"lag_seconds":     int64(status.Lag(now).Seconds()),
+			"last_sync_error": status.LastSyncError,
+		}
+		if !status.LastSyncedAt.IsZero() {
+			mirrorData["last_synced_at"] = status.LastSyncedAt.Format(time.RFC3339)
+		}
+		if !status.LastSyncAttemptAt.IsZero() {
+			mirrorData["last_sync_attempt_at"] = status.LastSyncAttemptAt.Format(time.RFC3339)
+		}
+
+		mirrorsData[mirror.Name] = mirrorData
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"mirrors": mirrorsData,
+		},
+	}, nil
+}
+
+func (publisher *Publisher) pathConfigureDelegatedRoleList(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	list, err := req.Storage.List(ctx, storageKeyPrefixDelegatedRole)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list %q in storage: %w", storageKeyPrefixDelegatedRole, err)
+	}
+
+	return logical.ListResponse(list), nil
+}
+
+func (publisher *Publisher) pathConfigureDelegatedRoleExistenceCheck(ctx context.Context, req *logical.Request, fields *framework.FieldData) (bool, error) {
+	role, err := GetDelegatedRole(ctx, req.Storage, fields.Get(fieldNameDelegatedRoleName).(string))
+	if err != nil {
+		return false, err
+	}
+
+	return role != nil, nil
+}
+
+func (publisher *Publisher) pathConfigureDelegatedRoleCreateOrUpdate(ctx context.Context, req *logical.Request, fields *framework.FieldData) (*logical.Response, error) {
+	if errResp := util.CheckRequiredFields(fields); errResp != nil {
+		return errResp, nil
+	}
+
+	role := &DelegatedRole{
+		Name:      fields.Get(fieldNameDelegatedRoleName).(string),
+		Paths:     fields.Get(fieldNameDelegatedRolePaths).([]string),
+		Threshold: fields.Get(fieldNameDelegatedRoleThreshold).(int),
+	}
+
+	if err := role.Validate(); err != nil {
+		return logical.ErrorResponse("Invalid delegated role: %s", err), nil
+	}
+
+	if err := PutDelegatedRole(ctx, req.Storage, role); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (publisher *Publisher) pathConfigureDelegatedRoleRead(ctx context.Context, req *logical.Request, fields *framework.FieldData) (*logical.Response, error) {
+	name := fields.Get(fieldNameDelegatedRoleName).(string)
+
+	role, err := GetDelegatedRole(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if role == nil {
+		return logical.ErrorResponse("Delegated role %q not found in storage", name), nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			fieldNameDelegatedRoleName:      role.Name,
+			fieldNameDelegatedRolePaths:     role.Paths,
+			fieldNameDelegatedRoleThreshold: role.Threshold,
+		},
+	}, nil
+}
+
+func (publisher *Publisher) pathConfigureDelegatedRoleDelete(ctx context.Context, req *logical.Request, fields *framework.FieldData) (*logical.Response, error) {
+	if err := DeleteDelegatedRole(ctx, req.Storage, fields.Get(fieldNameDelegatedRoleName).(string)); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,135 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/werf/trdl/server/pkg/config"
+)
+
+// ChannelsHistoryTargetName is the target with the history of the published channels
+const ChannelsHistoryTargetName = "trdl_channels_history.json"
+
+// ChannelsHistory keeps the versions of each group channel in order of publishing.
+type ChannelsHistory struct {
+	Groups map[string]map[string][]*ChannelsHistoryEntry `json:"groups"`
+}
+
+type ChannelsHistoryEntry struct {
+	Version     string    `json:"version"`
+	PublishedAt time.Time `json:"publishedAt"`
+	GitCommit   string    `json:"gitCommit,omitempty"`
+	Rollback    bool      `json:"rollback,omitempty"`
+}
+
+func NewChannelsHistory() *ChannelsHistory {
+	return &ChannelsHistory{Groups: make(map[string]map[string][]*ChannelsHistoryEntry)}
+}
+
+func (history *ChannelsHistory) IsEmpty() bool {
+	return len(history.Groups) == 0
+}
+
+func (history *ChannelsHistory) GetEntries(group, channel string) []*ChannelsHistoryEntry {
+	return history.Groups[group][channel]
+}
+
+// GetCurrentEntry returns the last published entry of the channel or nil.
+func (history *ChannelsHistory) GetCurrentEntry(group, channel string) *ChannelsHistoryEntry {
+	entries := history.GetEntries(group, channel)
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return entries[len(entries)-1]
+}
+
+// GetVersionDuration returns the total time the channel has pointed to the version.
+func (history *ChannelsHistory) GetVersionDuration(group, channel, version string, now time.Time) time.Duration {
+	var duration time.Duration
+
+	entries := history.GetEntries(group, channel)
+	for i, entry := range entries {
+		if entry.Version != version {
+			continue
+		}
+
+		until := now
+		if i+1 < len(entries) {
+			until = entries[i+1].PublishedAt
+		}
+
+		duration += until.Sub(entry.PublishedAt)
+	}
+
+	return duration
+}
+
+// Update appends the entries of the channels which versions have been changed by the config.
+func (history *ChannelsHistory) Update(trdlChannelsConfig *config.TrdlChannels, gitCommit string, rollback bool, now time.Time) bool {
+	var updated bool
+
+	for _, grp := range trdlChannelsConfig.Groups {
+		for _, chnl := range grp.Channels {
+			if current := history.GetCurrentEntry(grp.Name, chnl.Name); current != nil && current.Version == chnl.Version {
+				continue
+			}
+
+			if history.Groups[grp.Name] == nil {
+				history.Groups[grp.Name] = make(map[string][]*ChannelsHistoryEntry)
+			}
+
+			history.Groups[grp.Name][chnl.Name] = append(history.Groups[grp.Name][chnl.Name], &ChannelsHistoryEntry{
+				Version:     chnl.Version,
+				PublishedAt: now.UTC(),
+				GitCommit:   gitCommit,
+				Rollback:    rollback,
+			})
+
+			updated = true
+		}
+	}
+
+	return updated
+}
+
+func (publisher *Publisher) GetChannelsHistory(ctx context.Context, repository RepositoryInterface) (*ChannelsHistory, error) {
+	data, exist, err := repository.ReadTarget(ctx, ChannelsHistoryTargetName)
+	if err != nil {
+		return nil, err
+	}
+
+	history := NewChannelsHistory()
+	if !exist {
+		return history, nil
+	}
+
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal %q: %w", ChannelsHistoryTargetName, err)
+	}
+
+	if history.Groups == nil {
+		history.Groups = make(map[string]map[string][]*ChannelsHistoryEntry)
+	}
+
+	return history, nil
+}
+
+func (publisher *Publisher) StageChannelsHistory(ctx context.Context, repository RepositoryInterface, history *ChannelsHistory) error {
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+
+	data, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("unable to marshal channels history: %w", err)
+	}
+
+	if err := repository.StageTarget(ctx, ChannelsHistoryTargetName, bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("error publishing %q: %w", ChannelsHistoryTargetName, err)
+	}
+
+	return nil
+}
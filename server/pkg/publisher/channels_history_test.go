@@ -0,0 +1,82 @@
+package publisher
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/werf/trdl/server/pkg/config"
+)
+
+var _ = Describe("ChannelsHistory", func() {
+	var ctx context.Context
+	var repository *S3Repository
+	var publisher *Publisher
+
+	now := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	trdlChannels := func(eaVersion, stableVersion string) *config.TrdlChannels {
+		return &config.TrdlChannels{Groups: []config.TrdlGroup{{
+			Name: "1",
+			Channels: []config.TrdlGroupChannel{
+				{Name: "ea", Version: eaVersion},
+				{Name: "stable", Version: stableVersion},
+			},
+		}}}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		publisher = NewPublisher(hclog.NewNullLogger())
+
+		var err error
+		repository, err = NewRepositoryWithOptions(newTestMemoryFilesystem(), TufRepoOptions{}, hclog.NewNullLogger())
+		Expect(err).To(Succeed())
+		Expect(repository.Init()).To(Succeed())
+		Expect(repository.GenPrivKeys()).To(Succeed())
+	})
+
+	It("should be empty until published", func() {
+		history, err := publisher.GetChannelsHistory(ctx, repository)
+		Expect(err).To(Succeed())
+		Expect(history.IsEmpty()).To(BeTrue())
+	})
+
+	It("should append the changed channels only", func() {
+		history := NewChannelsHistory()
+		Expect(history.Update(trdlChannels("1.1.0", "1.0.0"), "commit1", false, now)).To(BeTrue())
+		Expect(history.Update(trdlChannels("1.1.0", "1.0.0"), "commit2", false, now.Add(time.Hour))).To(BeFalse())
+		Expect(history.Update(trdlChannels("1.2.0", "1.0.0"), "commit3", false, now.Add(2*time.Hour))).To(BeTrue())
+
+		Expect(history.GetEntries("1", "stable")).To(HaveLen(1))
+		Expect(history.GetEntries("1", "ea")).To(HaveLen(2))
+		Expect(history.GetCurrentEntry("1", "ea")).To(Equal(&ChannelsHistoryEntry{Version: "1.2.0", PublishedAt: now.Add(2 * time.Hour), GitCommit: "commit3"}))
+		Expect(history.GetCurrentEntry("1", "alpha")).To(BeNil())
+	})
+
+	It("should count the total time the channel pointed to the version", func() {
+		history := NewChannelsHistory()
+		history.Update(trdlChannels("1.1.0", "1.0.0"), "commit1", false, now)
+		history.Update(trdlChannels("1.2.0", "1.0.0"), "commit2", false, now.Add(time.Hour))
+		history.Update(trdlChannels("1.1.0", "1.0.0"), "commit3", true, now.Add(3*time.Hour))
+
+		Expect(history.GetVersionDuration("1", "ea", "1.1.0", now.Add(4*time.Hour))).To(Equal(2 * time.Hour))
+		Expect(history.GetVersionDuration("1", "ea", "1.2.0", now.Add(4*time.Hour))).To(Equal(2 * time.Hour))
+		Expect(history.GetVersionDuration("1", "ea", "1.3.0", now.Add(4*time.Hour))).To(BeZero())
+	})
+
+	It("should be published into the repository", func() {
+		history := NewChannelsHistory()
+		history.Update(trdlChannels("1.1.0", "1.0.0"), "commit1", false, now)
+
+		Expect(publisher.StageChannelsHistory(ctx, repository, history)).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		publishedHistory, err := publisher.GetChannelsHistory(ctx, repository)
+		Expect(err).To(Succeed())
+		Expect(publishedHistory).To(Equal(history))
+	})
+})
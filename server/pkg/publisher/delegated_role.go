@@ -0,0 +1,161 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const storageKeyPrefixDelegatedRole = "delegated_role/"
+
+var delegatedRoleNameRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9_-]*[a-z0-9])?$`)
+
+const (
+	// recursivePathPatternSuffix matches the targets of any depth under the prefix, e.g. releases/**
+	recursivePathPatternSuffix = "/**"
+	// maxRecursivePathPatternDepth is the depth the recursive pattern is expanded to,
+	// TUF clients match the target path by the pattern of the same depth only
+	maxRecursivePathPatternDepth = 10
+)
+
+// DelegatedRole is a targets role with its own keys,
+// the top-level targets role delegates signing of the targets matching the paths to it.
+type DelegatedRole struct {
+	Name string `json:"name"`
+	// Paths are TUF path patterns, "*" matches any sequence of characters except "/",
+	// thus the pattern matches the targets of the same depth only.
+	// The trailing "/**" matches the targets of any depth up to maxRecursivePathPatternDepth under the prefix
+	Paths     []string `json:"paths"`
+	Threshold int      `json:"threshold"`
+}
+
+func (role *DelegatedRole) Validate() error {
+	if !delegatedRoleNameRegexp.MatchString(role.Name) {
+		return fmt.Errorf("invalid delegated role name %q: expected lowercase letters, digits, dashes and underscores", role.Name)
+	}
+
+	for _, topLevelRole := range topLevelRoles {
+		if role.Name == topLevelRole {
+			return fmt.Errorf("invalid delegated role name %q: top-level role name cannot be used", role.Name)
+		}
+	}
+
+	if len(role.Paths) == 0 {
+		return fmt.Errorf("delegated role %q paths must be set", role.Name)
+	}
+
+	for _, pattern := range role.Paths {
+		if strings.Contains(strings.TrimSuffix(pattern, recursivePathPatternSuffix), "**") {
+			return fmt.Errorf("invalid delegated role %q path pattern %q: \"**\" is supported only as the trailing %q", role.Name, pattern, recursivePathPatternSuffix)
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid delegated role %q path pattern %q: %w", role.Name, pattern, err)
+		}
+	}
+
+	if role.Threshold < 1 {
+		return fmt.Errorf("delegated role %q threshold must be positive", role.Name)
+	}
+
+	return nil
+}
+
+// TufPaths returns the path patterns published in the TUF metadata,
+// the recursive pattern is expanded into the patterns of each depth since TUF clients do not support it.
+func (role *DelegatedRole) TufPaths() []string {
+	var result []string
+	for _, pattern := range role.Paths {
+		if !strings.HasSuffix(pattern, recursivePathPatternSuffix) {
+			result = append(result, pattern)
+			continue
+		}
+
+		prefix := strings.TrimSuffix(pattern, recursivePathPatternSuffix)
+		for depth := 1; depth <= maxRecursivePathPatternDepth; depth++ {
+			result = append(result, prefix+strings.Repeat("/*", depth))
+		}
+	}
+
+	return result
+}
+
+func (role *DelegatedRole) MatchesPath(targetPath string) bool {
+	for _, pattern := range role.TufPaths() {
+		if matched, _ := path.Match(pattern, targetPath); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func delegatedRoleStorageKey(name string) string {
+	return storageKeyPrefixDelegatedRole + name
+}
+
+// GetDelegatedRoles returns delegated roles ordered by name:
+// the target matching the paths of several roles is signed by the first one.
+func GetDelegatedRoles(ctx context.Context, storage logical.Storage) ([]*DelegatedRole, error) {
+	names, err := storage.List(ctx, storageKeyPrefixDelegatedRole)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list %q in storage: %w", storageKeyPrefixDelegatedRole, err)
+	}
+
+	var roles []*DelegatedRole
+	for _, name := range names {
+		role, err := GetDelegatedRole(ctx, storage, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if role != nil {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles, nil
+}
+
+func GetDelegatedRole(ctx context.Context, storage logical.Storage, name string) (*DelegatedRole, error) {
+	entry, err := storage.Get(ctx, delegatedRoleStorageKey(name))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get delegated role %q from storage: %w", name, err)
+	}
+
+	if entry == nil {
+		return nil, nil
+	}
+
+	role := new(DelegatedRole)
+	if err := entry.DecodeJSON(role); err != nil {
+		return nil, fmt.Errorf("unable to decode delegated role %q: %w", name, err)
+	}
+
+	return role, nil
+}
+
+func PutDelegatedRole(ctx context.Context, storage logical.Storage, role *DelegatedRole) error {
+	entry, err := logical.StorageEntryJSON(delegatedRoleStorageKey(role.Name), role)
+	if err != nil {
+		return fmt.Errorf("error creating storage json entry by key %q: %w", delegatedRoleStorageKey(role.Name), err)
+	}
+
+	if err := storage.Put(ctx, entry); err != nil {
+		return fmt.Errorf("unable to put delegated role %q into storage: %w", role.Name, err)
+	}
+
+	return nil
+}
+
+func DeleteDelegatedRole(ctx context.Context, storage logical.Storage, name string) error {
+	if err := storage.Delete(ctx, delegatedRoleStorageKey(name)); err != nil {
+		return fmt.Errorf("unable to delete delegated role %q from storage: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theupdateframework/go-tuf/data"
+)
+
+var _ = Describe("DelegatedRole", func() {
+	DescribeTable("should validate the role",
+		func(role *DelegatedRole, expectedErr string) {
+			err := role.Validate()
+			if expectedErr == "" {
+				Expect(err).To(Succeed())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+			}
+		},
+		Entry("valid", &DelegatedRole{Name: "releases", Paths: []string{"releases/*/*/*/*"}, Threshold: 1}, ""),
+		Entry("top-level role name", &DelegatedRole{Name: "targets", Paths: []string{"releases/*"}, Threshold: 1}, "top-level role name"),
+		Entry("name with dots", &DelegatedRole{Name: "1.root", Paths: []string{"releases/*"}, Threshold: 1}, "invalid delegated role name"),
+		Entry("no paths", &DelegatedRole{Name: "releases", Threshold: 1}, "paths must be set"),
+		Entry("bad path pattern", &DelegatedRole{Name: "releases", Paths: []string{"releases/["}, Threshold: 1}, "path pattern"),
+		Entry("recursive pattern", &DelegatedRole{Name: "releases", Paths: []string{"releases/**"}, Threshold: 1}, ""),
+		Entry("recursive pattern in the middle", &DelegatedRole{Name: "releases", Paths: []string{"releases/**/bin/*"}, Threshold: 1}, "supported only as the trailing"),
+		Entry("zero threshold", &DelegatedRole{Name: "releases", Paths: []string{"releases/*"}}, "threshold must be positive"),
+	)
+
+	DescribeTable("should match the target path",
+		func(pattern, targetPath string, expected bool) {
+			role := &DelegatedRole{Name: "releases", Paths: []string{pattern}, Threshold: 1}
+			Expect(role.MatchesPath(targetPath)).To(Equal(expected))
+
+			// TUF clients match by the published patterns
+			tufRole := data.DelegatedRole{Name: role.Name, Paths: role.TufPaths()}
+			Expect(tufRole.MatchesPath(targetPath)).To(Equal(expected))
+		},
+		Entry("same depth", "releases/*/*/*/*", "releases/1.0.0/linux-amd64/bin/app", true),
+		Entry("other depth", "releases/*", "releases/1.0.0/linux-amd64/bin/app", false),
+		Entry("recursive", "releases/**", "releases/1.0.0/linux-amd64/bin/app", true),
+		Entry("recursive direct child", "releases/**", "releases/1.0.0", true),
+		Entry("recursive other prefix", "releases/**", "channels/0/stable", false),
+		Entry("recursive prefix itself", "releases/**", "releases", false),
+	)
+
+	It("should be configured by the path", func() {
+		ctx := context.Background()
+		storage := &logical.InmemStorage{}
+		backend := &framework.Backend{Paths: NewPublisher(hclog.NewNullLogger()).Paths()}
+
+		resp, err := backend.HandleRequest(ctx, &logical.Request{
+			Operation: logical.CreateOperation,
+			Path:      "configure/delegated_role/releases",
+			Storage:   storage,
+			Data:      map[string]interface{}{"paths": "releases/*/*/*/*,signatures/*/*/*/*", "threshold": 2},
+		})
+		Expect(err).To(Succeed())
+		Expect(resp).To(BeNil())
+
+		roles, err := GetDelegatedRoles(ctx, storage)
+		Expect(err).To(Succeed())
+		Expect(roles).To(Equal([]*DelegatedRole{{Name: "releases", Paths: []string{"releases/*/*/*/*", "signatures/*/*/*/*"}, Threshold: 2}}))
+
+		resp, err = backend.HandleRequest(ctx, &logical.Request{
+			Operation: logical.CreateOperation,
+			Path:      "configure/delegated_role/snapshot",
+			Storage:   storage,
+			Data:      map[string]interface{}{"paths": "channels/*/*"},
+		})
+		Expect(err).To(Succeed())
+		Expect(resp.IsError()).To(BeTrue())
+	})
+})
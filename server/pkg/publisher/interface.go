@@ -2,9 +2,11 @@ package publisher
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 
 	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/theupdateframework/go-tuf/data"
 
 	"github.com/werf/trdl/server/pkg/config"
 	"github.com/werf/trdl/server/pkg/util"
@@ -13,11 +15,19 @@ import (
 type Interface interface {
 	GetRepository(ctx context.Context, storage logical.Storage, options RepositoryOptions) (RepositoryInterface, error)
 	RotateRepositoryKeys(ctx context.Context, storage logical.Storage, repository RepositoryInterface, systemClock util.Clock) error
+	UpdateDelegatedRoles(ctx context.Context, storage logical.Storage, repository RepositoryInterface) error
 	UpdateTimestamps(ctx context.Context, storage logical.Storage, repository RepositoryInterface, systemClock util.Clock) error
-	StageReleaseTarget(ctx context.Context, repository RepositoryInterface, releaseName, path string, data io.Reader) error
+	AddRootKey(ctx context.Context, storage logical.Storage, repository RepositoryInterface, key *data.PublicKey) error
+	AddRootSignature(ctx context.Context, storage logical.Storage, repository RepositoryInterface, signature data.Signature) (bool, error)
+	StageReleaseTarget(ctx context.Context, repository RepositoryInterface, releaseName, path string, data io.Reader, platforms []Platform) error
 	StageChannelsConfig(ctx context.Context, repository RepositoryInterface, trdlChannelsConfig *config.TrdlChannels) error
 	StageInMemoryFiles(ctx context.Context, repository RepositoryInterface, files []*InMemoryFile) error
 	GetExistingReleases(ctx context.Context, repository RepositoryInterface) ([]string, error)
+	GetChannelsHistory(ctx context.Context, repository RepositoryInterface) (*ChannelsHistory, error)
+	StageChannelsHistory(ctx context.Context, repository RepositoryInterface, history *ChannelsHistory) error
+	GetReleasesIndex(ctx context.Context, repository RepositoryInterface) (*ReleasesIndex, error)
+	StageReleasesIndex(ctx context.Context, repository RepositoryInterface, release ReleaseInfo) error
+	SyncReleasesIndex(ctx context.Context, repository RepositoryInterface) error
 }
 
 type RepositoryInterface interface {
@@ -25,9 +35,17 @@ type RepositoryInterface interface {
 	SetPrivKeys(privKeys TufRepoPrivKeys) error
 	GetPrivKeys() TufRepoPrivKeys
 	GenPrivKeys() error
-	RotatePrivKeys(ctx context.Context) (bool, TufRepoPrivKeys, error)
+	RotatePrivKeys(ctx context.Context, systemClock util.Clock) (bool, TufRepoPrivKeys, error)
+	UpdateDelegatedRoles(ctx context.Context, roles []*DelegatedRole) (bool, TufRepoPrivKeys, error)
 	UpdateTimestamps(ctx context.Context, systemClock util.Clock) error
 	StageTarget(ctx context.Context, pathInsideTargets string, data io.Reader) error
 	CommitStaged(ctx context.Context) error
+	DiscardStaged(ctx context.Context) error
 	GetTargets(ctx context.Context) ([]string, error)
+	ReadTarget(ctx context.Context, pathInsideTargets string) ([]byte, bool, error)
+	GetRootMeta(ctx context.Context) ([]byte, int64, error)
+	GetRolesStatus(ctx context.Context) ([]*TufRoleStatus, error)
+	AddRootKey(ctx context.Context, key *data.PublicKey) error
+	AddRootSignature(ctx context.Context, rootData json.RawMessage, signature data.Signature) (json.RawMessage, bool, error)
+	CommitRoot(ctx context.Context, rootData json.RawMessage, privKeys *TufRepoPrivKeys) error
 }
@@ -0,0 +1,150 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// LocalFilesystem stores files in the local directory, which can be served by any static web server.
+type LocalFilesystem struct {
+	RootDir string
+
+	logger hclog.Logger
+}
+
+func NewLocalFilesystem(rootDir string, logger hclog.Logger) *LocalFilesystem {
+	return &LocalFilesystem{RootDir: rootDir, logger: logger}
+}
+
+func (fs *LocalFilesystem) IsFileExist(_ context.Context, path string) (bool, error) {
+	_, err := os.Stat(fs.fullPath(path))
+	fs.logger.Debug(fmt.Sprintf("-- LocalFilesystem.IsFileExist %q err=%v", path, err))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error checking file %q: %w", path, err)
+	}
+
+	return true, nil
+}
+
+func (fs *LocalFilesystem) ReadFile(_ context.Context, path string, writerAt io.WriterAt) error {
+	f, err := os.Open(fs.fullPath(path))
+	if err != nil {
+		return fmt.Errorf("unable to open file %q: %w", path, err)
+	}
+	defer f.Close()
+
+	buf := make([]byte, 1024*1024)
+	var offset int64
+	for {
+		n, readErr := f.Read(buf)
+		if n > 0 {
+			if _, err := writerAt.WriteAt(buf[:n], offset); err != nil {
+				return fmt.Errorf("unable to write file %q data: %w", path, err)
+			}
+			offset += int64(n)
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("unable to read file %q: %w", path, readErr)
+		}
+	}
+
+	fs.logger.Debug(fmt.Sprintf("-- LocalFilesystem.ReadFile read %q %d bytes", path, offset))
+
+	return nil
+}
+
+func (fs *LocalFilesystem) ReadFileStream(_ context.Context, path string, writer io.Writer) error {
+	f, err := os.Open(fs.fullPath(path))
+	if err != nil {
+		return fmt.Errorf("unable to open file %q: %w", path, err)
+	}
+	defer f.Close()
+
+	numBytes, err := io.Copy(writer, f)
+	if err != nil {
+		return fmt.Errorf("unable to read file %q: %w", path, err)
+	}
+
+	fs.logger.Debug(fmt.Sprintf("-- LocalFilesystem.ReadFileStream read %q %d bytes", path, numBytes))
+
+	return nil
+}
+
+func (fs *LocalFilesystem) ReadFileBytes(_ context.Context, path string) ([]byte, error) {
+	data, err := os.ReadFile(fs.fullPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file %q: %w", path, err)
+	}
+
+	return data, nil
+}
+
+func (fs *LocalFilesystem) WriteFileBytes(ctx context.Context, path string, data []byte) error {
+	return fs.WriteFileStream(ctx, path, bytes.NewReader(data))
+}
+
+// WriteFileStream writes data into the temporary file and renames it,
+// so that the web server never serves a partially written file.
+func (fs *LocalFilesystem) WriteFileStream(_ context.Context, path string, data io.Reader) error {
+	fullPath := fs.fullPath(path)
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return fmt.Errorf("unable to create directory for %q: %w", path, err)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(fullPath), ".tmp-"+filepath.Base(fullPath)+"-")
+	if err != nil {
+		return fmt.Errorf("unable to create temporary file for %q: %w", path, err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := io.Copy(tmpFile, data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("error writing %q: %w", path, err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("error writing %q: %w", path, err)
+	}
+
+	if err := os.Chmod(tmpFile.Name(), 0o644); err != nil {
+		return fmt.Errorf("unable to set permissions for %q: %w", path, err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), fullPath); err != nil {
+		return fmt.Errorf("unable to rename temporary file to %q: %w", path, err)
+	}
+
+	fs.logger.Debug(fmt.Sprintf("-- LocalFilesystem.WriteFileStream written %q", fullPath))
+
+	return nil
+}
+
+func (fs *LocalFilesystem) DeleteFile(_ context.Context, path string) error {
+	if err := os.Remove(fs.fullPath(path)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to delete file %q: %w", path, err)
+	}
+
+	fs.logger.Debug(fmt.Sprintf("-- LocalFilesystem.DeleteFile deleted %q", path))
+
+	return nil
+}
+
+// fullPath never points outside the root directory.
+func (fs *LocalFilesystem) fullPath(p string) string {
+	return filepath.Join(fs.RootDir, filepath.FromSlash(path.Clean("/"+p)))
+}
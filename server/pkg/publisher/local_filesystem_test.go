@@ -0,0 +1,63 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-hclog"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LocalFilesystem", func() {
+	var ctx context.Context
+	var rootDir string
+	var fs *LocalFilesystem
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		rootDir = GinkgoT().TempDir()
+		fs = NewLocalFilesystem(rootDir, hclog.NewNullLogger())
+	})
+
+	It("should write and read files", func() {
+		exists, err := fs.IsFileExist(ctx, "targets/channels/0/stable")
+		Expect(err).To(Succeed())
+		Expect(exists).To(BeFalse())
+
+		Expect(fs.WriteFileStream(ctx, "targets/channels/0/stable", bytes.NewBufferString("1.0.0\n"))).To(Succeed())
+
+		exists, err = fs.IsFileExist(ctx, "targets/channels/0/stable")
+		Expect(err).To(Succeed())
+		Expect(exists).To(BeTrue())
+
+		data, err := fs.ReadFileBytes(ctx, "targets/channels/0/stable")
+		Expect(err).To(Succeed())
+		Expect(string(data)).To(Equal("1.0.0\n"))
+
+		buf := bytes.NewBuffer(nil)
+		Expect(fs.ReadFileStream(ctx, "targets/channels/0/stable", buf)).To(Succeed())
+		Expect(buf.String()).To(Equal("1.0.0\n"))
+
+		entries, err := os.ReadDir(filepath.Join(rootDir, "targets", "channels", "0"))
+		Expect(err).To(Succeed())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("should delete files", func() {
+		Expect(fs.WriteFileBytes(ctx, "root.json", []byte("{}"))).To(Succeed())
+		Expect(fs.DeleteFile(ctx, "root.json")).To(Succeed())
+		Expect(fs.DeleteFile(ctx, "root.json")).To(Succeed())
+
+		exists, err := fs.IsFileExist(ctx, "root.json")
+		Expect(err).To(Succeed())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("should not write files outside the root directory", func() {
+		Expect(fs.WriteFileBytes(ctx, "../../escaped.json", []byte("{}"))).To(Succeed())
+		Expect(filepath.Join(rootDir, "escaped.json")).To(BeARegularFile())
+	})
+})
@@ -0,0 +1,67 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/theupdateframework/go-tuf/data"
+	"github.com/theupdateframework/go-tuf/pkg/keys"
+)
+
+// LocalSignerBackend is the in-memory stand-in for the external signer backends.
+// Keys are lost with the backend, so it is only suitable for tests.
+type LocalSignerBackend struct {
+	name string
+
+	mu      sync.Mutex
+	signers map[string]keys.Signer
+}
+
+func NewLocalSignerBackend(name string) *LocalSignerBackend {
+	return &LocalSignerBackend{name: name, signers: make(map[string]keys.Signer)}
+}
+
+func (backend *LocalSignerBackend) Name() string {
+	return backend.name
+}
+
+func (backend *LocalSignerBackend) GenerateKey(_ context.Context, role string) (string, *data.PublicKey, error) {
+	signer, err := keys.GenerateEd25519Key()
+	if err != nil {
+		return "", nil, err
+	}
+
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	keyName := fmt.Sprintf("%s-%d", role, len(backend.signers))
+	backend.signers[keyName] = signer
+
+	return keyName, signer.PublicData(), nil
+}
+
+func (backend *LocalSignerBackend) Sign(_ context.Context, keyName string, message []byte) ([]byte, error) {
+	backend.mu.Lock()
+	signer, ok := backend.signers[keyName]
+	backend.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("key %q not found", keyName)
+	}
+
+	return signer.SignMessage(message)
+}
+
+// KeyNames returns the names of the keys generated by the backend.
+func (backend *LocalSignerBackend) KeyNames() []string {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	var res []string
+	for keyName := range backend.signers {
+		res = append(res, keyName)
+	}
+
+	return res
+}
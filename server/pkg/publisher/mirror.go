@@ -0,0 +1,382 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	storageKeyPrefixMirror       = "mirror/"
+	storageKeyPrefixMirrorStatus = "mirror_status/"
+)
+
+type Mirror struct {
+	Name              string `json:"name"`
+	S3Endpoint        string `json:"s3_endpoint"`
+	S3Region          string `json:"s3_region"`
+	S3AccessKeyID     string `json:"s3_access_key_id"`
+	S3SecretAccessKey string `json:"s3_secret_access_key"`
+	S3BucketName      string `json:"s3_bucket_name"`
+}
+
+func (mirror *Mirror) Filesystem(logger hclog.Logger) Filesystem {
+	awsConfig := &aws.Config{
+		Endpoint:    aws.String(mirror.S3Endpoint),
+		Region:      aws.String(mirror.S3Region),
+		Credentials: credentials.NewStaticCredentials(mirror.S3AccessKeyID, mirror.S3SecretAccessKey, ""),
+	}
+
+	return NewS3Filesystem(awsConfig, mirror.S3BucketName, logger)
+}
+
+type MirrorStatus struct {
+	LastSyncAttemptAt time.Time `json:"last_sync_attempt_at"`
+	LastSyncedAt      time.Time `json:"last_synced_at"`
+	LastSyncError     string    `json:"last_sync_error"`
+	// OutOfSyncSince is zero when the mirror is in sync with the primary storage
+	OutOfSyncSince time.Time `json:"out_of_sync_since"`
+}
+
+func (status *MirrorStatus) InSync() bool {
+	return status.OutOfSyncSince.IsZero()
+}
+
+func (status *MirrorStatus) Lag(now time.Time) time.Duration {
+	if status.InSync() {
+		return 0
+	}
+	return now.Sub(status.OutOfSyncSince)
+}
+
+func mirrorStorageKey(name string) string {
+	return storageKeyPrefixMirror + name
+}
+
+func mirrorStatusStorageKey(name string) string {
+	return storageKeyPrefixMirrorStatus + name
+}
+
+func GetMirrors(ctx context.Context, storage logical.Storage) ([]*Mirror, error) {
+	names, err := storage.List(ctx, storageKeyPrefixMirror)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list %q in storage: %w", storageKeyPrefixMirror, err)
+	}
+
+	var mirrors []*Mirror
+	for _, name := range names {
+		mirror, err := GetMirror(ctx, storage, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if mirror != nil {
+			mirrors = append(mirrors, mirror)
+		}
+	}
+
+	return mirrors, nil
+}
+
+func GetMirror(ctx context.Context, storage logical.Storage, name string) (*Mirror, error) {
+	entry, err := storage.Get(ctx, mirrorStorageKey(name))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get mirror %q from storage: %w", name, err)
+	}
+
+	if entry == nil {
+		return nil, nil
+	}
+
+	mirror := new(Mirror)
+	if err := entry.DecodeJSON(mirror); err != nil {
+		return nil, fmt.Errorf("unable to decode mirror %q: %w", name, err)
+	}
+
+	return mirror, nil
+}
+
+// PutMirror saves the mirror configuration and marks the mirror out of sync,
+// so that the whole repository will be replicated into it on the next commit.
+func PutMirror(ctx context.Context, storage logical.Storage, mirror *Mirror, now time.Time) error {
+	entry, err := logical.StorageEntryJSON(mirrorStorageKey(mirror.Name), mirror)
+	if err != nil {
+		return fmt.Errorf("error creating storage json entry by key %q: %w", mirrorStorageKey(mirror.Name), err)
+	}
+
+	if err := storage.Put(ctx, entry); err != nil {
+		return fmt.Errorf("unable to put mirror %q into storage: %w", mirror.Name, err)
+	}
+
+	return putMirrorStatus(ctx, storage, mirror.Name, &MirrorStatus{OutOfSyncSince: now})
+}
+
+func DeleteMirror(ctx context.Context, storage logical.Storage, name string) error {
+	if err := storage.Delete(ctx, mirrorStorageKey(name)); err != nil {
+		return fmt.Errorf("unable to delete mirror %q from storage: %w", name, err)
+	}
+
+	if err := storage.Delete(ctx, mirrorStatusStorageKey(name)); err != nil {
+		return fmt.Errorf("unable to delete mirror %q status from storage: %w", name, err)
+	}
+
+	return nil
+}
+
+func GetMirrorStatus(ctx context.Context, storage logical.Storage, name string) (*MirrorStatus, error) {
+	entry, err := storage.Get(ctx, mirrorStatusStorageKey(name))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get mirror %q status from storage: %w", name, err)
+	}
+
+	if entry == nil {
+		return nil, nil
+	}
+
+	status := new(MirrorStatus)
+	if err := entry.DecodeJSON(status); err != nil {
+		return nil, fmt.Errorf("unable to decode mirror %q status: %w", name, err)
+	}
+
+	return status, nil
+}
+
+func putMirrorStatus(ctx context.Context, storage logical.Storage, name string, status *MirrorStatus) error {
+	entry, err := logical.StorageEntryJSON(mirrorStatusStorageKey(name), status)
+	if err != nil {
+		return fmt.Errorf("error creating storage json entry by key %q: %w", mirrorStatusStorageKey(name), err)
+	}
+
+	if err := storage.Put(ctx, entry); err != nil {
+		return fmt.Errorf("unable to put mirror %q status into storage: %w", name, err)
+	}
+
+	return nil
+}
+
+// MirrorsReplicator replicates files written into the primary storage to the mirrors.
+// Mirror failures are reported into the mirror status and logs, but never returned to the caller.
+type MirrorsReplicator struct {
+	Primary *RecordingFilesystem
+	Mirrors []*Mirror
+
+	storage logical.Storage
+	logger  hclog.Logger
+
+	mirrorFilesystem func(mirror *Mirror) Filesystem
+}
+
+func NewMirrorsReplicator(primary *RecordingFilesystem, mirrors []*Mirror, storage logical.Storage, logger hclog.Logger) *MirrorsReplicator {
+	return &MirrorsReplicator{
+		Primary: primary,
+		Mirrors: mirrors,
+		storage: storage,
+		logger:  logger,
+		mirrorFilesystem: func(mirror *Mirror) Filesystem {
+			return mirror.Filesystem(logger)
+		},
+	}
+}
+
+// Replicate copies the files changed since the last call into every mirror.
+// Mirrors which are out of sync get all published files returned by the publishedPathsFunc.
+func (replicator *MirrorsReplicator) Replicate(ctx context.Context, publishedPathsFunc func(ctx context.Context) ([]string, error)) {
+	changedPaths := sortPathsForPublishing(replicator.Primary.TakeWrittenPaths())
+
+	var publishedPaths []string
+	var publishedPathsErr error
+	var publishedPathsOnce sync.Once
+
+	for _, mirror := range replicator.Mirrors {
+		now := time.Now()
+
+		status, err := GetMirrorStatus(ctx, replicator.storage, mirror.Name)
+		if err != nil {
+			replicator.logger.Error(fmt.Sprintf("Unable to get mirror %q status: %s", mirror.Name, err))
+			continue
+		}
+		if status == nil {
+			status = &MirrorStatus{OutOfSyncSince: now}
+		}
+
+		paths := changedPaths
+		if !status.InSync() {
+			publishedPathsOnce.Do(func() {
+				publishedPaths, publishedPathsErr = publishedPathsFunc(ctx)
+				publishedPaths = sortPathsForPublishing(publishedPaths)
+			})
+
+			if publishedPathsErr != nil {
+				replicator.logger.Error(fmt.Sprintf("Unable to get published files list for mirror %q: %s", mirror.Name, publishedPathsErr))
+				continue
+			}
+			paths = publishedPaths
+		}
+
+		if len(paths) == 0 {
+			continue
+		}
+
+		status.LastSyncAttemptAt = now
+		if err := replicator.replicateToMirror(ctx, mirror, paths); err != nil {
+			replicator.logger.Warn(fmt.Sprintf("Unable to replicate TUF repository into mirror %q: %s", mirror.Name, err))
+
+			status.LastSyncError = err.Error()
+			if status.InSync() {
+				status.OutOfSyncSince = now
+			}
+		} else {
+			replicator.logger.Info(fmt.Sprintf("Replicated %d files into mirror %q", len(paths), mirror.Name))
+
+			status.LastSyncedAt = time.Now()
+			status.LastSyncError = ""
+			status.OutOfSyncSince = time.Time{}
+		}
+
+		if err := putMirrorStatus(ctx, replicator.storage, mirror.Name, status); err != nil {
+			replicator.logger.Error(fmt.Sprintf("Unable to save mirror %q status: %s", mirror.Name, err))
+		}
+	}
+}
+
+func (replicator *MirrorsReplicator) replicateToMirror(ctx context.Context, mirror *Mirror, paths []string) error {
+	mirrorFs := replicator.mirrorFilesystem(mirror)
+
+	for _, path := range paths {
+		if err := copyFile(ctx, replicator.Primary, mirrorFs, path); err != nil {
+			return fmt.Errorf("unable to replicate %q: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func copyFile(ctx context.Context, src, dst Filesystem, path string) error {
+	reader, writer := io.Pipe()
+
+	go func() {
+		writer.CloseWithError(src.ReadFileStream(ctx, path, writer))
+	}()
+
+	err := dst.WriteFileStream(ctx, path, reader)
+	reader.Close()
+
+	return err
+}
+
+var versionedRootMetadataRegexp = regexp.MustCompile(`^\d+\.root\.json$`)
+
+// sortPathsForPublishing returns unique paths ordered so that the TUF metadata follows the target files
+// and is in the safe order: root versions, root, delegated roles, targets, snapshot and timestamp.
+func sortPathsForPublishing(paths []string) []string {
+	orderOf := func(p string) int {
+		switch {
+		case strings.HasPrefix(p, "targets/"):
+			return 0
+		case versionedRootMetadataRegexp.MatchString(p):
+			return 1
+		case p == "root.json":
+			return 2
+		case p == "targets.json":
+			return 4
+		case p == "snapshot.json":
+			return 5
+		case p == "timestamp.json":
+			return 6
+		default:
+			return 3
+		}
+	}
+
+	var res []string
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		if !seen[p] {
+			seen[p] = true
+			res = append(res, p)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return orderOf(res[i]) < orderOf(res[j])
+	})
+
+	return res
+}
+
+// RecordingFilesystem records paths written into the underlying filesystem.
+// Deleted paths are forgotten, so temporary files are never replicated.
+type RecordingFilesystem struct {
+	Filesystem
+
+	mu           sync.Mutex
+	writtenPaths []string
+}
+
+func NewRecordingFilesystem(filesystem Filesystem) *RecordingFilesystem {
+	return &RecordingFilesystem{Filesystem: filesystem}
+}
+
+func (fs *RecordingFilesystem) WriteFileBytes(ctx context.Context, path string, data []byte) error {
+	if err := fs.Filesystem.WriteFileBytes(ctx, path, data); err != nil {
+		return err
+	}
+
+	fs.record(path)
+	return nil
+}
+
+func (fs *RecordingFilesystem) WriteFileStream(ctx context.Context, path string, reader io.Reader) error {
+	if err := fs.Filesystem.WriteFileStream(ctx, path, reader); err != nil {
+		return err
+	}
+
+	fs.record(path)
+	return nil
+}
+
+func (fs *RecordingFilesystem) DeleteFile(ctx context.Context, path string) error {
+	if err := fs.Filesystem.DeleteFile(ctx, path); err != nil {
+		return err
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	var writtenPaths []string
+	for _, p := range fs.writtenPaths {
+		if p != path {
+			writtenPaths = append(writtenPaths, p)
+		}
+	}
+	fs.writtenPaths = writtenPaths
+
+	return nil
+}
+
+func (fs *RecordingFilesystem) TakeWrittenPaths() []string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	paths := fs.writtenPaths
+	fs.writtenPaths = nil
+
+	return paths
+}
+
+func (fs *RecordingFilesystem) record(path string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.writtenPaths = append(fs.writtenPaths, path)
+}
@@ -0,0 +1,97 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/sdk/logical"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theupdateframework/go-tuf"
+)
+
+var _ = Describe("MirrorsReplicator", func() {
+	var ctx context.Context
+	var storage logical.Storage
+	var primaryFs *testMemoryFilesystem
+	var mirrorFs *testMemoryFilesystem
+	var repository *S3Repository
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		storage = &logical.InmemStorage{}
+		primaryFs = newTestMemoryFilesystem()
+		mirrorFs = newTestMemoryFilesystem()
+
+		mirror := &Mirror{Name: "eu"}
+		Expect(PutMirror(ctx, storage, mirror, time.Now())).To(Succeed())
+
+		recordingFs := NewRecordingFilesystem(primaryFs)
+		tufStore := NewNonAtomicTufStore(TufRepoPrivKeys{}, recordingFs, hclog.NewNullLogger())
+		tufRepo, err := tuf.NewRepo(tufStore)
+		Expect(err).To(Succeed())
+
+		repository = NewRepository(recordingFs, tufStore, tufRepo, hclog.NewNullLogger())
+		repository.MirrorsReplicator = NewMirrorsReplicator(recordingFs, []*Mirror{mirror}, storage, hclog.NewNullLogger())
+		repository.MirrorsReplicator.mirrorFilesystem = func(_ *Mirror) Filesystem { return mirrorFs }
+
+		Expect(repository.Init()).To(Succeed())
+		Expect(repository.GenPrivKeys()).To(Succeed())
+	})
+
+	It("should replicate the whole repository into the new mirror and then only changes", func() {
+		Expect(repository.StageTarget(ctx, "channels/0/stable", bytes.NewBufferString("1.0.0\n"))).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		Expect(mirrorFs.files).To(Equal(primaryFs.files))
+
+		status, err := GetMirrorStatus(ctx, storage, "eu")
+		Expect(err).To(Succeed())
+		Expect(status.InSync()).To(BeTrue())
+		Expect(status.LastSyncError).To(BeEmpty())
+
+		mirrorFs.writes = nil
+		Expect(repository.StageTarget(ctx, "channels/0/stable", bytes.NewBufferString("1.0.1\n"))).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		Expect(mirrorFs.writes).To(Equal([]string{"targets/channels/0/stable", "targets.json", "snapshot.json", "timestamp.json"}))
+		Expect(mirrorFs.files).To(Equal(primaryFs.files))
+	})
+
+	It("should report mirror failures without breaking the primary publish and resync later", func() {
+		mirrorFs.writeErr = errors.New("mirror is down")
+
+		Expect(repository.StageTarget(ctx, "channels/0/stable", bytes.NewBufferString("1.0.0\n"))).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		status, err := GetMirrorStatus(ctx, storage, "eu")
+		Expect(err).To(Succeed())
+		Expect(status.InSync()).To(BeFalse())
+		Expect(status.LastSyncError).To(ContainSubstring("mirror is down"))
+		Expect(status.Lag(time.Now().Add(time.Minute))).To(BeNumerically(">=", time.Minute))
+
+		mirrorFs.writeErr = nil
+		Expect(repository.StageTarget(ctx, "channels/0/beta", bytes.NewBufferString("1.0.1\n"))).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		Expect(mirrorFs.files).To(Equal(primaryFs.files))
+
+		status, err = GetMirrorStatus(ctx, storage, "eu")
+		Expect(err).To(Succeed())
+		Expect(status.InSync()).To(BeTrue())
+		Expect(status.Lag(time.Now())).To(BeZero())
+	})
+})
+
+var _ = Describe("sortPathsForPublishing", func() {
+	It("should place targets before metadata and metadata in the safe order", func() {
+		Expect(sortPathsForPublishing([]string{
+			"timestamp.json", "snapshot.json", "targets.json", "root.json", "2.root.json", "targets/a", "targets/a", "targets/b",
+		})).To(Equal([]string{
+			"targets/a", "targets/b", "2.root.json", "root.json", "targets.json", "snapshot.json", "timestamp.json",
+		}))
+	})
+})
@@ -53,26 +53,42 @@ func (store *NonAtomicTufStore) GetMeta() (map[string]json.RawMessage, error) {
 	meta := make(map[string]json.RawMessage)
 
 	for _, name := range topLevelManifests {
-		stagedData, hasKey := store.stagedMeta[name]
-		if hasKey {
-			meta[name] = stagedData
-			continue
+		data, exists, err := store.getMetaFile(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			meta[name] = data
 		}
-		store.logger.Debug(fmt.Sprintf("-- NonAtomicTufStore.GetMeta %q not found in staged meta!", name))
+	}
+
+	// Load metadata of the delegated targets roles reachable from the top-level targets role
+	queue := []string{"targets.json"}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
 
-		exists, err := store.Filesystem.IsFileExist(ctx, name)
+		delegatedRoles, err := parseDelegatedRoleNames(meta[name])
 		if err != nil {
-			return nil, fmt.Errorf("error checking existence of %q: %w", name, err)
+			return nil, fmt.Errorf("unable to parse delegations of %q: %w", name, err)
 		}
 
-		if exists {
-			data, err := store.Filesystem.ReadFileBytes(ctx, name)
+		for _, role := range delegatedRoles {
+			delegatedName := role + ".json"
+			if _, loaded := meta[delegatedName]; loaded {
+				continue
+			}
+
+			data, exists, err := store.getMetaFile(ctx, delegatedName)
 			if err != nil {
-				return nil, fmt.Errorf("error reading %q: %w", name, err)
+				return nil, err
+			}
+
+			if exists {
+				meta[delegatedName] = data
+				queue = append(queue, delegatedName)
 			}
-			meta[name] = data
-		} else {
-			store.logger.Debug(fmt.Sprintf("-- NonAtomicTufStore.GetMeta %q not found in the store filesystem!", name))
 		}
 	}
 
@@ -81,6 +97,67 @@ func (store *NonAtomicTufStore) GetMeta() (map[string]json.RawMessage, error) {
 	return meta, nil
 }
 
+func (store *NonAtomicTufStore) getMetaFile(ctx context.Context, name string) (json.RawMessage, bool, error) {
+	stagedData, hasKey := store.stagedMeta[name]
+	if hasKey {
+		return stagedData, true, nil
+	}
+	store.logger.Debug(fmt.Sprintf("-- NonAtomicTufStore.GetMeta %q not found in staged meta!", name))
+
+	exists, err := store.Filesystem.IsFileExist(ctx, name)
+	if err != nil {
+		return nil, false, fmt.Errorf("error checking existence of %q: %w", name, err)
+	}
+
+	if !exists {
+		store.logger.Debug(fmt.Sprintf("-- NonAtomicTufStore.GetMeta %q not found in the store filesystem!", name))
+		return nil, false, nil
+	}
+
+	data, err := store.Filesystem.ReadFileBytes(ctx, name)
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading %q: %w", name, err)
+	}
+
+	return data, true, nil
+}
+
+func parseDelegatedRoleNames(targetsMeta json.RawMessage) ([]string, error) {
+	if targetsMeta == nil {
+		return nil, nil
+	}
+
+	t, err := decodeTargetsMeta(targetsMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Delegations == nil {
+		return nil, nil
+	}
+
+	var res []string
+	for _, role := range t.Delegations.Roles {
+		res = append(res, role.Name)
+	}
+
+	return res, nil
+}
+
+func decodeTargetsMeta(targetsMeta json.RawMessage) (*data.Targets, error) {
+	signed := &data.Signed{}
+	if err := json.Unmarshal(targetsMeta, signed); err != nil {
+		return nil, err
+	}
+
+	t := &data.Targets{}
+	if err := json.Unmarshal(signed.Signed, t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
 func (store *NonAtomicTufStore) SetMeta(name string, meta json.RawMessage) error {
 	store.logger.Debug(fmt.Sprintf("-- NonAtomicTufStore.SetMeta %q", name))
 	store.stagedMeta[name] = meta
@@ -90,33 +167,37 @@ func (store *NonAtomicTufStore) SetMeta(name string, meta json.RawMessage) error
 func (store *NonAtomicTufStore) WalkStagedTargets(targetPathList []string, targetsFn tuf.TargetsWalkFunc) error {
 	store.logger.Debug(fmt.Sprintf("-- NonAtomicTufStore.WalkStagedTargets %v", targetPathList))
 
-	ctx := context.Background()
+	return walkStagedTargets(store.stagedFiles, targetPathList, func(targetPath string) io.Reader {
+		return store.pipedFileReader(context.Background(), path.Join("targets", targetPath))
+	}, targetsFn)
+}
 
-	runPipedFileReader := func(path string) io.Reader {
-		buf := buffer.New(64 * 1024 * 1024)
-		reader, writer := nio.Pipe(buf)
+func (store *NonAtomicTufStore) pipedFileReader(ctx context.Context, path string) io.Reader {
+	buf := buffer.New(64 * 1024 * 1024)
+	reader, writer := nio.Pipe(buf)
 
-		go func() {
-			store.logger.Debug(fmt.Sprintf("-- NonAtomicTufStore.WalkStagedTargets before ReadFileStream %q", path))
+	go func() {
+		store.logger.Debug(fmt.Sprintf("-- NonAtomicTufStore.pipedFileReader before ReadFileStream %q", path))
 
-			if err := store.Filesystem.ReadFileStream(ctx, path, writer); err != nil {
-				if err := writer.CloseWithError(fmt.Errorf("error reading file %q stream: %w", path, err)); err != nil {
-					panic(fmt.Sprintf("ERROR: failed to close pipe writer while reading file %q stream: %s\n", path, err))
-				}
-			}
-
-			store.logger.Debug(fmt.Sprintf("-- NonAtomicTufStore.WalkStagedTargets after ReadFileStream %q", path))
-			if err := writer.Close(); err != nil {
+		if err := store.Filesystem.ReadFileStream(ctx, path, writer); err != nil {
+			if err := writer.CloseWithError(fmt.Errorf("error reading file %q stream: %w", path, err)); err != nil {
 				panic(fmt.Sprintf("ERROR: failed to close pipe writer while reading file %q stream: %s\n", path, err))
 			}
-		}()
+		}
 
-		return reader
-	}
+		store.logger.Debug(fmt.Sprintf("-- NonAtomicTufStore.pipedFileReader after ReadFileStream %q", path))
+		if err := writer.Close(); err != nil {
+			panic(fmt.Sprintf("ERROR: failed to close pipe writer while reading file %q stream: %s\n", path, err))
+		}
+	}()
+
+	return reader
+}
 
+func walkStagedTargets(stagedFiles, targetPathList []string, openFn func(targetPath string) io.Reader, targetsFn tuf.TargetsWalkFunc) error {
 	if len(targetPathList) == 0 {
-		for _, filePath := range store.stagedFiles {
-			if err := targetsFn(filePath, runPipedFileReader(path.Join("targets", filePath))); err != nil {
+		for _, filePath := range stagedFiles {
+			if err := targetsFn(filePath, openFn(filePath)); err != nil {
 				return err
 			}
 		}
@@ -126,9 +207,9 @@ func (store *NonAtomicTufStore) WalkStagedTargets(targetPathList []string, targe
 
 FilterStagedPaths:
 	for _, targetPath := range targetPathList {
-		for _, stagedPath := range store.stagedFiles {
+		for _, stagedPath := range stagedFiles {
 			if stagedPath == targetPath {
-				if err := targetsFn(targetPath, runPipedFileReader(path.Join("targets", targetPath))); err != nil {
+				if err := targetsFn(targetPath, openFn(targetPath)); err != nil {
 					return err
 				}
 
@@ -159,7 +240,7 @@ func (store *NonAtomicTufStore) StageTargetFile(ctx context.Context, targetPath
 func (store *NonAtomicTufStore) Commit(consistentSnapshot bool, versions map[string]int64, _ map[string]data.Hashes) error {
 	store.logger.Debug("-- NonAtomicTufStore.Commit")
 	if consistentSnapshot {
-		panic("not supported")
+		return fmt.Errorf("the repository with consistent snapshots requires the atomic tuf store")
 	}
 
 	ctx := context.Background()
@@ -182,6 +263,17 @@ func (store *NonAtomicTufStore) Commit(consistentSnapshot bool, versions map[str
 	return nil
 }
 
+// DiscardStaged forgets staged changes.
+// Target files are already written into the store filesystem and cannot be removed safely.
+func (store *NonAtomicTufStore) DiscardStaged(_ context.Context) error {
+	store.logger.Debug("-- NonAtomicTufStore.DiscardStaged")
+
+	store.stagedFiles = nil
+	store.stagedMeta = make(map[string]json.RawMessage)
+
+	return nil
+}
+
 func (store *NonAtomicTufStore) FileIsStaged(filename string) bool {
 	_, ok := store.stagedMeta[filename]
 	return ok
@@ -243,11 +335,9 @@ func (m *NonAtomicTufStore) Clean() error {
 	panic("not supported")
 }
 
+// computeMetadataPaths returns the paths the metadata is written to.
+// The unversioned paths are always written since the store reads the metadata by them.
 func computeMetadataPaths(consistentSnapshot bool, name string, versions map[string]int64) []string {
-	if consistentSnapshot {
-		panic("not supported")
-	}
-
 	copyVersion := false
 
 	switch name {
@@ -256,7 +346,7 @@ func computeMetadataPaths(consistentSnapshot bool, name string, versions map[str
 	case "timestamp.json":
 		copyVersion = false
 	default:
-		copyVersion = false
+		copyVersion = consistentSnapshot
 	}
 
 	paths := []string{name}
@@ -0,0 +1,133 @@
+package publisher
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Period is a calendar-aware time period, e.g. 1y, 3mo, 3w, 7d or 4h30m.
+type Period struct {
+	Years    int
+	Months   int
+	Days     int
+	Duration time.Duration
+}
+
+var (
+	periodRegexp     = regexp.MustCompile(`^(\d+(y|mo|w|d|h|m|s))+$`)
+	periodPartRegexp = regexp.MustCompile(`(\d+)(y|mo|w|d|h|m|s)`)
+)
+
+func ParsePeriod(s string) (Period, error) {
+	var p Period
+
+	if !periodRegexp.MatchString(s) {
+		return p, fmt.Errorf("invalid period %q: expected a sequence of numbers with units y, mo, w, d, h, m or s (e.g. 1y, 3mo, 3w, 7d, 4h30m)", s)
+	}
+
+	for _, match := range periodPartRegexp.FindAllStringSubmatch(s, -1) {
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			return p, fmt.Errorf("invalid period %q: %w", s, err)
+		}
+
+		switch match[2] {
+		case "y":
+			p.Years += n
+		case "mo":
+			p.Months += n
+		case "w":
+			p.Days += n * 7
+		case "d":
+			p.Days += n
+		case "h":
+			p.Duration += time.Duration(n) * time.Hour
+		case "m":
+			p.Duration += time.Duration(n) * time.Minute
+		case "s":
+			p.Duration += time.Duration(n) * time.Second
+		}
+	}
+
+	return p, nil
+}
+
+func (p Period) IsZero() bool {
+	return p == Period{}
+}
+
+func (p Period) AddTo(t time.Time) time.Time {
+	return t.AddDate(p.Years, p.Months, p.Days).Add(p.Duration)
+}
+
+func (p Period) SubFrom(t time.Time) time.Time {
+	return t.AddDate(-p.Years, -p.Months, -p.Days).Add(-p.Duration)
+}
+
+// MinDuration is the shortest possible duration of the period.
+func (p Period) MinDuration() time.Duration {
+	return time.Duration(p.Years*365+p.Months*28+p.Days)*24*time.Hour + p.Duration
+}
+
+// MaxDuration is the longest possible duration of the period.
+func (p Period) MaxDuration() time.Duration {
+	return time.Duration(p.Years*366+p.Months*31+p.Days)*24*time.Hour + p.Duration
+}
+
+func (p Period) String() string {
+	if p.IsZero() {
+		return ""
+	}
+
+	var b strings.Builder
+	if p.Years > 0 {
+		fmt.Fprintf(&b, "%dy", p.Years)
+	}
+	if p.Months > 0 {
+		fmt.Fprintf(&b, "%dmo", p.Months)
+	}
+	if p.Days > 0 {
+		fmt.Fprintf(&b, "%dd", p.Days)
+	}
+
+	d := p.Duration
+	for _, unit := range []struct {
+		suffix string
+		value  time.Duration
+	}{{"h", time.Hour}, {"m", time.Minute}, {"s", time.Second}} {
+		if d >= unit.value {
+			fmt.Fprintf(&b, "%d%s", d/unit.value, unit.suffix)
+			d %= unit.value
+		}
+	}
+
+	return b.String()
+}
+
+func (p Period) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+func (p *Period) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if s == "" {
+		*p = Period{}
+		return nil
+	}
+
+	parsed, err := ParsePeriod(s)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+
+	return nil
+}
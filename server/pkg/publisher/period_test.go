@@ -0,0 +1,99 @@
+package publisher
+
+import (
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Period", func() {
+	DescribeTable("should parse and format periods",
+		func(s string, expected Period, expectedString string) {
+			p, err := ParsePeriod(s)
+			Expect(err).To(Succeed())
+			Expect(p).To(Equal(expected))
+			Expect(p.String()).To(Equal(expectedString))
+		},
+		Entry("years", "1y", Period{Years: 1}, "1y"),
+		Entry("months", "3mo", Period{Months: 3}, "3mo"),
+		Entry("weeks", "3w", Period{Days: 21}, "21d"),
+		Entry("hours and minutes", "4h30m", Period{Duration: 4*time.Hour + 30*time.Minute}, "4h30m"),
+		Entry("combined", "1y2mo3d4h", Period{Years: 1, Months: 2, Days: 3, Duration: 4 * time.Hour}, "1y2mo3d4h"),
+	)
+
+	DescribeTable("should not parse invalid periods",
+		func(s string) {
+			_, err := ParsePeriod(s)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty", ""),
+		Entry("no unit", "10"),
+		Entry("unknown unit", "10x"),
+		Entry("spaces", "1 y"),
+	)
+
+	It("should add calendar periods to time", func() {
+		t := time.Date(2022, 1, 31, 12, 0, 0, 0, time.UTC)
+		p := Period{Months: 1, Duration: time.Hour}
+		Expect(p.AddTo(t)).To(Equal(t.AddDate(0, 1, 0).Add(time.Hour)))
+		Expect(p.SubFrom(p.AddTo(t))).To(Equal(t.AddDate(0, 1, 0).AddDate(0, -1, 0)))
+	})
+})
+
+var _ = Describe("TufRepoLifetimes", func() {
+	It("should have valid defaults", func() {
+		Expect(DefaultTufRepoLifetimes().Validate(time.Hour)).To(Succeed())
+	})
+
+	It("should fill unset periods with defaults", func() {
+		lifetimes := TufRepoLifetimes{Timestamp: TufRoleLifetime{Expiration: Period{Days: 14}}}.WithDefaults()
+		Expect(lifetimes.Timestamp.Expiration).To(Equal(Period{Days: 14}))
+		Expect(lifetimes.Timestamp.RotationPeriod).To(Equal(DefaultTufRepoLifetimes().Timestamp.RotationPeriod))
+		Expect(lifetimes.Root).To(Equal(DefaultTufRepoLifetimes().Root))
+	})
+
+	It("should require rotation period shorter than expiration", func() {
+		lifetimes := DefaultTufRepoLifetimes()
+		lifetimes.Targets.RotationPeriod = Period{Days: 30}
+		lifetimes.Targets.Expiration = Period{Months: 1}
+		Expect(lifetimes.Validate(time.Hour)).To(MatchError(ContainSubstring("targets rotation period 30d must be shorter than expiration 1mo")))
+	})
+
+	It("should require expiration to exceed rotation period by two rotation check periods", func() {
+		lifetimes := DefaultTufRepoLifetimes()
+		lifetimes.Timestamp.Expiration = Period{Duration: 5 * time.Hour}
+		lifetimes.Timestamp.RotationPeriod = Period{Duration: 4 * time.Hour}
+		Expect(lifetimes.Validate(time.Hour)).To(MatchError(ContainSubstring("timestamp expiration 5h must exceed rotation period 4h by at least 2h0m0s")))
+
+		lifetimes.Timestamp.Expiration = Period{Duration: 6 * time.Hour}
+		Expect(lifetimes.Validate(time.Hour)).To(Succeed())
+	})
+
+	It("should rotate roles by the configured periods", func() {
+		now := time.Now()
+		testRepo := &testTufRepoRotatorAccessor{
+			rootExpires:      now,
+			targetsExpires:   now,
+			snapshotExpires:  now,
+			timestampExpires: now,
+		}
+
+		lifetimes := DefaultTufRepoLifetimes()
+		lifetimes.Timestamp = TufRoleLifetime{Expiration: Period{Days: 14}, RotationPeriod: Period{Days: 1}}
+		lifetimes.Snapshot = TufRoleLifetime{Expiration: Period{Days: 30}, RotationPeriod: Period{Days: 7}}
+		rotator := NewTufRepoRotatorWithLifetimes(testRepo, lifetimes)
+
+		Expect(rotator.Rotate(hclog.NewNullLogger(), now)).To(Succeed())
+		Expect(testRepo.snapshotExpires).To(Equal(now.AddDate(0, 0, 30)))
+		Expect(testRepo.timestampExpires).To(Equal(now.AddDate(0, 0, 14)))
+
+		prevTimestampExpires := testRepo.timestampExpires
+		Expect(rotator.Rotate(hclog.NewNullLogger(), now.Add(23*time.Hour))).To(Succeed())
+		Expect(testRepo.timestampExpires).To(Equal(prevTimestampExpires))
+
+		Expect(rotator.Rotate(hclog.NewNullLogger(), now.Add(24*time.Hour))).To(Succeed())
+		Expect(testRepo.timestampExpires).To(Equal(now.Add(24*time.Hour).AddDate(0, 0, 14)))
+	})
+})
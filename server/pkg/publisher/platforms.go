@@ -0,0 +1,114 @@
+package publisher
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const platformAny = "any"
+
+// DefaultTargetPlatforms are accepted when the mount does not configure the target platforms.
+var DefaultTargetPlatforms = []string{
+	"linux/amd64", "linux/arm64",
+	"darwin/amd64", "darwin/arm64",
+	"windows/amd64", "windows/arm64",
+}
+
+var platformPartRegexp = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// Platform is the release target platform in format <os>/<arch>[/<variant>], e.g. linux/arm/v7.
+// The release target directory of the platform is <os>-<arch>[-<variant>], e.g. linux-arm-v7.
+type Platform struct {
+	OS      string
+	Arch    string
+	Variant string
+}
+
+func (p Platform) String() string {
+	return strings.Join(p.parts(), "/")
+}
+
+func (p Platform) DirName() string {
+	return strings.Join(p.parts(), "-")
+}
+
+func (p Platform) parts() []string {
+	parts := []string{p.OS, p.Arch}
+	if p.Variant != "" {
+		parts = append(parts, p.Variant)
+	}
+
+	return parts
+}
+
+func ParsePlatform(platform string) (Platform, error) {
+	p, ok := newPlatform(strings.Split(platform, "/"))
+	if !ok || p.OS == platformAny || p.Arch == platformAny {
+		return Platform{}, fmt.Errorf("expected platform in format <os>/<arch>[/<variant>], got %q", platform)
+	}
+
+	return p, nil
+}
+
+// ParsePlatforms returns the default target platforms if the list is empty.
+func ParsePlatforms(platforms []string) ([]Platform, error) {
+	if len(platforms) == 0 {
+		platforms = DefaultTargetPlatforms
+	}
+
+	var result []Platform
+	for _, platform := range platforms {
+		p, err := ParsePlatform(platform)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, p)
+	}
+
+	return result, nil
+}
+
+// ValidatePlatformDir checks the release target directory <os>-<arch>[-<variant>] matches one of the platforms,
+// the os and the arch can be "any" if the release artifacts are platform-independent.
+func ValidatePlatformDir(dir string, platforms []Platform) error {
+	p, ok := newPlatform(strings.Split(dir, "-"))
+	if !ok || (p.Arch == platformAny && p.Variant != "") {
+		return fmt.Errorf("expected directory in format <os>-<arch>[-<variant>], got %q", dir)
+	}
+
+	for _, platform := range platforms {
+		if p.OS != platformAny && p.OS != platform.OS {
+			continue
+		}
+
+		// the directory without the variant fits all variants of the arch
+		if p.Arch != platformAny && (p.Arch != platform.Arch || (p.Variant != "" && p.Variant != platform.Variant)) {
+			continue
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("platform %q is not allowed", dir)
+}
+
+func newPlatform(parts []string) (Platform, bool) {
+	if len(parts) < 2 || len(parts) > 3 {
+		return Platform{}, false
+	}
+
+	for _, part := range parts {
+		if !platformPartRegexp.MatchString(part) {
+			return Platform{}, false
+		}
+	}
+
+	p := Platform{OS: parts[0], Arch: parts[1]}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+
+	return p, true
+}
@@ -0,0 +1,46 @@
+package publisher
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Platforms", func() {
+	It("should parse the platform with the variant", func() {
+		platform, err := ParsePlatform("linux/arm/v7")
+		Expect(err).To(Succeed())
+		Expect(platform).To(Equal(Platform{OS: "linux", Arch: "arm", Variant: "v7"}))
+		Expect(platform.DirName()).To(Equal("linux-arm-v7"))
+	})
+
+	It("should use the default platforms", func() {
+		platforms, err := ParsePlatforms(nil)
+		Expect(err).To(Succeed())
+		Expect(platforms).To(HaveLen(len(DefaultTargetPlatforms)))
+	})
+
+	DescribeTable("validating the platform directory",
+		func(dir string, valid bool) {
+			platforms, err := ParsePlatforms([]string{"linux/amd64", "linux/arm/v7", "freebsd/riscv64"})
+			Expect(err).To(Succeed())
+
+			err = ValidatePlatformDir(dir, platforms)
+			if valid {
+				Expect(err).To(Succeed())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("exact platform", "linux-amd64", true),
+		Entry("platform with the variant", "linux-arm-v7", true),
+		Entry("arch without the variant", "linux-arm", true),
+		Entry("any os", "any-riscv64", true),
+		Entry("any arch", "freebsd-any", true),
+		Entry("any platform", "any-any", true),
+		Entry("unknown variant", "linux-arm-v6", false),
+		Entry("unknown os", "windows-amd64", false),
+		Entry("unknown arch", "freebsd-amd64", false),
+		Entry("any arch with the variant", "linux-any-v7", false),
+		Entry("no arch", "linux", false),
+	)
+})
@@ -3,6 +3,7 @@ package publisher
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -11,12 +12,14 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 	"unicode/utf8"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/theupdateframework/go-tuf/data"
 
 	"github.com/werf/trdl/server/pkg/config"
 	"github.com/werf/trdl/server/pkg/pgp"
@@ -31,16 +34,35 @@ const (
 var (
 	ErrUninitializedRepositoryKeys = errors.New("uninitialized repository keys")
 	ErrUninitializedPGPSigningKey  = errors.New("uninitialized pgp signing key")
+	ErrUninitializedRepositoryRoot = errors.New("uninitialized repository root")
+)
+
+// ChannelsDeclarationTargetName is the target with the allowed channels declared by trdl_channels.yaml
+const ChannelsDeclarationTargetName = "trdl_channels.json"
+
+const (
+	StorageTypeS3    = "s3"
+	StorageTypeLocal = "local"
 )
 
 type RepositoryOptions struct {
+	StorageType      string
+	LocalStoragePath string
+
 	S3Endpoint        string
 	S3Region          string
 	S3AccessKeyID     string
 	S3SecretAccessKey string
 	S3BucketName      string
 
-	InitializeTUFKeys       bool
+	AtomicTufStore   bool
+	TufRepoLifetimes TufRepoLifetimes
+	TufRepoRolesKeys TufRepoRolesKeys
+	TufSigner        TufSignerOptions
+
+	InitializeTUFKeys bool
+	// InitializePGPSigningKey generates the pgp signing key if it does not exist,
+	// otherwise the repository is returned without the key
 	InitializePGPSigningKey bool
 }
 
@@ -49,8 +71,13 @@ type InMemoryFile struct {
 	Data []byte
 }
 
-func NewErrIncorrectTargetPath(path string) error {
-	return fmt.Errorf(`got incorrect target path %q: expected path in format <os>-<arch>/... where os can be either "any", "linux", "darwin" or "windows", and arch can be either "any", "amd64" or "arm64"`, path)
+func NewErrIncorrectTargetPath(path string, platforms []Platform) error {
+	var allowedPlatforms []string
+	for _, platform := range platforms {
+		allowedPlatforms = append(allowedPlatforms, platform.DirName())
+	}
+
+	return fmt.Errorf(`got incorrect target path %q: expected path in format <os>-<arch>[-<variant>]/... where the platform is one of %q, os and arch can be "any"`, path, allowedPlatforms)
 }
 
 type Publisher struct {
@@ -65,19 +92,28 @@ func NewPublisher(logger hclog.Logger) *Publisher {
 }
 
 func (publisher *Publisher) RotateRepositoryKeys(ctx context.Context, storage logical.Storage, repository RepositoryInterface, systemClock util.Clock) error {
-	updated, updatedPrivKeys, err := repository.RotatePrivKeys(ctx)
+	pendingRoot, err := publisher.getPendingRoot(ctx, storage, systemClock.Now())
 	if err != nil {
-		return fmt.Errorf("unable to rotate TUF repository keys: %w", err)
+		return err
 	}
 
-	if updated {
-		entry, err := logical.StorageEntryJSON(storageKeyTufRepositoryKeys, updatedPrivKeys)
-		if err != nil {
-			return fmt.Errorf("error creating storage json entry by key %q: %w", storageKeyTufRepositoryKeys, err)
+	// Keys changes would overwrite the pending root.json, which may be partially signed already
+	if pendingRoot != nil {
+		publisher.logger.Info("Waiting for the pending root.json signatures: skipping repository keys rotation")
+		return nil
+	}
+
+	updated, updatedPrivKeys, err := repository.RotatePrivKeys(ctx, systemClock)
+	if err != nil {
+		if err := publisher.putPendingRoot(ctx, storage, err); err != nil {
+			return fmt.Errorf("unable to rotate TUF repository keys: %w", err)
 		}
+		return nil
+	}
 
-		if err := storage.Put(ctx, entry); err != nil {
-			return fmt.Errorf("error putting private keys json entry by key %q into the storage: %w", storageKeyTufRepositoryKeys, err)
+	if updated {
+		if err := putRepositoryPrivKeys(ctx, storage, updatedPrivKeys); err != nil {
+			return err
 		}
 
 		publisher.logger.Info("Successfully rotated repository private keys")
@@ -86,8 +122,171 @@ func (publisher *Publisher) RotateRepositoryKeys(ctx context.Context, storage lo
 	return nil
 }
 
+// UpdateDelegatedRoles applies the configured delegated roles to the repository and commits the changes.
+func (publisher *Publisher) UpdateDelegatedRoles(ctx context.Context, storage logical.Storage, repository RepositoryInterface) error {
+	roles, err := GetDelegatedRoles(ctx, storage)
+	if err != nil {
+		return fmt.Errorf("error getting delegated roles: %w", err)
+	}
+
+	updated, updatedPrivKeys, err := repository.UpdateDelegatedRoles(ctx, roles)
+	if err != nil {
+		return fmt.Errorf("unable to update TUF repository delegated roles: %w", err)
+	}
+
+	if !updated {
+		return nil
+	}
+
+	// Keys of the new roles must be saved before the roles are published
+	if err := putRepositoryPrivKeys(ctx, storage, updatedPrivKeys); err != nil {
+		return err
+	}
+
+	if err := repository.CommitStaged(ctx); err != nil {
+		return fmt.Errorf("unable to commit TUF repository delegated roles: %w", err)
+	}
+
+	publisher.logger.Info("Successfully updated repository delegated roles")
+
+	return nil
+}
+
+func putRepositoryPrivKeys(ctx context.Context, storage logical.Storage, privKeys TufRepoPrivKeys) error {
+	entry, err := logical.StorageEntryJSON(storageKeyTufRepositoryKeys, privKeys)
+	if err != nil {
+		return fmt.Errorf("error creating storage json entry by key %q: %w", storageKeyTufRepositoryKeys, err)
+	}
+
+	if err := storage.Put(ctx, entry); err != nil {
+		return fmt.Errorf("error putting private keys json entry by key %q into the storage: %w", storageKeyTufRepositoryKeys, err)
+	}
+
+	return nil
+}
+
 func (publisher *Publisher) UpdateTimestamps(ctx context.Context, storage logical.Storage, repository RepositoryInterface, systemClock util.Clock) error {
-	return repository.UpdateTimestamps(ctx, systemClock)
+	err := repository.UpdateTimestamps(ctx, systemClock)
+
+	var sigErr *RootSignaturesRequiredError
+	if !errors.As(err, &sigErr) {
+		return err
+	}
+
+	pendingRoot, err := publisher.getPendingRoot(ctx, storage, systemClock.Now())
+	if err != nil {
+		return err
+	}
+
+	// The root.json change is already pending signatures
+	if pendingRoot != nil {
+		return nil
+	}
+
+	return publisher.putPendingRoot(ctx, storage, sigErr)
+}
+
+// AddRootKey adds the offline root key, the root.json change is saved as the pending root if the offline signatures are required.
+func (publisher *Publisher) AddRootKey(ctx context.Context, storage logical.Storage, repository RepositoryInterface, key *data.PublicKey) error {
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+
+	pendingRoot, err := publisher.getPendingRoot(ctx, storage, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if pendingRoot != nil {
+		return fmt.Errorf("root.json change is already pending signatures")
+	}
+
+	if err := repository.AddRootKey(ctx, key); err != nil {
+		if err := publisher.putPendingRoot(ctx, storage, err); err != nil {
+			return fmt.Errorf("unable to add TUF repository root key: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// AddRootSignature adds the offline root key signature to the pending root.json and commits it once it is signed enough.
+func (publisher *Publisher) AddRootSignature(ctx context.Context, storage logical.Storage, repository RepositoryInterface, signature data.Signature) (bool, error) {
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+
+	pendingRoot, err := publisher.getPendingRoot(ctx, storage, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	if pendingRoot == nil {
+		return false, ErrNoPendingRoot
+	}
+
+	rootData, complete, err := repository.AddRootSignature(ctx, pendingRoot.Root, signature)
+	if err != nil {
+		return false, fmt.Errorf("unable to add root.json signature: %w", err)
+	}
+
+	pendingRoot.Root = rootData
+	if !complete {
+		return false, PutPendingRoot(ctx, storage, pendingRoot)
+	}
+
+	if err := repository.CommitRoot(ctx, rootData, pendingRoot.PrivKeys); err != nil {
+		return false, fmt.Errorf("unable to commit TUF repository root: %w", err)
+	}
+
+	if pendingRoot.PrivKeys != nil {
+		if err := putRepositoryPrivKeys(ctx, storage, *pendingRoot.PrivKeys); err != nil {
+			return false, err
+		}
+	}
+
+	if err := DeletePendingRoot(ctx, storage); err != nil {
+		return false, err
+	}
+
+	publisher.logger.Info("Successfully committed repository root signed by the offline keys")
+
+	return true, nil
+}
+
+// getPendingRoot returns the pending root, the expired one is dropped to be prepared again.
+func (publisher *Publisher) getPendingRoot(ctx context.Context, storage logical.Storage, now time.Time) (*PendingRoot, error) {
+	pendingRoot, err := GetPendingRoot(ctx, storage)
+	if err != nil || pendingRoot == nil {
+		return nil, err
+	}
+
+	_, root, err := pendingRoot.GetRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	if root.Expires.After(now) {
+		return pendingRoot, nil
+	}
+
+	publisher.logger.Warn(fmt.Sprintf("Pending root.json version %d expired at %s before it was signed: dropping it", root.Version, root.Expires))
+
+	return nil, DeletePendingRoot(ctx, storage)
+}
+
+// putPendingRoot saves the root.json change requiring the offline signatures, other errors are returned as is.
+func (publisher *Publisher) putPendingRoot(ctx context.Context, storage logical.Storage, err error) error {
+	var sigErr *RootSignaturesRequiredError
+	if !errors.As(err, &sigErr) {
+		return err
+	}
+
+	if err := PutPendingRoot(ctx, storage, sigErr.PendingRoot); err != nil {
+		return err
+	}
+
+	publisher.logger.Info("Root.json change requires the offline root keys signatures: waiting for the signatures to be imported")
+
+	return nil
 }
 
 type setRepositoryKeysOptions struct {
@@ -193,21 +392,45 @@ func (publisher *Publisher) GetRepository(ctx context.Context, storage logical.S
 	publisher.mu.Lock()
 	defer publisher.mu.Unlock()
 
-	awsConfig := &aws.Config{
-		Endpoint:    aws.String(options.S3Endpoint),
-		Region:      aws.String(options.S3Region),
-		Credentials: credentials.NewStaticCredentials(options.S3AccessKeyID, options.S3SecretAccessKey, ""),
+	filesystem, err := newRepositoryFilesystem(options, publisher.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrors, err := GetMirrors(ctx, storage)
+	if err != nil {
+		return nil, fmt.Errorf("error getting repository mirrors: %w", err)
+	}
+
+	var recordingFilesystem *RecordingFilesystem
+	if len(mirrors) > 0 {
+		recordingFilesystem = NewRecordingFilesystem(filesystem)
+		filesystem = recordingFilesystem
+	}
+
+	signerBackend, err := newSignerBackend(options)
+	if err != nil {
+		return nil, err
+	}
+
+	// The backend must be registered before the saved keys are loaded
+	if signerBackend != nil {
+		RegisterSignerBackend(signerBackend)
 	}
 
 	repository, err := NewRepositoryWithOptions(
-		S3Options{AwsConfig: awsConfig, BucketName: options.S3BucketName},
-		TufRepoOptions{},
+		filesystem,
+		TufRepoOptions{AtomicStore: options.AtomicTufStore, Lifetimes: options.TufRepoLifetimes, RolesKeys: options.TufRepoRolesKeys, SignerBackend: signerBackend},
 		publisher.logger,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing publisher repository handle: %w", err)
 	}
 
+	if recordingFilesystem != nil {
+		repository.MirrorsReplicator = NewMirrorsReplicator(recordingFilesystem, mirrors, storage, publisher.logger)
+	}
+
 	if err := repository.Init(); err != nil {
 		return nil, fmt.Errorf("error initializing repository: %w", err)
 	}
@@ -219,7 +442,9 @@ func (publisher *Publisher) GetRepository(ctx context.Context, storage logical.S
 	}
 
 	pgpSigningKey, err := publisher.fetchPGPSigningKey(ctx, storage, options.InitializePGPSigningKey)
-	if err != nil {
+	if err == ErrUninitializedPGPSigningKey {
+		return repository, nil
+	} else if err != nil {
 		return nil, fmt.Errorf("error fetching pgp signing key: %w", err)
 	}
 	publisher.PGPSigningKey = pgpSigningKey
@@ -227,27 +452,35 @@ func (publisher *Publisher) GetRepository(ctx context.Context, storage logical.S
 	return repository, nil
 }
 
-func (publisher *Publisher) StageReleaseTarget(ctx context.Context, repository RepositoryInterface, releaseName, releaseFilePath string, data io.Reader) error {
-	publisher.mu.Lock()
-	defer publisher.mu.Unlock()
+func newRepositoryFilesystem(options RepositoryOptions, logger hclog.Logger) (Filesystem, error) {
+	switch options.StorageType {
+	case StorageTypeS3, "":
+		awsConfig := &aws.Config{
+			Endpoint:    aws.String(options.S3Endpoint),
+			Region:      aws.String(options.S3Region),
+			Credentials: credentials.NewStaticCredentials(options.S3AccessKeyID, options.S3SecretAccessKey, ""),
+		}
 
-	pathParts := SplitFilepath(filepath.Clean(releaseFilePath))
-	if len(pathParts) == 0 {
-		return NewErrIncorrectTargetPath(releaseFilePath)
+		return NewS3Filesystem(awsConfig, options.S3BucketName, logger), nil
+	case StorageTypeLocal:
+		return NewLocalFilesystem(options.LocalStoragePath, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown storage type %q", options.StorageType)
 	}
+}
 
-	osAndArchParts := strings.SplitN(pathParts[0], "-", 2)
+// StageReleaseTarget stages the release file placed in the directory of one of the platforms.
+func (publisher *Publisher) StageReleaseTarget(ctx context.Context, repository RepositoryInterface, releaseName, releaseFilePath string, data io.Reader, platforms []Platform) error {
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
 
-	switch osAndArchParts[0] {
-	case "any", "linux", "darwin", "windows":
-	default:
-		return NewErrIncorrectTargetPath(releaseFilePath)
+	pathParts := SplitFilepath(filepath.Clean(releaseFilePath))
+	if len(pathParts) < 2 {
+		return NewErrIncorrectTargetPath(releaseFilePath, platforms)
 	}
 
-	switch osAndArchParts[1] {
-	case "any", "amd64", "arm64":
-	default:
-		return NewErrIncorrectTargetPath(releaseFilePath)
+	if err := ValidatePlatformDir(pathParts[0], platforms); err != nil {
+		return fmt.Errorf("%s: %w", NewErrIncorrectTargetPath(releaseFilePath, platforms), err)
 	}
 
 	gpgSignErrCh := make(chan error)
@@ -295,6 +528,16 @@ func (publisher *Publisher) StageChannelsConfig(ctx context.Context, repository
 	publisher.mu.Lock()
 	defer publisher.mu.Unlock()
 
+	// publish /trdl_channels.json -> the declaration of the allowed channels
+	declarationData, err := json.Marshal(trdlChannelsConfig.Declaration())
+	if err != nil {
+		return fmt.Errorf("unable to marshal channels declaration: %w", err)
+	}
+
+	if err := repository.StageTarget(ctx, ChannelsDeclarationTargetName, bytes.NewReader(declarationData)); err != nil {
+		return fmt.Errorf("error publishing %q: %w", ChannelsDeclarationTargetName, err)
+	}
+
 	// publish /channels/GROUP/CHANNEL -> VERSION
 	for _, grp := range trdlChannelsConfig.Groups {
 		for _, chnl := range grp.Channels {
@@ -0,0 +1,167 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// ReleasesIndexTargetName is the target with the index of the published releases
+const ReleasesIndexTargetName = "trdl_releases.json"
+
+type ReleasesIndex struct {
+	Releases map[string]*ReleasesIndexEntry `json:"releases"`
+}
+
+type ReleasesIndexEntry struct {
+	// Platforms in format <os>-<arch>
+	Platforms []string `json:"platforms"`
+	// PublishedAt, GitTag and GitCommit are unknown for the releases published before the index
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+	GitTag      string     `json:"gitTag,omitempty"`
+	GitCommit   string     `json:"gitCommit,omitempty"`
+}
+
+type ReleaseInfo struct {
+	Name        string
+	GitTag      string
+	GitCommit   string
+	PublishedAt time.Time
+}
+
+func NewReleasesIndex() *ReleasesIndex {
+	return &ReleasesIndex{Releases: make(map[string]*ReleasesIndexEntry)}
+}
+
+// UpdatePlatforms sets the platforms of the releases found in the targets, the missing releases are added.
+// Returns true if the index has been changed.
+func (index *ReleasesIndex) UpdatePlatforms(targets []string) bool {
+	platformsByRelease := map[string][]string{}
+	for _, target := range targets {
+		if !strings.HasPrefix(target, "releases/") {
+			continue
+		}
+
+		pathParts := strings.SplitN(strings.TrimPrefix(target, "releases/"), "/", 3)
+		if len(pathParts) < 3 {
+			continue
+		}
+
+		releaseName, platform := strings.TrimPrefix(pathParts[0], "v"), pathParts[1]
+		platformsByRelease[releaseName] = append(platformsByRelease[releaseName], platform)
+	}
+
+	var updated bool
+	for releaseName, platforms := range platformsByRelease {
+		entry, ok := index.Releases[releaseName]
+		if !ok {
+			entry = &ReleasesIndexEntry{}
+			index.Releases[releaseName] = entry
+		}
+
+		platforms = lo.Uniq(platforms)
+		sort.Strings(platforms)
+
+		if !ok || !lo.Every(entry.Platforms, platforms) || len(entry.Platforms) != len(platforms) {
+			entry.Platforms = platforms
+			updated = true
+		}
+	}
+
+	return updated
+}
+
+func (index *ReleasesIndex) SetReleaseInfo(release ReleaseInfo) {
+	entry, ok := index.Releases[release.Name]
+	if !ok {
+		entry = &ReleasesIndexEntry{}
+		index.Releases[release.Name] = entry
+	}
+
+	publishedAt := release.PublishedAt.UTC()
+	entry.PublishedAt = &publishedAt
+	entry.GitTag = release.GitTag
+	entry.GitCommit = release.GitCommit
+}
+
+func (publisher *Publisher) GetReleasesIndex(ctx context.Context, repository RepositoryInterface) (*ReleasesIndex, error) {
+	data, exist, err := repository.ReadTarget(ctx, ReleasesIndexTargetName)
+	if err != nil {
+		return nil, err
+	}
+
+	index := NewReleasesIndex()
+	if !exist {
+		return index, nil
+	}
+
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal %q: %w", ReleasesIndexTargetName, err)
+	}
+
+	if index.Releases == nil {
+		index.Releases = make(map[string]*ReleasesIndexEntry)
+	}
+
+	return index, nil
+}
+
+// StageReleasesIndex updates the index by the staged release targets and the release info.
+func (publisher *Publisher) StageReleasesIndex(ctx context.Context, repository RepositoryInterface, release ReleaseInfo) error {
+	index, err := publisher.GetReleasesIndex(ctx, repository)
+	if err != nil {
+		return fmt.Errorf("unable to get releases index: %w", err)
+	}
+
+	targets, err := repository.GetTargets(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting existing targets: %w", err)
+	}
+
+	index.UpdatePlatforms(targets)
+	index.SetReleaseInfo(release)
+
+	return publisher.stageReleasesIndex(ctx, repository, index)
+}
+
+// SyncReleasesIndex adds the published releases missing in the index and updates their platforms,
+// the index is staged only if it has been changed.
+func (publisher *Publisher) SyncReleasesIndex(ctx context.Context, repository RepositoryInterface) error {
+	index, err := publisher.GetReleasesIndex(ctx, repository)
+	if err != nil {
+		return fmt.Errorf("unable to get releases index: %w", err)
+	}
+
+	targets, err := repository.GetTargets(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting existing targets: %w", err)
+	}
+
+	if !index.UpdatePlatforms(targets) {
+		return nil
+	}
+
+	return publisher.stageReleasesIndex(ctx, repository, index)
+}
+
+func (publisher *Publisher) stageReleasesIndex(ctx context.Context, repository RepositoryInterface, index *ReleasesIndex) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("unable to marshal releases index: %w", err)
+	}
+
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+
+	if err := repository.StageTarget(ctx, ReleasesIndexTargetName, bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("error publishing %q: %w", ReleasesIndexTargetName, err)
+	}
+
+	return nil
+}
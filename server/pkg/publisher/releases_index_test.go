@@ -0,0 +1,88 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReleasesIndex", func() {
+	var ctx context.Context
+	var repository *S3Repository
+	var publisher *Publisher
+
+	now := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	stageReleaseTargets := func(releaseName string, platforms ...string) {
+		for _, platform := range platforms {
+			Expect(repository.StageTarget(ctx, "releases/"+releaseName+"/"+platform+"/bin/app", bytes.NewBufferString(releaseName))).To(Succeed())
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		publisher = NewPublisher(hclog.NewNullLogger())
+
+		var err error
+		repository, err = NewRepositoryWithOptions(newTestMemoryFilesystem(), TufRepoOptions{}, hclog.NewNullLogger())
+		Expect(err).To(Succeed())
+		Expect(repository.Init()).To(Succeed())
+		Expect(repository.GenPrivKeys()).To(Succeed())
+	})
+
+	It("should index the release platforms and info", func() {
+		stageReleaseTargets("1.0.0", "linux-amd64", "darwin-arm64")
+		Expect(publisher.StageReleasesIndex(ctx, repository, ReleaseInfo{Name: "1.0.0", GitTag: "v1.0.0", GitCommit: "commit1", PublishedAt: now})).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		index, err := publisher.GetReleasesIndex(ctx, repository)
+		Expect(err).To(Succeed())
+		Expect(index.Releases).To(Equal(map[string]*ReleasesIndexEntry{
+			"1.0.0": {Platforms: []string{"darwin-arm64", "linux-amd64"}, PublishedAt: &now, GitTag: "v1.0.0", GitCommit: "commit1"},
+		}))
+	})
+
+	It("should add the releases published before the index", func() {
+		stageReleaseTargets("0.9.0", "any-any")
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		stageReleaseTargets("1.0.0", "linux-amd64")
+		Expect(publisher.StageReleasesIndex(ctx, repository, ReleaseInfo{Name: "1.0.0", GitTag: "v1.0.0", GitCommit: "commit1", PublishedAt: now})).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		stageReleaseTargets("1.1.0", "linux-amd64")
+		Expect(publisher.StageReleasesIndex(ctx, repository, ReleaseInfo{Name: "1.1.0", GitTag: "v1.1.0", GitCommit: "commit2", PublishedAt: now.Add(time.Hour)})).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		index, err := publisher.GetReleasesIndex(ctx, repository)
+		Expect(err).To(Succeed())
+		Expect(index.Releases).To(HaveLen(3))
+		Expect(index.Releases["0.9.0"]).To(Equal(&ReleasesIndexEntry{Platforms: []string{"any-any"}}))
+		Expect(index.Releases["1.0.0"].GitTag).To(Equal("v1.0.0"))
+		Expect(index.Releases["1.1.0"].GitCommit).To(Equal("commit2"))
+	})
+
+	It("should sync the index with the published releases", func() {
+		stageReleaseTargets("1.0.0", "linux-amd64")
+		Expect(publisher.StageReleasesIndex(ctx, repository, ReleaseInfo{Name: "1.0.0", GitTag: "v1.0.0", GitCommit: "commit1", PublishedAt: now})).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		// nothing changed: the index is not staged
+		Expect(publisher.SyncReleasesIndex(ctx, repository)).To(Succeed())
+		Expect(repository.TufStore.FileIsStaged("targets.json")).To(BeFalse())
+
+		stageReleaseTargets("0.9.0", "any-any")
+		Expect(publisher.SyncReleasesIndex(ctx, repository)).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		index, err := publisher.GetReleasesIndex(ctx, repository)
+		Expect(err).To(Succeed())
+		Expect(index.Releases).To(HaveLen(2))
+		Expect(index.Releases["0.9.0"]).To(Equal(&ReleasesIndexEntry{Platforms: []string{"any-any"}}))
+		Expect(index.Releases["1.0.0"].GitTag).To(Equal("v1.0.0"))
+	})
+})
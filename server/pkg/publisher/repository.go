@@ -3,35 +3,62 @@ package publisher
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"path"
+	"sort"
+	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
 	"github.com/hashicorp/go-hclog"
+	"github.com/samber/lo"
 	"github.com/theupdateframework/go-tuf"
+	"github.com/theupdateframework/go-tuf/data"
+	"github.com/theupdateframework/go-tuf/pkg/keys"
+	tufUtil "github.com/theupdateframework/go-tuf/util"
 
 	"github.com/werf/trdl/server/pkg/util"
 )
 
-type S3Options struct {
-	AwsConfig  *aws.Config
-	BucketName string
+type TufRepoOptions struct {
+	PrivKeys    TufRepoPrivKeys
+	AtomicStore bool
+	// Lifetimes of the TUF roles metadata, unset periods are defaulted
+	Lifetimes TufRepoLifetimes
+	// RolesKeys of the top-level roles, unset numbers are defaulted
+	RolesKeys TufRepoRolesKeys
+	// SignerBackend keeps the generated keys outside of the plugin, keys are generated locally when unset
+	SignerBackend SignerBackend
 }
 
-type TufRepoOptions struct {
-	PrivKeys TufRepoPrivKeys
+// TufStagingStore is a tuf.LocalStore which stages target files before commit.
+type TufStagingStore interface {
+	tuf.LocalStore
+	StageTargetFile(ctx context.Context, targetPath string, data io.Reader) error
+	DiscardStaged(ctx context.Context) error
 }
 
-func NewRepositoryWithOptions(s3Options S3Options, tufRepoOptions TufRepoOptions, logger hclog.Logger) (*S3Repository, error) {
-	s3fs := NewS3Filesystem(s3Options.AwsConfig, s3Options.BucketName, logger)
-	tufStore := NewNonAtomicTufStore(tufRepoOptions.PrivKeys, s3fs, logger)
+func NewRepositoryWithOptions(filesystem Filesystem, tufRepoOptions TufRepoOptions, logger hclog.Logger) (*S3Repository, error) {
+	var tufStore *NonAtomicTufStore
+	var stagingStore TufStagingStore
+	if tufRepoOptions.AtomicStore {
+		atomicTufStore := NewAtomicTufStore(tufRepoOptions.PrivKeys, filesystem, logger)
+		tufStore, stagingStore = atomicTufStore.NonAtomicTufStore, atomicTufStore
+	} else {
+		tufStore = NewNonAtomicTufStore(tufRepoOptions.PrivKeys, filesystem, logger)
+		stagingStore = tufStore
+	}
 
-	tufRepo, err := tuf.NewRepo(tufStore)
+	tufRepo, err := tuf.NewRepo(stagingStore)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing tuf repo: %w", err)
 	}
 
-	repository := NewRepository(s3fs, tufStore, tufRepo, logger)
+	repository := NewRepository(filesystem, tufStore, tufRepo, logger)
+	repository.StagingStore = stagingStore
+	repository.Lifetimes = tufRepoOptions.Lifetimes.WithDefaults()
+	repository.RolesKeys = tufRepoOptions.RolesKeys.WithDefaults()
+	repository.SignerBackend = tufRepoOptions.SignerBackend
 
 	if err := tufStore.PrivKeys.SetupStoreSigners(tufStore); err != nil {
 		return nil, fmt.Errorf("unable to set private keys into tuf store: %w", err)
@@ -41,18 +68,30 @@ func NewRepositoryWithOptions(s3Options S3Options, tufRepoOptions TufRepoOptions
 }
 
 type S3Repository struct {
-	S3Filesystem *S3Filesystem
+	Filesystem   Filesystem
 	TufStore     *NonAtomicTufStore
+	StagingStore TufStagingStore
 	TufRepo      *tuf.Repo
+	Lifetimes    TufRepoLifetimes
+	RolesKeys    TufRepoRolesKeys
+
+	// SignerBackend is set when the new keys are generated by the external signer backend
+	SignerBackend SignerBackend
+
+	// MirrorsReplicator is set when the repository has mirrors configured
+	MirrorsReplicator *MirrorsReplicator
 
 	logger hclog.Logger
 }
 
-func NewRepository(s3Filesystem *S3Filesystem, tufStore *NonAtomicTufStore, tufRepo *tuf.Repo, logger hclog.Logger) *S3Repository {
+func NewRepository(filesystem Filesystem, tufStore *NonAtomicTufStore, tufRepo *tuf.Repo, logger hclog.Logger) *S3Repository {
 	return &S3Repository{
-		S3Filesystem: s3Filesystem,
+		Filesystem:   filesystem,
 		TufStore:     tufStore,
+		StagingStore: tufStore,
 		TufRepo:      tufRepo,
+		Lifetimes:    DefaultTufRepoLifetimes(),
+		RolesKeys:    DefaultTufRepoRolesKeys(),
 		logger:       logger,
 	}
 }
@@ -82,33 +121,232 @@ func (repository *S3Repository) GetPrivKeys() TufRepoPrivKeys {
 }
 
 func (repository *S3Repository) GenPrivKeys() error {
-	if _, err := repository.TufRepo.GenKey("root"); err != nil {
-		return fmt.Errorf("error generating tuf repository root key: %w", err)
+	now := time.Now()
+	rootExpires := repository.Lifetimes.Root.Expiration.AddTo(now)
+
+	for _, role := range topLevelRoles {
+		roleKeys := repository.RolesKeys.Get(role)
+
+		for i := 0; i < roleKeys.Keys; i++ {
+			if err := repository.addRolePrivKey(role, rootExpires); err != nil {
+				return fmt.Errorf("error generating tuf repository %s key: %w", role, err)
+			}
+		}
+
+		// There are no offline root keys yet, the root threshold is raised when the offline keys are added
+		threshold := roleKeys.Threshold
+		if threshold > roleKeys.Keys {
+			threshold = roleKeys.Keys
+		}
+
+		if err := repository.TufRepo.SetThreshold(role, threshold); err != nil {
+			return fmt.Errorf("error setting tuf repository %s threshold: %w", role, err)
+		}
+	}
+
+	for _, role := range topLevelRoles {
+		repository.TufStore.PrivKeys.SetKeyInfo(role, now, DefaultPrivKeyLifetime(role))
+	}
+
+	return nil
+}
+
+// RotatePrivKeys rotates expired keys and applies the configured numbers of keys and thresholds of the top-level roles.
+// RootSignaturesRequiredError is returned when the changed root.json cannot be signed by the online root keys:
+// the changes are discarded and must be committed by CommitRoot when the offline signatures are collected.
+func (repository *S3Repository) RotatePrivKeys(ctx context.Context, systemClock util.Clock) (bool, TufRepoPrivKeys, error) {
+	now := systemClock.Now()
+	privKeys := &repository.TufStore.PrivKeys
+
+	origPrivKeys, err := privKeys.clone()
+	if err != nil {
+		return false, TufRepoPrivKeys{}, fmt.Errorf("unable to copy private keys: %w", err)
+	}
+
+	var updated bool
+	var changedRoles []string
+
+	for _, role := range topLevelRoles {
+		info := privKeys.GetKeyInfo(role)
+
+		// Keys generated before the keys info was introduced: start tracking from now on
+		if info == nil {
+			privKeys.SetKeyInfo(role, now, DefaultPrivKeyLifetime(role))
+			updated = true
+		} else if info.ExpiresAt().Sub(now) <= 0 {
+			if err := repository.rotateRolePrivKeys(role, now); err != nil {
+				return false, TufRepoPrivKeys{}, fmt.Errorf("unable to rotate %s keys: %w", role, err)
+			}
+
+			repository.logger.Info(fmt.Sprintf("Rotated TUF repository %s keys expired at %s", role, info.ExpiresAt()))
+			changedRoles = append(changedRoles, role)
+		}
+
+		changed, err := repository.updateRoleKeys(role, now)
+		if err != nil {
+			return false, TufRepoPrivKeys{}, fmt.Errorf("unable to update %s keys: %w", role, err)
+		}
+
+		if changed {
+			changedRoles = append(changedRoles, role)
+		}
+	}
+
+	if len(changedRoles) > 0 {
+		if err := repository.checkStagedRootSignatures(ctx); err != nil {
+			var sigErr *RootSignaturesRequiredError
+			if errors.As(err, &sigErr) {
+				rotatedPrivKeys := *privKeys
+				sigErr.PendingRoot.PrivKeys = &rotatedPrivKeys
+
+				repository.TufStore.PrivKeys = origPrivKeys
+				if err := repository.DiscardStaged(ctx); err != nil {
+					return false, TufRepoPrivKeys{}, err
+				}
+			}
+
+			return false, TufRepoPrivKeys{}, err
+		}
+
+		// Re-sign all roles with the new keys,
+		// root.json is signed by both the old and the new root keys at this point
+		if err := repository.rotator().ForceRotate(repository.logger, now); err != nil {
+			return false, TufRepoPrivKeys{}, fmt.Errorf("unable to re-sign TUF repository roles after %v keys changes: %w", lo.Uniq(changedRoles), err)
+		}
+
+		updated = true
+	}
+
+	if !updated {
+		return false, TufRepoPrivKeys{}, nil
+	}
+
+	return true, *privKeys, nil
+}
+
+// rotateRolePrivKeys replaces every online key of the role by a new one, offline keys are not affected.
+func (repository *S3Repository) rotateRolePrivKeys(role string, now time.Time) error {
+	oldSigners, err := repository.TufStore.PrivKeys.GetSigners(role)
+	if err != nil {
+		return fmt.Errorf("unable to get current key signers: %w", err)
+	}
+
+	rootExpires := repository.Lifetimes.Root.Expiration.AddTo(now)
+
+	for _, oldSigner := range oldSigners {
+		if err := repository.addRolePrivKey(role, rootExpires); err != nil {
+			return err
+		}
+
+		if err := repository.revokeRolePrivKey(role, oldSigner.PublicData().IDs()[0], rootExpires); err != nil {
+			return err
+		}
+	}
+
+	repository.TufStore.PrivKeys.SetKeyInfo(role, now, DefaultPrivKeyLifetime(role))
+
+	return nil
+}
+
+// updateRoleKeys brings the number of online keys and the threshold of the role to the configured ones.
+func (repository *S3Repository) updateRoleKeys(role string, now time.Time) (bool, error) {
+	roleKeys := repository.RolesKeys.Get(role)
+	rootExpires := repository.Lifetimes.Root.Expiration.AddTo(now)
+
+	signers, err := repository.TufStore.PrivKeys.GetSigners(role)
+	if err != nil {
+		return false, fmt.Errorf("unable to get current key signers: %w", err)
+	}
+
+	var changed bool
+	for i := len(signers); i < roleKeys.Keys; i++ {
+		if err := repository.addRolePrivKey(role, rootExpires); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+
+	for i := len(signers); i > roleKeys.Keys; i-- {
+		if err := repository.revokeRolePrivKey(role, signers[i-1].PublicData().IDs()[0], rootExpires); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+
+	threshold, err := repository.TufRepo.GetThreshold(role)
+	if err != nil {
+		return false, fmt.Errorf("unable to get current threshold: %w", err)
+	}
+
+	if threshold == roleKeys.Threshold {
+		return changed, nil
+	}
+
+	keyIDs, err := repository.getRoleKeyIDs(role)
+	if err != nil {
+		return false, err
+	}
+
+	// The root threshold is applied once enough offline root keys are added
+	if len(keyIDs) < roleKeys.Threshold {
+		repository.logger.Warn(fmt.Sprintf("Unable to set TUF repository %s threshold %d: the role has only %d keys", role, roleKeys.Threshold, len(keyIDs)))
+		return changed, nil
 	}
 
-	if _, err := repository.TufRepo.GenKey("targets"); err != nil {
-		return fmt.Errorf("error generating tuf repository targets key: %w", err)
+	if err := repository.TufRepo.SetThreshold(role, roleKeys.Threshold); err != nil {
+		return false, fmt.Errorf("unable to set threshold: %w", err)
 	}
 
-	if _, err := repository.TufRepo.GenKey("snapshot"); err != nil {
-		return fmt.Errorf("error generating tuf repository snapshot key: %w", err)
+	repository.logger.Info(fmt.Sprintf("Changed TUF repository %s threshold from %d to %d", role, threshold, roleKeys.Threshold))
+
+	return true, nil
+}
+
+func (repository *S3Repository) addRolePrivKey(role string, rootExpires time.Time) error {
+	signer, err := repository.generateSigner(role)
+	if err != nil {
+		return fmt.Errorf("unable to generate new key: %w", err)
 	}
 
-	if _, err := repository.TufRepo.GenKey("timestamp"); err != nil {
-		return fmt.Errorf("error generating tuf repository timestamp key: %w", err)
+	if err := repository.TufRepo.AddPrivateKeyWithExpires(role, signer, rootExpires); err != nil {
+		return fmt.Errorf("unable to add new key: %w", err)
 	}
 
 	return nil
 }
 
-func (repository *S3Repository) RotatePrivKeys(ctx context.Context) (bool, TufRepoPrivKeys, error) {
-	// TODO: Check priv keys expiration and generate new keys when necessary.
+// generateSigner generates the key by the signer backend if configured, otherwise the private key is generated locally.
+func (repository *S3Repository) generateSigner(role string) (keys.Signer, error) {
+	if repository.SignerBackend != nil {
+		return GenerateExternalKey(context.Background(), repository.SignerBackend, role)
+	}
 
-	return false, TufRepoPrivKeys{}, nil
+	return keys.GenerateEd25519Key()
+}
+
+// revokeRolePrivKey revokes the key from the role and deletes the private key from the persisted keys.
+// The signer is still registered in the in-memory TUF store until the repository is reloaded:
+// go-tuf signs root.json by all locally known root signers, so the new root.json committed in the same run
+// is signed by the revoked root key as well and clients are able to verify it by the previously trusted root.
+func (repository *S3Repository) revokeRolePrivKey(role, keyID string, rootExpires time.Time) error {
+	if err := repository.TufRepo.RevokeKeyWithExpires(role, keyID, rootExpires); err != nil {
+		return fmt.Errorf("unable to revoke key %q: %w", keyID, err)
+	}
+
+	if err := repository.TufStore.PrivKeys.DeleteKey(role, keyID); err != nil {
+		return fmt.Errorf("unable to delete key %q: %w", keyID, err)
+	}
+
+	return nil
 }
 
+// Init initializes the new TUF repository.
+// The repository with the atomic store is initialized with consistent snapshots:
+// the targets are published under the hash-prefixed names and the metadata under the versioned names,
+// so that the published files the current metadata points to are never overwritten.
 func (repository *S3Repository) Init() error {
-	err := repository.TufRepo.Init(false)
+	_, isAtomicStore := repository.StagingStore.(*AtomicTufStore)
+	err := repository.TufRepo.Init(isAtomicStore)
 
 	if err == tuf.ErrInitNotAllowed {
 		repository.logger.Info("Tuf repository already initialized: skip initialization")
@@ -120,26 +358,171 @@ func (repository *S3Repository) Init() error {
 }
 
 func (repository *S3Repository) StageTarget(ctx context.Context, pathInsideTargets string, data io.Reader) error {
-	if err := repository.TufStore.StageTargetFile(ctx, pathInsideTargets, data); err != nil {
+	if err := repository.StagingStore.StageTargetFile(ctx, pathInsideTargets, data); err != nil {
 		return fmt.Errorf("unable to add staged file %q: %w", pathInsideTargets, err)
 	}
 
-	if err := repository.TufRepo.AddTarget(pathInsideTargets, json.RawMessage("")); err != nil {
+	targetsExpires := repository.Lifetimes.Targets.Expiration.AddTo(time.Now())
+	if err := repository.TufRepo.AddTargetWithExpires(pathInsideTargets, json.RawMessage(""), targetsExpires); err != nil {
 		return fmt.Errorf("unable to register target file %q in the tuf repo: %w", pathInsideTargets, err)
 	}
 
 	return nil
 }
 
-func (repository *S3Repository) UpdateTimestamps(_ context.Context, systemClock util.Clock) error {
-	return NewTufRepoRotator(repository.TufRepo).Rotate(repository.logger, systemClock.Now())
+func (repository *S3Repository) UpdateTimestamps(ctx context.Context, systemClock util.Clock) error {
+	now := systemClock.Now()
+
+	rotatedDelegatedRoles, err := repository.rotateDelegatedRoles(now)
+	if err != nil {
+		return fmt.Errorf("unable to rotate delegated roles: %w", err)
+	}
+
+	// Top-level roles rotation does not cover the delegated roles, so commit them separately
+	if rotatedDelegatedRoles {
+		if err := repository.CommitStaged(ctx); err != nil {
+			return fmt.Errorf("unable to commit delegated roles rotation: %w", err)
+		}
+	}
+
+	canSignRoot, err := repository.canSignRootOnline()
+	if err != nil {
+		return err
+	}
+
+	// root.json requiring the offline signatures is rotated separately by the pending root
+	rotator := repository.rotator()
+	rotator.SkipRoot = !canSignRoot
+
+	if err := rotator.Rotate(repository.logger, now); err != nil {
+		return err
+	}
+
+	repository.replicateToMirrors(ctx)
+
+	if !canSignRoot {
+		return repository.stageRootRotation(ctx, rotator, now)
+	}
+
+	return nil
+}
+
+func (repository *S3Repository) CommitStaged(ctx context.Context) error {
+	if err := repository.commitStaged(); err != nil {
+		if discardErr := repository.StagingStore.DiscardStaged(ctx); discardErr != nil {
+			repository.logger.Error(fmt.Sprintf("Unable to discard staged changes: %s", discardErr))
+		}
+
+		return err
+	}
+
+	repository.replicateToMirrors(ctx)
+
+	return nil
 }
 
-func (repository *S3Repository) CommitStaged(_ context.Context) error {
-	if err := repository.TufRepo.Snapshot(); err != nil {
+func (repository *S3Repository) replicateToMirrors(ctx context.Context) {
+	if repository.MirrorsReplicator == nil {
+		return
+	}
+
+	repository.MirrorsReplicator.Replicate(ctx, repository.getPublishedPaths)
+}
+
+// getPublishedPaths returns paths of all target files and metadata of the repository.
+// With consistent snapshots the hash-prefixed targets and the current versioned metadata are returned as well.
+func (repository *S3Repository) getPublishedPaths(ctx context.Context) ([]string, error) {
+	targets, err := repository.GetTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, rootVersion, err := repository.GetRootMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	consistentSnapshot, err := repository.isConsistentSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, target := range targets {
+		fileMeta, _, err := repository.getTargetFileMeta(target)
+		if err != nil {
+			return nil, err
+		}
+
+		targetPaths, err := getPublishedTargetPaths(consistentSnapshot, target, fileMeta)
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, targetPaths...)
+	}
+	for version := int64(1); version <= rootVersion; version++ {
+		paths = append(paths, fmt.Sprintf("%d.root.json", version))
+	}
+	paths = append(paths, topLevelManifests...)
+
+	meta, err := repository.StagingStore.GetMeta()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get TUF repository metadata: %w", err)
+	}
+
+	delegatedMeta, err := repository.getDelegatedTargetsMeta(meta)
+	if err != nil {
+		return nil, err
+	}
+	for _, desc := range delegatedMeta {
+		paths = append(paths, desc.Role.Name+".json")
+	}
+
+	if consistentSnapshot {
+		for name, data := range meta {
+			if name == "root.json" || name == "timestamp.json" {
+				continue
+			}
+
+			version, err := decodeMetaVersion(data)
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode %s: %w", name, err)
+			}
+
+			paths = append(paths, tufUtil.VersionedPath(name, version))
+		}
+	}
+
+	return paths, nil
+}
+
+func decodeMetaVersion(meta json.RawMessage) (int64, error) {
+	signed := &data.Signed{}
+	if err := json.Unmarshal(meta, signed); err != nil {
+		return 0, err
+	}
+
+	var common struct {
+		Version int64 `json:"version"`
+	}
+	if err := json.Unmarshal(signed.Signed, &common); err != nil {
+		return 0, err
+	}
+
+	return common.Version, nil
+}
+
+func (repository *S3Repository) rotator() *TufRepoRotator {
+	return NewTufRepoRotatorWithLifetimes(repository.TufRepo, repository.Lifetimes)
+}
+
+func (repository *S3Repository) commitStaged() error {
+	now := time.Now()
+	if err := repository.TufRepo.SnapshotWithExpires(repository.Lifetimes.Snapshot.Expiration.AddTo(now)); err != nil {
 		return fmt.Errorf("tuf repo snapshot failed: %w", err)
 	}
-	if err := repository.TufRepo.Timestamp(); err != nil {
+	if err := repository.TufRepo.TimestampWithExpires(repository.Lifetimes.Timestamp.Expiration.AddTo(now)); err != nil {
 		return fmt.Errorf("tuf repo timestamp failed: %w", err)
 	}
 	if err := repository.TufRepo.Commit(); err != nil {
@@ -158,5 +541,193 @@ func (repository *S3Repository) GetTargets(ctx context.Context) ([]string, error
 	for path := range targetsMeta {
 		res = append(res, path)
 	}
+
+	meta, err := repository.StagingStore.GetMeta()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get TUF repository metadata: %w", err)
+	}
+
+	delegatedMeta, err := repository.getDelegatedTargetsMeta(meta)
+	if err != nil {
+		return nil, err
+	}
+	for _, desc := range delegatedMeta {
+		for path := range desc.Targets.Targets {
+			if _, ok := targetsMeta[path]; !ok {
+				res = append(res, path)
+			}
+		}
+	}
+
 	return res, nil
 }
+
+// ReadTarget returns the data of the published target, false is returned if there is no such target.
+func (repository *S3Repository) ReadTarget(ctx context.Context, pathInsideTargets string) ([]byte, bool, error) {
+	fileMeta, exist, err := repository.getTargetFileMeta(pathInsideTargets)
+	if err != nil || !exist {
+		return nil, false, err
+	}
+
+	consistentSnapshot, err := repository.isConsistentSnapshot(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	publishedPaths, err := getPublishedTargetPaths(consistentSnapshot, pathInsideTargets, fileMeta)
+	if err != nil {
+		return nil, false, err
+	}
+
+	data, err := repository.Filesystem.ReadFileBytes(ctx, publishedPaths[0])
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to read target %q: %w", pathInsideTargets, err)
+	}
+
+	return data, true, nil
+}
+
+// getTargetFileMeta returns the meta of the target of the top-level or delegated targets role.
+func (repository *S3Repository) getTargetFileMeta(pathInsideTargets string) (data.TargetFileMeta, bool, error) {
+	targetsMeta, err := repository.TufRepo.Targets()
+	if err != nil {
+		return data.TargetFileMeta{}, false, fmt.Errorf("unable to get TUF-repo targets metadata: %w", err)
+	}
+
+	if fileMeta, ok := targetsMeta[pathInsideTargets]; ok {
+		return fileMeta, true, nil
+	}
+
+	meta, err := repository.StagingStore.GetMeta()
+	if err != nil {
+		return data.TargetFileMeta{}, false, fmt.Errorf("unable to get TUF repository metadata: %w", err)
+	}
+
+	delegatedMeta, err := repository.getDelegatedTargetsMeta(meta)
+	if err != nil {
+		return data.TargetFileMeta{}, false, err
+	}
+	for _, desc := range delegatedMeta {
+		if fileMeta, ok := desc.Targets.Targets[pathInsideTargets]; ok {
+			return fileMeta, true, nil
+		}
+	}
+
+	return data.TargetFileMeta{}, false, nil
+}
+
+// isConsistentSnapshot returns true if the published targets and metadata have the hash-prefixed and versioned names.
+func (repository *S3Repository) isConsistentSnapshot(ctx context.Context) (bool, error) {
+	root, err := repository.getTrustedRoot(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return root != nil && root.ConsistentSnapshot, nil
+}
+
+// getPublishedTargetPaths returns the hash-prefixed paths of the target if the repository uses consistent snapshots.
+func getPublishedTargetPaths(consistentSnapshot bool, pathInsideTargets string, fileMeta data.TargetFileMeta) ([]string, error) {
+	targetPath := path.Join("targets", pathInsideTargets)
+	if !consistentSnapshot {
+		return []string{targetPath}, nil
+	}
+
+	paths := tufUtil.HashedPaths(targetPath, fileMeta.Hashes)
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no hashes of target %q found", pathInsideTargets)
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+func (repository *S3Repository) GetRolesStatus(_ context.Context) ([]*TufRoleStatus, error) {
+	rotator := repository.rotator()
+
+	var res []*TufRoleStatus
+	for _, role := range []struct {
+		name       string
+		lifetime   TufRoleLifetime
+		versionFn  func() (int64, error)
+		expiresFn  func() (time.Time, error)
+		rotateAtFn func() (time.Time, error)
+	}{
+		{"root", repository.Lifetimes.Root, repository.TufRepo.RootVersion, repository.TufRepo.RootExpires, rotator.GetRootRotateAt},
+		{"targets", repository.Lifetimes.Targets, repository.TufRepo.TargetsVersion, repository.TufRepo.TargetsExpires, rotator.GetTargetsRotateAt},
+		{"snapshot", repository.Lifetimes.Snapshot, repository.TufRepo.SnapshotVersion, repository.TufRepo.SnapshotExpires, rotator.GetSnapshotRotateAt},
+		{"timestamp", repository.Lifetimes.Timestamp, repository.TufRepo.TimestampVersion, repository.TufRepo.TimestampExpires, rotator.GetTimestampRotateAt},
+	} {
+		version, err := role.versionFn()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get %s version: %w", role.name, err)
+		}
+
+		expires, err := role.expiresFn()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get %s expires: %w", role.name, err)
+		}
+
+		rotateAt, err := role.rotateAtFn()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get %s rotation time: %w", role.name, err)
+		}
+
+		res = append(res, &TufRoleStatus{
+			Role:     role.name,
+			Version:  version,
+			Expires:  expires,
+			RotateAt: rotateAt,
+			Lifetime: role.lifetime,
+		})
+	}
+
+	meta, err := repository.StagingStore.GetMeta()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get TUF repository metadata: %w", err)
+	}
+
+	delegatedMeta, err := repository.getDelegatedTargetsMeta(meta)
+	if err != nil {
+		return nil, err
+	}
+	for _, desc := range delegatedMeta {
+		res = append(res, &TufRoleStatus{
+			Role:     desc.Role.Name,
+			Version:  desc.Targets.Version,
+			Expires:  desc.Targets.Expires,
+			RotateAt: repository.getDelegatedRoleRotateAt(desc.Targets.Expires),
+			Lifetime: repository.Lifetimes.Targets,
+		})
+	}
+
+	return res, nil
+}
+
+func (repository *S3Repository) GetRootMeta(ctx context.Context) ([]byte, int64, error) {
+	exists, err := repository.TufStore.Filesystem.IsFileExist(ctx, "root.json")
+	if err != nil {
+		return nil, 0, fmt.Errorf("error checking existence of root.json: %w", err)
+	}
+
+	if !exists {
+		return nil, 0, ErrUninitializedRepositoryRoot
+	}
+
+	rootData, err := repository.TufStore.Filesystem.ReadFileBytes(ctx, "root.json")
+	if err != nil {
+		return nil, 0, fmt.Errorf("error reading root.json: %w", err)
+	}
+
+	signed := &data.Signed{}
+	if err := json.Unmarshal(rootData, signed); err != nil {
+		return nil, 0, fmt.Errorf("unable to unmarshal root.json: %w", err)
+	}
+
+	root := &data.Root{}
+	if err := json.Unmarshal(signed.Signed, root); err != nil {
+		return nil, 0, fmt.Errorf("unable to unmarshal root.json signed data: %w", err)
+	}
+
+	return rootData, root.Version, nil
+}
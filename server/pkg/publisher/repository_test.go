@@ -0,0 +1,232 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theupdateframework/go-tuf"
+	"github.com/theupdateframework/go-tuf/data"
+
+	"github.com/werf/trdl/server/pkg/util"
+)
+
+var _ = Describe("S3Repository", func() {
+	var ctx context.Context
+	var fs *testMemoryFilesystem
+	var repository *S3Repository
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fs = newTestMemoryFilesystem()
+
+		tufStore := NewNonAtomicTufStore(TufRepoPrivKeys{}, fs, hclog.NewNullLogger())
+		tufRepo, err := tuf.NewRepo(tufStore)
+		Expect(err).To(Succeed())
+
+		repository = NewRepository(nil, tufStore, tufRepo, hclog.NewNullLogger())
+		Expect(repository.Init()).To(Succeed())
+		Expect(repository.GenPrivKeys()).To(Succeed())
+		Expect(repository.StageTarget(ctx, "channels/0/stable", bytes.NewBufferString("1.0.0\n"))).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+	})
+
+	It("should track keys creation time and lifetime", func() {
+		for _, role := range topLevelRoles {
+			info := repository.GetPrivKeys().GetKeyInfo(role)
+			Expect(info).NotTo(BeNil())
+			Expect(info.Lifetime).To(Equal(DefaultPrivKeyLifetime(role)))
+		}
+	})
+
+	It("should return current root metadata", func() {
+		rootData, rootVersion, err := repository.GetRootMeta(ctx)
+		Expect(err).To(Succeed())
+		Expect(rootVersion).To(Equal(int64(1)))
+
+		expectedRootData, err := fs.ReadFileBytes(ctx, "root.json")
+		Expect(err).To(Succeed())
+		Expect(rootData).To(Equal(expectedRootData))
+	})
+
+	It("should report roles status", func() {
+		rolesStatus, err := repository.GetRolesStatus(ctx)
+		Expect(err).To(Succeed())
+		Expect(rolesStatus).To(HaveLen(4))
+
+		now := time.Now()
+		for _, status := range rolesStatus {
+			Expect(status.Version).To(BeNumerically(">=", 1))
+			Expect(status.Expires).To(BeTemporally(">", now))
+			Expect(status.RotateAt).To(BeTemporally("<", status.Expires))
+			Expect(status.IsNearExpiry(now)).To(BeFalse())
+			Expect(status.IsNearExpiry(status.Expires.Add(-time.Minute))).To(BeTrue())
+		}
+	})
+
+	It("should not rotate unexpired keys", func() {
+		privKeysBefore := repository.GetPrivKeys()
+
+		updated, _, err := repository.RotatePrivKeys(ctx, util.NewFixedClock(time.Now().Add(time.Hour)))
+		Expect(err).To(Succeed())
+		Expect(updated).To(BeFalse())
+		Expect(repository.GetPrivKeys().Root).To(Equal(privKeysBefore.Root))
+	})
+
+	It("should start tracking untracked keys", func() {
+		repository.TufStore.PrivKeys.KeysInfo = nil
+
+		now := time.Now()
+		updated, privKeys, err := repository.RotatePrivKeys(ctx, util.NewFixedClock(now))
+		Expect(err).To(Succeed())
+		Expect(updated).To(BeTrue())
+
+		for _, role := range topLevelRoles {
+			Expect(privKeys.GetKeyInfo(role).CreatedAt).To(Equal(now.UTC().Round(time.Second)))
+		}
+	})
+
+	It("should rotate expired keys and sign new root by both old and new root keys", func() {
+		oldPrivKeys := repository.GetPrivKeys()
+		oldRootSigner, err := oldPrivKeys.GetSigner("root")
+		Expect(err).To(Succeed())
+
+		now := time.Now().AddDate(3, 0, 0)
+		updated, newPrivKeys, err := repository.RotatePrivKeys(ctx, util.NewFixedClock(now))
+		Expect(err).To(Succeed())
+		Expect(updated).To(BeTrue())
+
+		Expect(newPrivKeys.Root).NotTo(Equal(oldPrivKeys.Root))
+		Expect(newPrivKeys.Targets).NotTo(Equal(oldPrivKeys.Targets))
+		Expect(newPrivKeys.Snapshot).NotTo(Equal(oldPrivKeys.Snapshot))
+		Expect(newPrivKeys.Timestamp).NotTo(Equal(oldPrivKeys.Timestamp))
+
+		for _, role := range topLevelRoles {
+			Expect(newPrivKeys.GetKeyInfo(role).CreatedAt).To(Equal(now.UTC().Round(time.Second)))
+		}
+
+		newRootSigner, err := newPrivKeys.GetSigner("root")
+		Expect(err).To(Succeed())
+
+		rootData, err := fs.ReadFileBytes(ctx, "2.root.json")
+		Expect(err).To(Succeed())
+
+		signed := &data.Signed{}
+		Expect(json.Unmarshal(rootData, signed)).To(Succeed())
+
+		var signatureKeyIDs []string
+		for _, sig := range signed.Signatures {
+			signatureKeyIDs = append(signatureKeyIDs, sig.KeyID)
+		}
+		Expect(signatureKeyIDs).To(ContainElements(oldRootSigner.PublicData().IDs()))
+		Expect(signatureKeyIDs).To(ContainElements(newRootSigner.PublicData().IDs()))
+
+		root := &data.Root{}
+		Expect(json.Unmarshal(signed.Signed, root)).To(Succeed())
+		Expect(root.Roles["root"].KeyIDs).To(ConsistOf(newRootSigner.PublicData().IDs()))
+	})
+})
+
+type testMemoryFilesystem struct {
+	mu     sync.Mutex
+	files  map[string][]byte
+	writes []string
+
+	// writeErr is returned by writes into the published (not staged) paths
+	writeErr error
+}
+
+func newTestMemoryFilesystem() *testMemoryFilesystem {
+	return &testMemoryFilesystem{files: make(map[string][]byte)}
+}
+
+func (fs *testMemoryFilesystem) IsFileExist(_ context.Context, path string) (bool, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	_, exists := fs.files[path]
+	return exists, nil
+}
+
+func (fs *testMemoryFilesystem) ReadFile(ctx context.Context, path string, writerAt io.WriterAt) error {
+	data, err := fs.ReadFileBytes(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	_, err = writerAt.WriteAt(data, 0)
+	return err
+}
+
+func (fs *testMemoryFilesystem) ReadFileStream(ctx context.Context, path string, writer io.Writer) error {
+	data, err := fs.ReadFileBytes(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(data)
+	return err
+}
+
+func (fs *testMemoryFilesystem) ReadFileBytes(_ context.Context, path string) ([]byte, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	data, exists := fs.files[path]
+	if !exists {
+		return nil, fmt.Errorf("file %q not found", path)
+	}
+
+	return data, nil
+}
+
+func (fs *testMemoryFilesystem) WriteFileBytes(_ context.Context, path string, data []byte) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.writeErr != nil && !strings.HasPrefix(path, stagedTargetsDir) {
+		return fs.writeErr
+	}
+
+	fs.files[path] = append([]byte(nil), data...)
+	fs.writes = append(fs.writes, path)
+	return nil
+}
+
+func (fs *testMemoryFilesystem) WriteFileStream(ctx context.Context, path string, reader io.Reader) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	return fs.WriteFileBytes(ctx, path, data)
+}
+
+func (fs *testMemoryFilesystem) DeleteFile(_ context.Context, path string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	delete(fs.files, path)
+	return nil
+}
+
+func (fs *testMemoryFilesystem) listFiles(prefix string) []string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	var res []string
+	for path := range fs.files {
+		if strings.HasPrefix(path, prefix) {
+			res = append(res, path)
+		}
+	}
+	return res
+}
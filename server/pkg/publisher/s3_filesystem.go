@@ -182,6 +182,26 @@ func (fs *S3Filesystem) WriteFileStream(ctx context.Context, path string, data i
 	return nil
 }
 
+func (fs *S3Filesystem) DeleteFile(ctx context.Context, path string) error {
+	sess, err := session.NewSession(fs.AwsConfig)
+	if err != nil {
+		return fmt.Errorf("error opening s3 session: %w", err)
+	}
+
+	svc := s3.New(sess)
+
+	if _, err := svc.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: &fs.BucketName,
+		Key:    &path,
+	}); err != nil {
+		return fmt.Errorf("error deleting s3 object by key %q: %w", path, err)
+	}
+
+	fs.logger.Debug(fmt.Sprintf("-- S3Filesystem.DeleteFile deleted %q", path))
+
+	return nil
+}
+
 type debugReader struct {
 	origReader io.Reader
 	logger     hclog.Logger
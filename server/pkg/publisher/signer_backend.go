@@ -0,0 +1,155 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/theupdateframework/go-tuf/data"
+	"github.com/theupdateframework/go-tuf/pkg/keys"
+)
+
+// KeyTypeExternal is the type of the TUF key kept by the SignerBackend.
+// The key is saved in the plugin storage as the reference to the backend key instead of the private key material.
+const KeyTypeExternal data.KeyType = "trdl-external"
+
+const (
+	SignerTypeLocal        = "local"
+	SignerTypeVaultTransit = "vault_transit"
+)
+
+type TufSignerOptions struct {
+	// Type is either local (the private keys are kept in the plugin storage) or vault_transit
+	Type         string
+	VaultTransit VaultTransitOptions
+}
+
+// SignerBackend generates and uses the ed25519 TUF keys which never leave the backend.
+type SignerBackend interface {
+	// Name identifies the backend in the saved key references.
+	Name() string
+	// GenerateKey creates a new key for the role and returns the key name in the backend and its public key.
+	GenerateKey(ctx context.Context, role string) (string, *data.PublicKey, error)
+	// Sign signs the message by the named key.
+	Sign(ctx context.Context, keyName string, message []byte) ([]byte, error)
+}
+
+var signerBackends sync.Map
+
+func init() {
+	keys.SignerMap.Store(KeyTypeExternal, newExternalSigner)
+}
+
+// RegisterSignerBackend makes the backend keys available for signing when the saved keys are loaded.
+// The backend registered previously by the same name is replaced.
+func RegisterSignerBackend(backend SignerBackend) {
+	signerBackends.Store(backend.Name(), backend)
+}
+
+func getSignerBackend(name string) (SignerBackend, error) {
+	backend, ok := signerBackends.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("signer backend %q is not configured", name)
+	}
+
+	return backend.(SignerBackend), nil
+}
+
+type externalKeyValue struct {
+	Backend string          `json:"backend"`
+	KeyName string          `json:"key_name"`
+	Public  *data.PublicKey `json:"public"`
+}
+
+type externalSigner struct {
+	backend SignerBackend
+	value   externalKeyValue
+}
+
+func newExternalSigner() keys.Signer {
+	return &externalSigner{}
+}
+
+// GenerateExternalKey creates a new key in the backend and returns the signer using it.
+func GenerateExternalKey(ctx context.Context, backend SignerBackend, role string) (keys.Signer, error) {
+	keyName, publicKey, err := backend.GenerateKey(ctx, role)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate %s key by the %q signer backend: %w", role, backend.Name(), err)
+	}
+
+	if publicKey.Type != data.KeyTypeEd25519 {
+		return nil, fmt.Errorf("unsupported key type %q generated by the %q signer backend: expected %q", publicKey.Type, backend.Name(), data.KeyTypeEd25519)
+	}
+
+	return &externalSigner{
+		backend: backend,
+		value:   externalKeyValue{Backend: backend.Name(), KeyName: keyName, Public: publicKey},
+	}, nil
+}
+
+func (signer *externalSigner) MarshalPrivateKey() (*data.PrivateKey, error) {
+	value, err := json.Marshal(signer.value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.PrivateKey{
+		Type:       KeyTypeExternal,
+		Scheme:     signer.value.Public.Scheme,
+		Algorithms: signer.value.Public.Algorithms,
+		Value:      value,
+	}, nil
+}
+
+func (signer *externalSigner) UnmarshalPrivateKey(key *data.PrivateKey) error {
+	if err := json.Unmarshal(key.Value, &signer.value); err != nil {
+		return fmt.Errorf("unable to unmarshal external key reference: %w", err)
+	}
+
+	if signer.value.Public == nil {
+		return fmt.Errorf("external key %q has no public key", signer.value.KeyName)
+	}
+
+	backend, err := getSignerBackend(signer.value.Backend)
+	if err != nil {
+		return fmt.Errorf("unable to use external key %q: %w", signer.value.KeyName, err)
+	}
+	signer.backend = backend
+
+	return nil
+}
+
+func (signer *externalSigner) PublicData() *data.PublicKey {
+	return signer.value.Public
+}
+
+func (signer *externalSigner) SignMessage(message []byte) ([]byte, error) {
+	sig, err := signer.backend.Sign(context.Background(), signer.value.KeyName, message)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign by the %q signer backend key %q: %w", signer.value.Backend, signer.value.KeyName, err)
+	}
+
+	// The signature is verified locally: a misconfigured backend must not publish metadata which clients reject
+	verifier, err := keys.GetVerifier(signer.value.Public)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get key verifier: %w", err)
+	}
+
+	if err := verifier.Verify(message, sig); err != nil {
+		return nil, fmt.Errorf("invalid signature produced by the %q signer backend key %q: %w", signer.value.Backend, signer.value.KeyName, err)
+	}
+
+	return sig, nil
+}
+
+func newSignerBackend(options RepositoryOptions) (SignerBackend, error) {
+	switch options.TufSigner.Type {
+	case SignerTypeLocal, "":
+		return nil, nil
+	case SignerTypeVaultTransit:
+		return NewVaultTransitSignerBackend(options.TufSigner.VaultTransit)
+	default:
+		return nil, fmt.Errorf("unknown TUF signer type %q", options.TufSigner.Type)
+	}
+}
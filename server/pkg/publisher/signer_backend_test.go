@@ -0,0 +1,211 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	tufClient "github.com/theupdateframework/go-tuf/client"
+	"github.com/theupdateframework/go-tuf/data"
+	"github.com/theupdateframework/go-tuf/pkg/keys"
+)
+
+var _ = Describe("S3Repository with the signer backend", func() {
+	var ctx context.Context
+	var fs *testMemoryFilesystem
+	var backend *LocalSignerBackend
+
+	newRepository := func(privKeys TufRepoPrivKeys) *S3Repository {
+		repository, err := NewRepositoryWithOptions(fs, TufRepoOptions{PrivKeys: privKeys, SignerBackend: backend}, hclog.NewNullLogger())
+		Expect(err).To(Succeed())
+		Expect(repository.Init()).To(Succeed())
+		return repository
+	}
+
+	publishTarget := func(repository *S3Repository, version string) {
+		Expect(repository.StageTarget(ctx, "channels/0/stable", bytes.NewBufferString(version))).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+	}
+
+	expectVerifiedTarget := func(version string) {
+		rootData, err := fs.ReadFileBytes(ctx, "1.root.json")
+		Expect(err).To(Succeed())
+
+		client := tufClient.NewClient(tufClient.MemoryLocalStore(), &testRemoteStore{fs: fs})
+		Expect(client.Init(rootData)).To(Succeed())
+		_, err = client.Update()
+		Expect(err).To(Succeed())
+
+		dest := &testBufferDestination{}
+		Expect(client.Download("channels/0/stable", dest)).To(Succeed())
+		Expect(dest.String()).To(Equal(version))
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fs = newTestMemoryFilesystem()
+		backend = NewLocalSignerBackend("test-signer-backend")
+		RegisterSignerBackend(backend)
+	})
+
+	It("should keep only the key references in the private keys", func() {
+		repository := newRepository(TufRepoPrivKeys{})
+		Expect(repository.GenPrivKeys()).To(Succeed())
+		publishTarget(repository, "1.0.0")
+
+		Expect(backend.KeyNames()).To(HaveLen(len(topLevelRoles)))
+
+		privKeys := repository.GetPrivKeys()
+		for _, role := range topLevelRoles {
+			roleKeys := *privKeys.getRoleKeys(role)
+			Expect(roleKeys).To(HaveLen(1))
+			Expect(roleKeys[0].Type).To(Equal(KeyTypeExternal))
+			Expect(string(roleKeys[0].Value)).NotTo(ContainSubstring("private"))
+		}
+
+		expectVerifiedTarget("1.0.0")
+	})
+
+	It("should sign by the keys loaded from the storage", func() {
+		repository := newRepository(TufRepoPrivKeys{})
+		Expect(repository.GenPrivKeys()).To(Succeed())
+		publishTarget(repository, "1.0.0")
+
+		privKeys, err := repository.GetPrivKeys().clone()
+		Expect(err).To(Succeed())
+
+		repository = newRepository(TufRepoPrivKeys{})
+		Expect(repository.SetPrivKeys(privKeys)).To(Succeed())
+		publishTarget(repository, "1.0.1")
+
+		expectVerifiedTarget("1.0.1")
+	})
+
+	It("should fail to load the keys of the unknown signer backend", func() {
+		repository := newRepository(TufRepoPrivKeys{})
+		Expect(repository.GenPrivKeys()).To(Succeed())
+
+		privKeys := repository.GetPrivKeys()
+		privKeys.Targets[0].Value = json.RawMessage(strings.Replace(string(privKeys.Targets[0].Value), "test-signer-backend", "unknown-signer-backend", 1))
+
+		repository = newRepository(TufRepoPrivKeys{})
+		Expect(repository.SetPrivKeys(privKeys)).To(MatchError(ContainSubstring(`signer backend "unknown-signer-backend" is not configured`)))
+	})
+})
+
+var _ = Describe("VaultTransitSignerBackend", func() {
+	var server *httptest.Server
+	var privateKey ed25519.PrivateKey
+	var badSignature bool
+
+	BeforeEach(func() {
+		var publicKey ed25519.PublicKey
+		var err error
+		publicKey, privateKey, err = ed25519.GenerateKey(rand.Reader)
+		Expect(err).To(Succeed())
+		badSignature = false
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+
+			Expect(r.Header.Get("X-Vault-Token")).To(Equal("test-token"))
+
+			switch {
+			case strings.HasPrefix(r.URL.Path, "/v1/trdl-transit/keys/") && r.Method == http.MethodPut:
+				w.WriteHeader(http.StatusNoContent)
+			case strings.HasPrefix(r.URL.Path, "/v1/trdl-transit/keys/") && r.Method == http.MethodGet:
+				Expect(json.NewEncoder(w).Encode(map[string]interface{}{
+					"data": map[string]interface{}{
+						"type":           "ed25519",
+						"latest_version": 1,
+						"keys": map[string]interface{}{
+							"1": map[string]interface{}{"public_key": base64.StdEncoding.EncodeToString(publicKey)},
+						},
+					},
+				})).To(Succeed())
+			case strings.HasPrefix(r.URL.Path, "/v1/trdl-transit/sign/") && r.Method == http.MethodPut:
+				var req struct {
+					Input string `json:"input"`
+				}
+				Expect(json.NewDecoder(r.Body).Decode(&req)).To(Succeed())
+				message, err := base64.StdEncoding.DecodeString(req.Input)
+				Expect(err).To(Succeed())
+
+				if badSignature {
+					message = append(message, '!')
+				}
+
+				Expect(json.NewEncoder(w).Encode(map[string]interface{}{
+					"data": map[string]interface{}{
+						"signature": "vault:v1:" + base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, message)),
+					},
+				})).To(Succeed())
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newSigner := func() keys.Signer {
+		backend, err := NewVaultTransitSignerBackend(VaultTransitOptions{Address: server.URL, Token: "test-token", MountPath: "/trdl-transit/"})
+		Expect(err).To(Succeed())
+		RegisterSignerBackend(backend)
+
+		signer, err := GenerateExternalKey(context.Background(), backend, "targets")
+		Expect(err).To(Succeed())
+
+		return signer
+	}
+
+	It("should sign by the transit key", func() {
+		signer := newSigner()
+
+		expectedPublicKey, err := json.Marshal(map[string]data.HexBytes{"public": data.HexBytes(privateKey.Public().(ed25519.PublicKey))})
+		Expect(err).To(Succeed())
+		Expect(string(signer.PublicData().Value)).To(Equal(string(expectedPublicKey)))
+
+		sig, err := signer.SignMessage([]byte("message"))
+		Expect(err).To(Succeed())
+		Expect(ed25519.Verify(privateKey.Public().(ed25519.PublicKey), []byte("message"), sig)).To(BeTrue())
+
+		// The key reference is restored into the signer
+		pk, err := signer.MarshalPrivateKey()
+		Expect(err).To(Succeed())
+		restoredSigner, err := keys.GetSigner(pk)
+		Expect(err).To(Succeed())
+		Expect(restoredSigner.PublicData().IDs()).To(Equal(signer.PublicData().IDs()))
+
+		_, err = restoredSigner.SignMessage([]byte("message"))
+		Expect(err).To(Succeed())
+	})
+
+	It("should reject the invalid signature", func() {
+		signer := newSigner()
+		badSignature = true
+
+		_, err := signer.SignMessage([]byte("message"))
+		Expect(err).To(MatchError(ContainSubstring("invalid signature")))
+	})
+})
+
+type testBufferDestination struct {
+	bytes.Buffer
+}
+
+func (dest *testBufferDestination) Delete() error {
+	dest.Reset()
+	return nil
+}
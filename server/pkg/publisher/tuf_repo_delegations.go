@@ -0,0 +1,380 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/theupdateframework/go-tuf"
+	"github.com/theupdateframework/go-tuf/data"
+	"github.com/theupdateframework/go-tuf/pkg/keys"
+	"github.com/theupdateframework/go-tuf/sign"
+)
+
+type delegatedTargetsMeta struct {
+	Role    data.DelegatedRole
+	Targets *data.Targets
+}
+
+// UpdateDelegatedRoles stages delegations of the top-level targets role to the given roles.
+// Keys are generated for new roles and existing targets are moved into the roles matching their paths,
+// so that clients never see outdated targets left in the top-level targets role.
+// Updated private keys must be saved before the staged changes are committed.
+func (repository *S3Repository) UpdateDelegatedRoles(_ context.Context, roles []*DelegatedRole) (bool, TufRepoPrivKeys, error) {
+	privKeys := &repository.TufStore.PrivKeys
+
+	meta, err := repository.StagingStore.GetMeta()
+	if err != nil {
+		return false, TufRepoPrivKeys{}, fmt.Errorf("unable to get TUF repository metadata: %w", err)
+	}
+
+	topLevelTargets, err := decodeTargetsMeta(meta["targets.json"])
+	if err != nil {
+		return false, TufRepoPrivKeys{}, fmt.Errorf("unable to decode targets.json: %w", err)
+	}
+
+	delegations := &data.Delegations{Keys: make(map[string]*data.PublicKey)}
+	for _, role := range roles {
+		signers, err := privKeys.GetDelegatedRoleSigners(role.Name)
+		if err != nil {
+			return false, TufRepoPrivKeys{}, fmt.Errorf("unable to get delegated role %q key signers: %w", role.Name, err)
+		}
+
+		if len(signers) != role.Threshold {
+			signers, err = repository.genDelegatedRoleKeys(role.Name, role.Threshold)
+			if err != nil {
+				return false, TufRepoPrivKeys{}, fmt.Errorf("unable to generate delegated role %q keys: %w", role.Name, err)
+			}
+		}
+
+		var keyIDs []string
+		for _, signer := range signers {
+			publicKey := signer.PublicData()
+			for _, keyID := range publicKey.IDs() {
+				delegations.Keys[keyID] = publicKey
+			}
+			keyIDs = append(keyIDs, publicKey.IDs()...)
+		}
+
+		delegations.Roles = append(delegations.Roles, data.DelegatedRole{
+			Name:        role.Name,
+			KeyIDs:      keyIDs,
+			Threshold:   role.Threshold,
+			Paths:       role.TufPaths(),
+			Terminating: true,
+		})
+	}
+
+	if len(delegations.Roles) == 0 {
+		delegations = nil
+	}
+
+	changed, err := isDelegationsChanged(topLevelTargets.Delegations, delegations)
+	if err != nil {
+		return false, TufRepoPrivKeys{}, err
+	}
+
+	if !changed {
+		return false, TufRepoPrivKeys{}, nil
+	}
+
+	for name := range privKeys.Delegations {
+		if delegations == nil || !hasDelegatedRole(delegations.Roles, name) {
+			privKeys.DeleteDelegatedRoleKeys(name)
+		}
+	}
+
+	if err := repository.stageDelegations(meta, topLevelTargets, delegations, time.Now()); err != nil {
+		return false, TufRepoPrivKeys{}, err
+	}
+
+	return true, *privKeys, nil
+}
+
+func (repository *S3Repository) genDelegatedRoleKeys(role string, count int) ([]keys.Signer, error) {
+	repository.TufStore.PrivKeys.DeleteDelegatedRoleKeys(role)
+
+	var signers []keys.Signer
+	for i := 0; i < count; i++ {
+		signer, err := repository.generateSigner(role)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := repository.TufStore.SaveSigner(role, signer); err != nil {
+			return nil, err
+		}
+
+		signers = append(signers, signer)
+	}
+
+	return signers, nil
+}
+
+func (repository *S3Repository) stageDelegations(meta map[string]json.RawMessage, topLevelTargets *data.Targets, delegations *data.Delegations, now time.Time) error {
+	currentDelegatedMeta, err := repository.getDelegatedTargetsMeta(meta)
+	if err != nil {
+		return err
+	}
+
+	// Clients look for the target in the top-level targets role first, then in the delegated roles in order
+	allTargets := make(data.TargetFiles)
+	for i := len(currentDelegatedMeta) - 1; i >= 0; i-- {
+		for targetPath, targetMeta := range currentDelegatedMeta[i].Targets.Targets {
+			allTargets[targetPath] = targetMeta
+		}
+	}
+	for targetPath, targetMeta := range topLevelTargets.Targets {
+		allTargets[targetPath] = targetMeta
+	}
+
+	var delegatedRoles []data.DelegatedRole
+	if delegations != nil {
+		delegatedRoles = delegations.Roles
+	}
+
+	topLevelTargets.Targets = make(data.TargetFiles)
+	rolesTargets := make(map[string]data.TargetFiles)
+	for _, role := range delegatedRoles {
+		rolesTargets[role.Name] = make(data.TargetFiles)
+	}
+
+	for targetPath, targetMeta := range allTargets {
+		if role := delegatedRoleForPath(delegatedRoles, targetPath); role != "" {
+			rolesTargets[role][targetPath] = targetMeta
+		} else {
+			topLevelTargets.Targets[targetPath] = targetMeta
+		}
+	}
+
+	expires := repository.Lifetimes.Targets.Expiration.AddTo(now).Round(time.Second)
+
+	for _, role := range delegatedRoles {
+		version, err := repository.getDelegatedRoleVersion(meta, role.Name)
+		if err != nil {
+			return err
+		}
+
+		t := data.NewTargets()
+		t.Version = version + 1
+		t.Expires = expires
+		t.Targets = rolesTargets[role.Name]
+
+		if err := repository.stageTargetsMeta(role.Name, role.KeyIDs, t); err != nil {
+			return err
+		}
+
+		repository.logger.Info(fmt.Sprintf("Staged delegated role %q metadata with %d targets", role.Name, len(t.Targets)))
+	}
+
+	targetsKeyIDs, err := getRootRoleKeyIDs(meta, "targets")
+	if err != nil {
+		return err
+	}
+
+	topLevelTargets.Delegations = delegations
+	topLevelTargets.Expires = expires
+	if !repository.StagingStore.FileIsStaged("targets.json") {
+		topLevelTargets.Version++
+	}
+
+	if err := repository.stageTargetsMeta("targets", targetsKeyIDs, topLevelTargets); err != nil {
+		return err
+	}
+
+	return repository.reloadTufRepo()
+}
+
+// rotateDelegatedRoles re-signs metadata of the delegated roles which hit the targets rotation period.
+func (repository *S3Repository) rotateDelegatedRoles(now time.Time) (bool, error) {
+	meta, err := repository.StagingStore.GetMeta()
+	if err != nil {
+		return false, fmt.Errorf("unable to get TUF repository metadata: %w", err)
+	}
+
+	delegatedMeta, err := repository.getDelegatedTargetsMeta(meta)
+	if err != nil {
+		return false, err
+	}
+
+	var rotated bool
+	for _, desc := range delegatedMeta {
+		if repository.getDelegatedRoleRotateAt(desc.Targets.Expires).After(now) {
+			continue
+		}
+
+		if !repository.StagingStore.FileIsStaged(desc.Role.Name + ".json") {
+			desc.Targets.Version++
+		}
+		desc.Targets.Expires = repository.Lifetimes.Targets.Expiration.AddTo(now).Round(time.Second)
+
+		if err := repository.stageTargetsMeta(desc.Role.Name, desc.Role.KeyIDs, desc.Targets); err != nil {
+			return false, err
+		}
+
+		repository.logger.Debug(fmt.Sprintf("rotated %s.json TUF repository delegated role", desc.Role.Name))
+		rotated = true
+	}
+
+	if !rotated {
+		return false, nil
+	}
+
+	return true, repository.reloadTufRepo()
+}
+
+func (repository *S3Repository) getDelegatedRoleRotateAt(expires time.Time) time.Time {
+	return repository.Lifetimes.Targets.RotationPeriod.AddTo(repository.Lifetimes.Targets.Expiration.SubFrom(expires))
+}
+
+// getDelegatedTargetsMeta returns metadata of the roles delegated by the top-level targets role in the delegations order.
+func (repository *S3Repository) getDelegatedTargetsMeta(meta map[string]json.RawMessage) ([]*delegatedTargetsMeta, error) {
+	targetsData, ok := meta["targets.json"]
+	if !ok {
+		return nil, nil
+	}
+
+	topLevelTargets, err := decodeTargetsMeta(targetsData)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode targets.json: %w", err)
+	}
+
+	if topLevelTargets.Delegations == nil {
+		return nil, nil
+	}
+
+	var res []*delegatedTargetsMeta
+	for _, role := range topLevelTargets.Delegations.Roles {
+		roleData, ok := meta[role.Name+".json"]
+		if !ok {
+			continue
+		}
+
+		t, err := decodeTargetsMeta(roleData)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode %s.json: %w", role.Name, err)
+		}
+
+		res = append(res, &delegatedTargetsMeta{Role: role, Targets: t})
+	}
+
+	return res, nil
+}
+
+// getDelegatedRoleVersion returns the current version of the role metadata,
+// metadata of the previously removed role is still in the store filesystem and its version should not be reused.
+func (repository *S3Repository) getDelegatedRoleVersion(meta map[string]json.RawMessage, role string) (int64, error) {
+	roleData, ok := meta[role+".json"]
+	if !ok {
+		var exists bool
+		var err error
+		roleData, exists, err = repository.TufStore.getMetaFile(context.Background(), role+".json")
+		if err != nil {
+			return 0, err
+		}
+
+		if !exists {
+			return 0, nil
+		}
+	}
+
+	t, err := decodeTargetsMeta(roleData)
+	if err != nil {
+		return 0, fmt.Errorf("unable to decode %s.json: %w", role, err)
+	}
+
+	return t.Version, nil
+}
+
+func (repository *S3Repository) stageTargetsMeta(role string, keyIDs []string, t *data.Targets) error {
+	signers := repository.TufStore.SignersForKeyIDs(keyIDs)
+	if len(signers) == 0 {
+		return fmt.Errorf("no keys available to sign %s.json", role)
+	}
+
+	signed, err := sign.Marshal(t, signers...)
+	if err != nil {
+		return fmt.Errorf("unable to sign %s.json: %w", role, err)
+	}
+
+	metaData, err := json.Marshal(signed)
+	if err != nil {
+		return fmt.Errorf("unable to marshal %s.json: %w", role, err)
+	}
+
+	return repository.StagingStore.SetMeta(role+".json", metaData)
+}
+
+// reloadTufRepo makes the TUF repository pick up the metadata staged directly into the store.
+func (repository *S3Repository) reloadTufRepo() error {
+	tufRepo, err := tuf.NewRepo(repository.StagingStore)
+	if err != nil {
+		return fmt.Errorf("error initializing tuf repo: %w", err)
+	}
+	repository.TufRepo = tufRepo
+
+	return nil
+}
+
+func getRootRoleKeyIDs(meta map[string]json.RawMessage, role string) ([]string, error) {
+	signed := &data.Signed{}
+	if err := json.Unmarshal(meta["root.json"], signed); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal root.json: %w", err)
+	}
+
+	root := &data.Root{}
+	if err := json.Unmarshal(signed.Signed, root); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal root.json signed data: %w", err)
+	}
+
+	rootRole, ok := root.Roles[role]
+	if !ok {
+		return nil, fmt.Errorf("role %q not found in root.json", role)
+	}
+
+	return rootRole.KeyIDs, nil
+}
+
+// delegatedRoleForPath returns the name of the first role matching the path or an empty string.
+func delegatedRoleForPath(roles []data.DelegatedRole, targetPath string) string {
+	for _, role := range roles {
+		if matches, err := role.MatchesPath(targetPath); err == nil && matches {
+			return role.Name
+		}
+	}
+
+	return ""
+}
+
+func hasDelegatedRole(roles []data.DelegatedRole, name string) bool {
+	for _, role := range roles {
+		if role.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isDelegationsChanged(current, desired *data.Delegations) (bool, error) {
+	if current != nil && len(current.Roles) == 0 {
+		current = nil
+	}
+
+	if current == nil || desired == nil {
+		return current != desired, nil
+	}
+
+	currentData, err := json.Marshal(current)
+	if err != nil {
+		return false, fmt.Errorf("unable to marshal current delegations: %w", err)
+	}
+
+	desiredData, err := json.Marshal(desired)
+	if err != nil {
+		return false, fmt.Errorf("unable to marshal desired delegations: %w", err)
+	}
+
+	return string(currentData) != string(desiredData), nil
+}
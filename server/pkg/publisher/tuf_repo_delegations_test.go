@@ -0,0 +1,192 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theupdateframework/go-tuf"
+	tufClient "github.com/theupdateframework/go-tuf/client"
+
+	"github.com/werf/trdl/server/pkg/util"
+)
+
+var _ = Describe("S3Repository delegated roles", func() {
+	var ctx context.Context
+	var fs *testMemoryFilesystem
+	var repository *S3Repository
+
+	roles := []*DelegatedRole{
+		{Name: "channels", Paths: []string{"channels/*/*"}, Threshold: 1},
+		{Name: "releases", Paths: []string{"releases/*/*/*/*", "signatures/*/*/*/*"}, Threshold: 2},
+	}
+
+	newClient := func() *tufClient.Client {
+		rootData, err := fs.ReadFileBytes(ctx, "root.json")
+		Expect(err).To(Succeed())
+
+		client := tufClient.NewClient(tufClient.MemoryLocalStore(), &testRemoteStore{fs: fs})
+		Expect(client.Init(rootData)).To(Succeed())
+		_, err = client.Update()
+		Expect(err).To(Succeed())
+
+		return client
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fs = newTestMemoryFilesystem()
+
+		tufStore := NewNonAtomicTufStore(TufRepoPrivKeys{}, fs, hclog.NewNullLogger())
+		tufRepo, err := tuf.NewRepo(tufStore)
+		Expect(err).To(Succeed())
+
+		repository = NewRepository(nil, tufStore, tufRepo, hclog.NewNullLogger())
+		Expect(repository.Init()).To(Succeed())
+		Expect(repository.GenPrivKeys()).To(Succeed())
+		Expect(repository.StageTarget(ctx, "channels/0/stable", bytes.NewBufferString("1.0.0\n"))).To(Succeed())
+		Expect(repository.StageTarget(ctx, "releases/1.0.0/any-any/bin/app", bytes.NewBufferString("app"))).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+	})
+
+	It("should move existing targets into the delegated roles", func() {
+		updated, privKeys, err := repository.UpdateDelegatedRoles(ctx, roles)
+		Expect(err).To(Succeed())
+		Expect(updated).To(BeTrue())
+		Expect(privKeys.Delegations["channels"]).To(HaveLen(1))
+		Expect(privKeys.Delegations["releases"]).To(HaveLen(2))
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		Expect(fs.listFiles("channels.json")).To(HaveLen(1))
+		Expect(fs.listFiles("releases.json")).To(HaveLen(1))
+
+		topLevelTargets, err := repository.TufRepo.Targets()
+		Expect(err).To(Succeed())
+		Expect(topLevelTargets).To(BeEmpty())
+
+		targets, err := repository.GetTargets(ctx)
+		Expect(err).To(Succeed())
+		Expect(targets).To(ConsistOf("channels/0/stable", "releases/1.0.0/any-any/bin/app"))
+
+		client := newClient()
+		_, err = client.Target("channels/0/stable")
+		Expect(err).To(Succeed())
+		_, err = client.Target("releases/1.0.0/any-any/bin/app")
+		Expect(err).To(Succeed())
+	})
+
+	It("should sign new targets by the delegated roles", func() {
+		_, _, err := repository.UpdateDelegatedRoles(ctx, roles)
+		Expect(err).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		Expect(repository.StageTarget(ctx, "channels/0/stable", bytes.NewBufferString("1.0.1\n"))).To(Succeed())
+		Expect(repository.StageTarget(ctx, "releases/1.0.1/any-any/bin/app", bytes.NewBufferString("app"))).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		delegatedMeta, err := repository.getDelegatedTargetsMeta(mustGetMeta(repository))
+		Expect(err).To(Succeed())
+		Expect(delegatedMeta).To(HaveLen(2))
+		Expect(delegatedMeta[0].Targets.Targets).To(HaveKey("channels/0/stable"))
+		Expect(delegatedMeta[1].Targets.Targets).To(HaveKey("releases/1.0.1/any-any/bin/app"))
+
+		client := newClient()
+		meta, err := client.Target("channels/0/stable")
+		Expect(err).To(Succeed())
+		Expect(meta.Length).To(Equal(int64(len("1.0.1\n"))))
+	})
+
+	It("should not update unchanged delegated roles", func() {
+		_, _, err := repository.UpdateDelegatedRoles(ctx, roles)
+		Expect(err).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		updated, _, err := repository.UpdateDelegatedRoles(ctx, roles)
+		Expect(err).To(Succeed())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should move targets back into the top-level targets role when the delegated role is removed", func() {
+		_, _, err := repository.UpdateDelegatedRoles(ctx, roles)
+		Expect(err).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		updated, privKeys, err := repository.UpdateDelegatedRoles(ctx, roles[:1])
+		Expect(err).To(Succeed())
+		Expect(updated).To(BeTrue())
+		Expect(privKeys.Delegations).NotTo(HaveKey("releases"))
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		topLevelTargets, err := repository.TufRepo.Targets()
+		Expect(err).To(Succeed())
+		Expect(topLevelTargets).To(HaveKey("releases/1.0.0/any-any/bin/app"))
+
+		client := newClient()
+		_, err = client.Target("releases/1.0.0/any-any/bin/app")
+		Expect(err).To(Succeed())
+	})
+
+	It("should rotate delegated roles", func() {
+		_, _, err := repository.UpdateDelegatedRoles(ctx, roles)
+		Expect(err).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+
+		statusBefore := getRoleStatus(repository, "releases")
+
+		now := time.Now().Add(repository.Lifetimes.Targets.RotationPeriod.MaxDuration() + time.Hour)
+		Expect(repository.UpdateTimestamps(ctx, util.NewFixedClock(now))).To(Succeed())
+
+		statusAfter := getRoleStatus(repository, "releases")
+		Expect(statusAfter.Version).To(Equal(statusBefore.Version + 1))
+		Expect(statusAfter.Expires).To(BeTemporally(">", statusBefore.Expires))
+		Expect(statusAfter.RotateAt).To(BeTemporally(">", now))
+	})
+})
+
+func mustGetMeta(repository *S3Repository) map[string]json.RawMessage {
+	meta, err := repository.StagingStore.GetMeta()
+	Expect(err).To(Succeed())
+	return meta
+}
+
+func getRoleStatus(repository *S3Repository, role string) *TufRoleStatus {
+	rolesStatus, err := repository.GetRolesStatus(context.Background())
+	Expect(err).To(Succeed())
+
+	for _, status := range rolesStatus {
+		if status.Role == role {
+			return status
+		}
+	}
+
+	Fail("role " + role + " status not found")
+	return nil
+}
+
+// testRemoteStore serves the repository from the test memory filesystem to the TUF client.
+type testRemoteStore struct {
+	fs *testMemoryFilesystem
+}
+
+func (store *testRemoteStore) GetMeta(name string) (io.ReadCloser, int64, error) {
+	return store.get(name)
+}
+
+func (store *testRemoteStore) GetTarget(path string) (io.ReadCloser, int64, error) {
+	return store.get("targets/" + strings.TrimPrefix(path, "/"))
+}
+
+func (store *testRemoteStore) get(path string) (io.ReadCloser, int64, error) {
+	data, err := store.fs.ReadFileBytes(context.Background(), path)
+	if err != nil {
+		return nil, 0, tufClient.ErrNotFound{File: path}
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), int64(len(data)), nil
+}
@@ -1,18 +1,170 @@
 package publisher
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/theupdateframework/go-tuf"
 	"github.com/theupdateframework/go-tuf/data"
 	"github.com/theupdateframework/go-tuf/pkg/keys"
 )
 
+var topLevelRoles = []string{"root", "targets", "snapshot", "timestamp"}
+
 type TufRepoPrivKeys struct {
-	Root      *data.PrivateKey `json:"root"`
-	Snapshot  *data.PrivateKey `json:"snapshot"`
-	Targets   *data.PrivateKey `json:"targets"`
-	Timestamp *data.PrivateKey `json:"timestamp"`
+	Root      TufRolePrivKeys `json:"root"`
+	Snapshot  TufRolePrivKeys `json:"snapshot"`
+	Targets   TufRolePrivKeys `json:"targets"`
+	Timestamp TufRolePrivKeys `json:"timestamp"`
+
+	// Delegations contains keys of the delegated targets roles by role name
+	Delegations map[string][]*data.PrivateKey `json:"delegations,omitempty"`
+
+	KeysInfo map[string]*TufRepoPrivKeyInfo `json:"keys_info,omitempty"`
+}
+
+// TufRolePrivKeys are the online keys of the top-level role.
+// Keys saved by the previous versions are stored as a single key object, which is decoded as well.
+type TufRolePrivKeys []*data.PrivateKey
+
+func (roleKeys *TufRolePrivKeys) UnmarshalJSON(b []byte) error {
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '{' {
+		key := new(data.PrivateKey)
+		if err := json.Unmarshal(trimmed, key); err != nil {
+			return err
+		}
+
+		*roleKeys = TufRolePrivKeys{key}
+		return nil
+	}
+
+	var res []*data.PrivateKey
+	if err := json.Unmarshal(b, &res); err != nil {
+		return err
+	}
+
+	*roleKeys = res
+	return nil
+}
+
+type TufRepoPrivKeyInfo struct {
+	CreatedAt time.Time     `json:"created_at"`
+	Lifetime  time.Duration `json:"lifetime"`
+}
+
+func (info *TufRepoPrivKeyInfo) ExpiresAt() time.Time {
+	return info.CreatedAt.Add(info.Lifetime)
+}
+
+// Root key lives for 2 years, targets key for a year, snapshot and timestamp keys for half a year
+func DefaultPrivKeyLifetime(role string) time.Duration {
+	switch role {
+	case "root":
+		return 2 * 365 * 24 * time.Hour
+	case "targets":
+		return 365 * 24 * time.Hour
+	case "snapshot", "timestamp":
+		return 183 * 24 * time.Hour
+	default:
+		panic(fmt.Sprintf("unknown role %q", role))
+	}
+}
+
+// TufRoleKeys defines how many keys of the top-level role are generated and kept online by the server
+// and how many of the role keys must sign the role metadata.
+// The root threshold may exceed the number of online keys:
+// root.json changes are signed by the offline root keys holders then.
+type TufRoleKeys struct {
+	Keys      int
+	Threshold int
+}
+
+type TufRepoRolesKeys struct {
+	Root      TufRoleKeys
+	Targets   TufRoleKeys
+	Snapshot  TufRoleKeys
+	Timestamp TufRoleKeys
+}
+
+// DefaultTufRepoRolesKeys: a single online key per role.
+func DefaultTufRepoRolesKeys() TufRepoRolesKeys {
+	return TufRepoRolesKeys{
+		Root:      TufRoleKeys{Keys: 1, Threshold: 1},
+		Targets:   TufRoleKeys{Keys: 1, Threshold: 1},
+		Snapshot:  TufRoleKeys{Keys: 1, Threshold: 1},
+		Timestamp: TufRoleKeys{Keys: 1, Threshold: 1},
+	}
+}
+
+// WithDefaults returns roles keys with unset numbers replaced by the default ones.
+func (rolesKeys TufRepoRolesKeys) WithDefaults() TufRepoRolesKeys {
+	defaults := DefaultTufRepoRolesKeys()
+
+	res := rolesKeys
+	for _, role := range []struct{ dst, def *TufRoleKeys }{
+		{&res.Root, &defaults.Root},
+		{&res.Targets, &defaults.Targets},
+		{&res.Snapshot, &defaults.Snapshot},
+		{&res.Timestamp, &defaults.Timestamp},
+	} {
+		if role.dst.Keys == 0 {
+			role.dst.Keys = role.def.Keys
+		}
+		if role.dst.Threshold == 0 {
+			role.dst.Threshold = role.def.Threshold
+		}
+	}
+
+	return res
+}
+
+// Validate checks that every role except root can be signed by the online keys.
+func (rolesKeys TufRepoRolesKeys) Validate() error {
+	for _, role := range topLevelRoles {
+		roleKeys := rolesKeys.Get(role)
+
+		if roleKeys.Keys < 1 || roleKeys.Threshold < 1 {
+			return fmt.Errorf("%s keys number and threshold must be positive", role)
+		}
+
+		if role != "root" && roleKeys.Threshold > roleKeys.Keys {
+			return fmt.Errorf("%s threshold %d must not exceed keys number %d", role, roleKeys.Threshold, roleKeys.Keys)
+		}
+	}
+
+	return nil
+}
+
+func (rolesKeys TufRepoRolesKeys) Get(role string) TufRoleKeys {
+	switch role {
+	case "root":
+		return rolesKeys.Root
+	case "targets":
+		return rolesKeys.Targets
+	case "snapshot":
+		return rolesKeys.Snapshot
+	case "timestamp":
+		return rolesKeys.Timestamp
+	default:
+		panic(fmt.Sprintf("unknown role %q", role))
+	}
+}
+
+func (privKeys TufRepoPrivKeys) GetKeyInfo(role string) *TufRepoPrivKeyInfo {
+	return privKeys.KeysInfo[role]
+}
+
+func (keys *TufRepoPrivKeys) SetKeyInfo(role string, createdAt time.Time, lifetime time.Duration) {
+	if keys.KeysInfo == nil {
+		keys.KeysInfo = make(map[string]*TufRepoPrivKeyInfo)
+	}
+
+	keys.KeysInfo[role] = &TufRepoPrivKeyInfo{
+		CreatedAt: createdAt.UTC().Round(time.Second),
+		Lifetime:  lifetime,
+	}
 }
 
 func (keys *TufRepoPrivKeys) SetKeyFromSigner(role string, signer keys.Signer) error {
@@ -21,87 +173,179 @@ func (keys *TufRepoPrivKeys) SetKeyFromSigner(role string, signer keys.Signer) e
 		return fmt.Errorf("unable to marshal signer private key: %w", err)
 	}
 
-	switch role {
-	case "root":
-		keys.Root = pk
+	if roleKeys := keys.getRoleKeys(role); roleKeys != nil {
+		for _, key := range *roleKeys {
+			if bytes.Equal(key.Value, pk.Value) {
+				return nil
+			}
+		}
 
-	case "targets":
-		keys.Targets = pk
+		*roleKeys = append(append(TufRolePrivKeys{}, *roleKeys...), pk)
+		return nil
+	}
 
-	case "snapshot":
-		keys.Snapshot = pk
+	keys.addDelegatedRoleKey(role, pk)
 
-	case "timestamp":
-		keys.Timestamp = pk
+	return nil
+}
 
-	default:
+// DeleteKey deletes the key of the top-level role by any of its key ids.
+func (privKeys *TufRepoPrivKeys) DeleteKey(role, keyID string) error {
+	roleKeys := privKeys.getRoleKeys(role)
+	if roleKeys == nil {
 		panic(fmt.Sprintf("unknown role %q", role))
 	}
 
+	var res TufRolePrivKeys
+	for _, key := range *roleKeys {
+		signer, err := keys.GetSigner(key)
+		if err != nil {
+			return fmt.Errorf("unable to get key signer: %w", err)
+		}
+
+		if !signer.PublicData().ContainsID(keyID) {
+			res = append(res, key)
+		}
+	}
+
+	*roleKeys = res
+
 	return nil
 }
 
+func (privKeys *TufRepoPrivKeys) getRoleKeys(role string) *TufRolePrivKeys {
+	switch role {
+	case "root":
+		return &privKeys.Root
+	case "targets":
+		return &privKeys.Targets
+	case "snapshot":
+		return &privKeys.Snapshot
+	case "timestamp":
+		return &privKeys.Timestamp
+	default:
+		return nil
+	}
+}
+
+func (keys *TufRepoPrivKeys) addDelegatedRoleKey(role string, pk *data.PrivateKey) {
+	if keys.Delegations == nil {
+		keys.Delegations = make(map[string][]*data.PrivateKey)
+	}
+
+	for _, key := range keys.Delegations[role] {
+		if bytes.Equal(key.Value, pk.Value) {
+			return
+		}
+	}
+
+	keys.Delegations[role] = append(keys.Delegations[role], pk)
+}
+
+func (keys *TufRepoPrivKeys) DeleteDelegatedRoleKeys(role string) {
+	delete(keys.Delegations, role)
+}
+
+func (privKeys TufRepoPrivKeys) GetDelegatedRoleSigners(role string) ([]keys.Signer, error) {
+	var signers []keys.Signer
+	for _, key := range privKeys.Delegations[role] {
+		signer, err := keys.GetSigner(key)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, signer)
+	}
+
+	return signers, nil
+}
+
 func (privKeys TufRepoPrivKeys) SetupStoreSigners(store tuf.LocalStore) error {
-	for _, role := range []string{"root", "targets", "snapshot", "timestamp"} {
-		signer, err := privKeys.GetSigner(role)
+	for _, role := range topLevelRoles {
+		signers, err := privKeys.GetSigners(role)
 		if err != nil {
-			return fmt.Errorf("unable to get key signer for role %q: %w", role, err)
+			return fmt.Errorf("unable to get key signers for role %q: %w", role, err)
 		}
-		if signer != nil {
+
+		for _, signer := range signers {
 			if err := store.SaveSigner(role, signer); err != nil {
 				return fmt.Errorf("unable to save key signer for role %q into tuf store: %w", role, err)
 			}
 		}
 	}
 
+	for role := range privKeys.Delegations {
+		signers, err := privKeys.GetDelegatedRoleSigners(role)
+		if err != nil {
+			return fmt.Errorf("unable to get key signers for delegated role %q: %w", role, err)
+		}
+
+		for _, signer := range signers {
+			if err := store.SaveSigner(role, signer); err != nil {
+				return fmt.Errorf("unable to save key signer for delegated role %q into tuf store: %w", role, err)
+			}
+		}
+	}
+
 	return nil
 }
 
 func (privKeys TufRepoPrivKeys) SetupTufRepoSigners(tufRepo *tuf.Repo) error {
-	for _, desc := range []struct {
-		role string
-		key  *data.PrivateKey
-	}{
-		{"root", privKeys.Root},
-		{"targets", privKeys.Targets},
-		{"snapshot", privKeys.Snapshot},
-		{"timestamp", privKeys.Timestamp},
-	} {
-		signer, err := keys.GetSigner(desc.key)
+	for _, role := range topLevelRoles {
+		signers, err := privKeys.GetSigners(role)
 		if err != nil {
-			return fmt.Errorf("unable to get key signer for role %s: %w", desc.role, err)
+			return fmt.Errorf("unable to get key signers for role %s: %w", role, err)
 		}
 
-		if err := tufRepo.AddPrivateKeyWithExpires(desc.role, signer, data.DefaultExpires("root")); err != nil {
-			return fmt.Errorf("unable to add tuf repository private key for role %s: %w", desc.role, err)
+		for _, signer := range signers {
+			if err := tufRepo.AddPrivateKeyWithExpires(role, signer, data.DefaultExpires("root")); err != nil {
+				return fmt.Errorf("unable to add tuf repository private key for role %s: %w", role, err)
+			}
 		}
 	}
 
 	return nil
 }
 
+// GetSigner returns the signer of the first key of the top-level role or nil if the role has no keys.
 func (privKeys TufRepoPrivKeys) GetSigner(role string) (keys.Signer, error) {
-	switch role {
-	case "root":
-		return toSigner(privKeys.Root)
-
-	case "targets":
-		return toSigner(privKeys.Targets)
-
-	case "snapshot":
-		return toSigner(privKeys.Snapshot)
+	signers, err := privKeys.GetSigners(role)
+	if err != nil || len(signers) == 0 {
+		return nil, err
+	}
 
-	case "timestamp":
-		return toSigner(privKeys.Timestamp)
+	return signers[0], nil
+}
 
-	default:
+func (privKeys TufRepoPrivKeys) GetSigners(role string) ([]keys.Signer, error) {
+	roleKeys := privKeys.getRoleKeys(role)
+	if roleKeys == nil {
 		panic(fmt.Sprintf("unknown role %q", role))
 	}
+
+	var signers []keys.Signer
+	for _, key := range *roleKeys {
+		signer, err := keys.GetSigner(key)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, signer)
+	}
+
+	return signers, nil
 }
 
-func toSigner(key *data.PrivateKey) (keys.Signer, error) {
-	if key == nil {
-		return nil, nil
+// clone returns a deep copy of the keys, so that the changes of the copy do not affect the original keys.
+func (privKeys TufRepoPrivKeys) clone() (TufRepoPrivKeys, error) {
+	var res TufRepoPrivKeys
+
+	data, err := json.Marshal(privKeys)
+	if err != nil {
+		return TufRepoPrivKeys{}, err
+	}
+
+	if err := json.Unmarshal(data, &res); err != nil {
+		return TufRepoPrivKeys{}, err
 	}
-	return keys.GetSigner(key)
+
+	return res, nil
 }
@@ -0,0 +1,444 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/secure-systems-lab/go-securesystemslib/cjson"
+	"github.com/theupdateframework/go-tuf/data"
+	"github.com/theupdateframework/go-tuf/verify"
+)
+
+const storageKeyTufPendingRoot = "tuf_pending_root"
+
+var ErrNoPendingRoot = errors.New("no pending root.json changes")
+
+// PendingRoot is the root.json change waiting for the signatures of the offline root keys.
+type PendingRoot struct {
+	// Root is the signed root.json, the imported signatures are added to it
+	Root json.RawMessage `json:"root"`
+	// PrivKeys are the keys to switch to when the root is committed, set when the change is caused by the keys rotation
+	PrivKeys *TufRepoPrivKeys `json:"priv_keys,omitempty"`
+}
+
+// Payload returns the canonical root.json signed data to be signed by the offline root keys.
+func (pendingRoot *PendingRoot) Payload() ([]byte, error) {
+	signed, _, err := decodeRootMeta(pendingRoot.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	return cjson.EncodeCanonical(signed.Signed)
+}
+
+func (pendingRoot *PendingRoot) GetRoot() (*data.Signed, *data.Root, error) {
+	return decodeRootMeta(pendingRoot.Root)
+}
+
+func GetPendingRoot(ctx context.Context, storage logical.Storage) (*PendingRoot, error) {
+	entry, err := storage.Get(ctx, storageKeyTufPendingRoot)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get %q from storage: %w", storageKeyTufPendingRoot, err)
+	}
+
+	if entry == nil {
+		return nil, nil
+	}
+
+	pendingRoot := new(PendingRoot)
+	if err := entry.DecodeJSON(pendingRoot); err != nil {
+		return nil, fmt.Errorf("unable to decode pending root: %w", err)
+	}
+
+	return pendingRoot, nil
+}
+
+func PutPendingRoot(ctx context.Context, storage logical.Storage, pendingRoot *PendingRoot) error {
+	entry, err := logical.StorageEntryJSON(storageKeyTufPendingRoot, pendingRoot)
+	if err != nil {
+		return fmt.Errorf("error creating storage json entry by key %q: %w", storageKeyTufPendingRoot, err)
+	}
+
+	if err := storage.Put(ctx, entry); err != nil {
+		return fmt.Errorf("unable to put pending root into storage: %w", err)
+	}
+
+	return nil
+}
+
+func DeletePendingRoot(ctx context.Context, storage logical.Storage) error {
+	if err := storage.Delete(ctx, storageKeyTufPendingRoot); err != nil {
+		return fmt.Errorf("unable to delete pending root from storage: %w", err)
+	}
+
+	return nil
+}
+
+// RootSignaturesRequiredError means that the root.json change is not signed by enough root keys.
+// The change is not committed and should be saved as the pending root until the offline signatures are imported.
+type RootSignaturesRequiredError struct {
+	PendingRoot *PendingRoot
+}
+
+func (e *RootSignaturesRequiredError) Error() string {
+	return "root.json change requires signatures of the offline root keys"
+}
+
+// AddRootKey adds the public key (normally the offline one) to the root role and applies the configured root threshold.
+func (repository *S3Repository) AddRootKey(ctx context.Context, key *data.PublicKey) error {
+	now := time.Now()
+
+	if err := repository.TufRepo.AddVerificationKeyWithExpiration("root", key, repository.Lifetimes.Root.Expiration.AddTo(now)); err != nil {
+		return fmt.Errorf("unable to add root key: %w", err)
+	}
+
+	if _, err := repository.updateRoleKeys("root", now); err != nil {
+		return fmt.Errorf("unable to update root keys: %w", err)
+	}
+
+	if !repository.StagingStore.FileIsStaged("root.json") {
+		return nil
+	}
+
+	if err := repository.checkStagedRootSignatures(ctx); err != nil {
+		if discardErr := repository.DiscardStaged(ctx); discardErr != nil {
+			return discardErr
+		}
+
+		return err
+	}
+
+	return repository.CommitStaged(ctx)
+}
+
+// AddRootSignature adds the signature made by the offline root key to the pending root.json.
+// The updated root.json is returned along with the flag whether it is signed enough to be committed.
+func (repository *S3Repository) AddRootSignature(ctx context.Context, rootData json.RawMessage, signature data.Signature) (json.RawMessage, bool, error) {
+	signed, root, err := decodeRootMeta(rootData)
+	if err != nil {
+		return nil, false, err
+	}
+
+	trustedRoot, err := repository.getTrustedRoot(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := checkPendingRootVersion(trustedRoot, root); err != nil {
+		return nil, false, err
+	}
+
+	// The signature is accepted only if it is made by the key of the new or the currently trusted root role
+	var verified bool
+	for _, r := range []*data.Root{root, trustedRoot} {
+		if r == nil || !isRootKey(r, signature.KeyID) {
+			continue
+		}
+
+		if err := verifyRootKeySignature(r, signed, signature); err != nil {
+			return nil, false, fmt.Errorf("invalid signature of the key %q: %w", signature.KeyID, err)
+		}
+
+		verified = true
+		break
+	}
+
+	if !verified {
+		return nil, false, fmt.Errorf("key %q is not a root key", signature.KeyID)
+	}
+
+	signatures := []data.Signature{signature}
+	for _, sig := range signed.Signatures {
+		if sig.KeyID != signature.KeyID {
+			signatures = append(signatures, sig)
+		}
+	}
+	signed.Signatures = signatures
+
+	newRootData, err := json.Marshal(signed)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to marshal root.json: %w", err)
+	}
+
+	if err := repository.verifyRootSignatures(ctx, newRootData); err != nil {
+		if isThresholdError(err) {
+			return newRootData, false, nil
+		}
+		return nil, false, err
+	}
+
+	return newRootData, true, nil
+}
+
+// CommitRoot publishes the root.json signed by the offline root keys and re-signs other top-level roles.
+// The private keys are switched to the given ones before signing, if set.
+func (repository *S3Repository) CommitRoot(ctx context.Context, rootData json.RawMessage, privKeys *TufRepoPrivKeys) error {
+	_, root, err := decodeRootMeta(rootData)
+	if err != nil {
+		return err
+	}
+
+	trustedRoot, err := repository.getTrustedRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := checkPendingRootVersion(trustedRoot, root); err != nil {
+		return err
+	}
+
+	if err := repository.verifyRootSignatures(ctx, rootData); err != nil {
+		return fmt.Errorf("root.json is not signed by enough root keys: %w", err)
+	}
+
+	if privKeys != nil {
+		repository.TufStore.PrivKeys = *privKeys
+		if err := privKeys.SetupStoreSigners(repository.TufStore); err != nil {
+			return fmt.Errorf("unable to set private keys into tuf store: %w", err)
+		}
+	}
+
+	if err := repository.StagingStore.SetMeta("root.json", rootData); err != nil {
+		return fmt.Errorf("unable to stage root.json: %w", err)
+	}
+
+	if err := repository.reloadTufRepo(); err != nil {
+		return err
+	}
+
+	rotator := repository.rotator()
+	rotator.SkipRoot = true
+	if err := rotator.ForceRotate(repository.logger, time.Now()); err != nil {
+		if discardErr := repository.DiscardStaged(ctx); discardErr != nil {
+			repository.logger.Error(fmt.Sprintf("Unable to discard staged changes: %s", discardErr))
+		}
+
+		return fmt.Errorf("unable to commit root.json: %w", err)
+	}
+
+	repository.replicateToMirrors(ctx)
+
+	return nil
+}
+
+// canSignRootOnline reports whether the online root keys meet the root threshold.
+func (repository *S3Repository) canSignRootOnline() (bool, error) {
+	threshold, err := repository.TufRepo.GetThreshold("root")
+	if err != nil {
+		return false, fmt.Errorf("unable to get root threshold: %w", err)
+	}
+
+	keyIDs, err := repository.getRoleKeyIDs("root")
+	if err != nil {
+		return false, err
+	}
+
+	signers, err := repository.TufStore.PrivKeys.GetSigners("root")
+	if err != nil {
+		return false, fmt.Errorf("unable to get root key signers: %w", err)
+	}
+
+	var onlineKeys int
+	for _, signer := range signers {
+		for _, keyID := range keyIDs {
+			if signer.PublicData().ContainsID(keyID) {
+				onlineKeys++
+				break
+			}
+		}
+	}
+
+	return onlineKeys >= threshold, nil
+}
+
+// stageRootRotation prepares the pending root.json with the rotated expiration when the rotation period is hit.
+func (repository *S3Repository) stageRootRotation(ctx context.Context, rotator *TufRepoRotator, now time.Time) error {
+	rotateAt, err := rotator.GetRootRotateAt()
+	if err != nil {
+		return fmt.Errorf("unable to get root.json rotation time: %w", err)
+	}
+
+	if rotateAt.After(now) {
+		return nil
+	}
+
+	if err := rotator.RotateRoot(now); err != nil {
+		return fmt.Errorf("unable to rotate root.json: %w", err)
+	}
+
+	meta, err := repository.StagingStore.GetMeta()
+	if err != nil {
+		return fmt.Errorf("unable to get TUF repository metadata: %w", err)
+	}
+	rootData := meta["root.json"]
+
+	if err := repository.DiscardStaged(ctx); err != nil {
+		return err
+	}
+
+	return &RootSignaturesRequiredError{PendingRoot: &PendingRoot{Root: rootData}}
+}
+
+// checkStagedRootSignatures returns RootSignaturesRequiredError if the staged root.json is not signed by enough root keys.
+func (repository *S3Repository) checkStagedRootSignatures(ctx context.Context) error {
+	if !repository.StagingStore.FileIsStaged("root.json") {
+		return nil
+	}
+
+	meta, err := repository.StagingStore.GetMeta()
+	if err != nil {
+		return fmt.Errorf("unable to get TUF repository metadata: %w", err)
+	}
+	rootData := meta["root.json"]
+
+	if err := repository.verifyRootSignatures(ctx, rootData); err != nil {
+		if isThresholdError(err) {
+			return &RootSignaturesRequiredError{PendingRoot: &PendingRoot{Root: rootData}}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// verifyRootSignatures checks that root.json is signed by the thresholds of both the new and the currently trusted root keys,
+// as clients do when updating the root.
+func (repository *S3Repository) verifyRootSignatures(ctx context.Context, rootData json.RawMessage) error {
+	signed, root, err := decodeRootMeta(rootData)
+	if err != nil {
+		return err
+	}
+
+	trustedRoot, err := repository.getTrustedRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range []*data.Root{trustedRoot, root} {
+		if r == nil {
+			continue
+		}
+
+		db, err := newRootKeysDB(r)
+		if err != nil {
+			return err
+		}
+
+		if err := db.VerifySignatures(signed, "root"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getTrustedRoot returns the published root or nil if the repository is not published yet.
+func (repository *S3Repository) getTrustedRoot(ctx context.Context) (*data.Root, error) {
+	rootData, _, err := repository.GetRootMeta(ctx)
+	if err == ErrUninitializedRepositoryRoot {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, root, err := decodeRootMeta(rootData)
+	return root, err
+}
+
+func (repository *S3Repository) getRoleKeyIDs(role string) ([]string, error) {
+	meta, err := repository.StagingStore.GetMeta()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get TUF repository metadata: %w", err)
+	}
+
+	return getRootRoleKeyIDs(meta, role)
+}
+
+// DiscardStaged forgets the staged changes, removes the staged objects and reloads the published TUF metadata.
+func (repository *S3Repository) DiscardStaged(ctx context.Context) error {
+	if err := repository.StagingStore.DiscardStaged(ctx); err != nil {
+		return fmt.Errorf("unable to discard staged changes: %w", err)
+	}
+
+	return repository.reloadTufRepo()
+}
+
+func checkPendingRootVersion(trustedRoot, root *data.Root) error {
+	if trustedRoot != nil && root.Version != trustedRoot.Version+1 {
+		return fmt.Errorf("pending root.json version %d does not follow the published version %d", root.Version, trustedRoot.Version)
+	}
+
+	return nil
+}
+
+func newRootKeysDB(root *data.Root) (*verify.DB, error) {
+	db := verify.NewDB()
+	for id, key := range root.Keys {
+		if err := db.AddKey(id, key); err != nil {
+			return nil, fmt.Errorf("unable to add root key %q: %w", id, err)
+		}
+	}
+
+	role, ok := root.Roles["root"]
+	if !ok {
+		return nil, fmt.Errorf("root role not found in root.json")
+	}
+
+	if err := db.AddRole("root", role); err != nil {
+		return nil, fmt.Errorf("unable to add root role: %w", err)
+	}
+
+	return db, nil
+}
+
+func verifyRootKeySignature(root *data.Root, signed *data.Signed, signature data.Signature) error {
+	db := verify.NewDB()
+	if err := db.AddKey(signature.KeyID, root.Keys[signature.KeyID]); err != nil {
+		return err
+	}
+
+	if err := db.AddRole("root", &data.Role{KeyIDs: []string{signature.KeyID}, Threshold: 1}); err != nil {
+		return err
+	}
+
+	return db.VerifySignatures(&data.Signed{Signed: signed.Signed, Signatures: []data.Signature{signature}}, "root")
+}
+
+func isRootKey(root *data.Root, keyID string) bool {
+	role, ok := root.Roles["root"]
+	if !ok || root.Keys[keyID] == nil {
+		return false
+	}
+
+	for _, id := range role.KeyIDs {
+		if id == keyID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isThresholdError(err error) bool {
+	var thresholdErr verify.ErrRoleThreshold
+	return errors.As(err, &thresholdErr)
+}
+
+func decodeRootMeta(rootData json.RawMessage) (*data.Signed, *data.Root, error) {
+	signed := &data.Signed{}
+	if err := json.Unmarshal(rootData, signed); err != nil {
+		return nil, nil, fmt.Errorf("unable to unmarshal root.json: %w", err)
+	}
+
+	root := &data.Root{}
+	if err := json.Unmarshal(signed.Signed, root); err != nil {
+		return nil, nil, fmt.Errorf("unable to unmarshal root.json signed data: %w", err)
+	}
+
+	return signed, root, nil
+}
@@ -0,0 +1,210 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/sdk/logical"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theupdateframework/go-tuf"
+	tufClient "github.com/theupdateframework/go-tuf/client"
+	"github.com/theupdateframework/go-tuf/data"
+	"github.com/theupdateframework/go-tuf/pkg/keys"
+
+	"github.com/werf/trdl/server/pkg/util"
+)
+
+var _ = Describe("TufRepoPrivKeys", func() {
+	It("should decode keys saved as a single key per role", func() {
+		signer, err := keys.GenerateEd25519Key()
+		Expect(err).To(Succeed())
+		pk, err := signer.MarshalPrivateKey()
+		Expect(err).To(Succeed())
+
+		keyData, err := json.Marshal(pk)
+		Expect(err).To(Succeed())
+
+		var privKeys TufRepoPrivKeys
+		Expect(json.Unmarshal([]byte(`{"root":`+string(keyData)+`,"targets":null}`), &privKeys)).To(Succeed())
+		Expect(privKeys.Root).To(HaveLen(1))
+		Expect(privKeys.Root[0].Value).To(Equal(pk.Value))
+		Expect(privKeys.Targets).To(BeEmpty())
+	})
+})
+
+var _ = Describe("S3Repository roles keys", func() {
+	var ctx context.Context
+	var fs *testMemoryFilesystem
+	var storage logical.Storage
+	var pub *Publisher
+	var repository *S3Repository
+
+	initRepository := func(rolesKeys TufRepoRolesKeys) {
+		tufStore := NewNonAtomicTufStore(TufRepoPrivKeys{}, fs, hclog.NewNullLogger())
+		tufRepo, err := tuf.NewRepo(tufStore)
+		Expect(err).To(Succeed())
+
+		repository = NewRepository(nil, tufStore, tufRepo, hclog.NewNullLogger())
+		repository.RolesKeys = rolesKeys.WithDefaults()
+		Expect(repository.Init()).To(Succeed())
+		Expect(repository.GenPrivKeys()).To(Succeed())
+		Expect(repository.StageTarget(ctx, "channels/0/stable", bytes.NewBufferString("1.0.0\n"))).To(Succeed())
+		Expect(repository.CommitStaged(ctx)).To(Succeed())
+	}
+
+	// newVerifiedClient initializes the client by the first root version and updates it through all published versions
+	newVerifiedClient := func() *tufClient.Client {
+		rootData, err := fs.ReadFileBytes(ctx, "1.root.json")
+		Expect(err).To(Succeed())
+
+		client := tufClient.NewClient(tufClient.MemoryLocalStore(), &testRemoteStore{fs: fs})
+		Expect(client.Init(rootData)).To(Succeed())
+		_, err = client.Update()
+		Expect(err).To(Succeed())
+		_, err = client.Target("channels/0/stable")
+		Expect(err).To(Succeed())
+
+		return client
+	}
+
+	signPendingRoot := func(signer keys.Signer) bool {
+		pendingRoot, err := GetPendingRoot(ctx, storage)
+		Expect(err).To(Succeed())
+		Expect(pendingRoot).NotTo(BeNil())
+
+		payload, err := pendingRoot.Payload()
+		Expect(err).To(Succeed())
+		sig, err := signer.SignMessage(payload)
+		Expect(err).To(Succeed())
+
+		committed, err := pub.AddRootSignature(ctx, storage, repository, data.Signature{KeyID: signer.PublicData().IDs()[0], Signature: sig})
+		Expect(err).To(Succeed())
+
+		return committed
+	}
+
+	publishedRootVersion := func() int64 {
+		_, version, err := repository.GetRootMeta(ctx)
+		Expect(err).To(Succeed())
+		return version
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fs = newTestMemoryFilesystem()
+		storage = &logical.InmemStorage{}
+		pub = NewPublisher(hclog.NewNullLogger())
+	})
+
+	It("should generate the configured number of keys and thresholds", func() {
+		initRepository(TufRepoRolesKeys{Targets: TufRoleKeys{Keys: 3, Threshold: 2}})
+
+		Expect(repository.GetPrivKeys().Targets).To(HaveLen(3))
+		Expect(repository.TufRepo.GetThreshold("targets")).To(Equal(2))
+
+		newVerifiedClient()
+	})
+
+	It("should apply the changed number of keys and thresholds", func() {
+		initRepository(TufRepoRolesKeys{})
+
+		repository.RolesKeys.Snapshot = TufRoleKeys{Keys: 2, Threshold: 2}
+		updated, privKeys, err := repository.RotatePrivKeys(ctx, util.NewFixedClock(time.Now()))
+		Expect(err).To(Succeed())
+		Expect(updated).To(BeTrue())
+		Expect(privKeys.Snapshot).To(HaveLen(2))
+		Expect(repository.TufRepo.GetThreshold("snapshot")).To(Equal(2))
+
+		repository.RolesKeys.Snapshot = TufRoleKeys{Keys: 1, Threshold: 1}
+		updated, privKeys, err = repository.RotatePrivKeys(ctx, util.NewFixedClock(time.Now()))
+		Expect(err).To(Succeed())
+		Expect(updated).To(BeTrue())
+		Expect(privKeys.Snapshot).To(HaveLen(1))
+
+		newVerifiedClient()
+	})
+
+	Context("with the offline root key", func() {
+		var offlineSigner keys.Signer
+
+		BeforeEach(func() {
+			var err error
+			offlineSigner, err = keys.GenerateEd25519Key()
+			Expect(err).To(Succeed())
+
+			initRepository(TufRepoRolesKeys{Root: TufRoleKeys{Keys: 1, Threshold: 2}})
+
+			Expect(pub.AddRootKey(ctx, storage, repository, offlineSigner.PublicData())).To(Succeed())
+			Expect(publishedRootVersion()).To(Equal(int64(1)))
+			Expect(signPendingRoot(offlineSigner)).To(BeTrue())
+		})
+
+		It("should publish root signed by the offline key", func() {
+			Expect(publishedRootVersion()).To(Equal(int64(2)))
+			Expect(repository.TufRepo.GetThreshold("root")).To(Equal(2))
+
+			pendingRoot, err := GetPendingRoot(ctx, storage)
+			Expect(err).To(Succeed())
+			Expect(pendingRoot).To(BeNil())
+
+			newVerifiedClient()
+		})
+
+		It("should reject the signature of the unknown key", func() {
+			now := time.Now().Add(repository.Lifetimes.Root.RotationPeriod.MaxDuration() + time.Hour)
+			Expect(pub.UpdateTimestamps(ctx, storage, repository, util.NewFixedClock(now))).To(Succeed())
+
+			unknownSigner, err := keys.GenerateEd25519Key()
+			Expect(err).To(Succeed())
+
+			_, err = pub.AddRootSignature(ctx, storage, repository, data.Signature{KeyID: unknownSigner.PublicData().IDs()[0], Signature: []byte("signature")})
+			Expect(err).To(MatchError(ContainSubstring("is not a root key")))
+		})
+
+		It("should rotate root only when it is signed by the offline key", func() {
+			now := time.Now().Add(repository.Lifetimes.Root.RotationPeriod.MaxDuration() + time.Hour)
+			Expect(pub.UpdateTimestamps(ctx, storage, repository, util.NewFixedClock(now))).To(Succeed())
+
+			// Other roles are rotated without waiting for the root signatures
+			Expect(getRoleStatus(repository, "timestamp").RotateAt).To(BeTemporally(">", now))
+			Expect(publishedRootVersion()).To(Equal(int64(2)))
+
+			Expect(signPendingRoot(offlineSigner)).To(BeTrue())
+			Expect(publishedRootVersion()).To(Equal(int64(3)))
+			Expect(getRoleStatus(repository, "root").RotateAt).To(BeTemporally(">", time.Now()))
+
+			newVerifiedClient()
+		})
+
+		It("should switch to the rotated keys when root is signed by the offline key", func() {
+			oldPrivKeys := repository.GetPrivKeys()
+
+			clock := util.NewFixedClock(time.Now().AddDate(3, 0, 0))
+			Expect(pub.RotateRepositoryKeys(ctx, storage, repository, clock)).To(Succeed())
+			Expect(publishedRootVersion()).To(Equal(int64(2)))
+			Expect(repository.GetPrivKeys().Targets).To(Equal(oldPrivKeys.Targets))
+
+			// Keys are not rotated again while the root is pending
+			pendingRootBefore, err := GetPendingRoot(ctx, storage)
+			Expect(err).To(Succeed())
+			Expect(pub.RotateRepositoryKeys(ctx, storage, repository, clock)).To(Succeed())
+			Expect(GetPendingRoot(ctx, storage)).To(Equal(pendingRootBefore))
+
+			Expect(signPendingRoot(offlineSigner)).To(BeTrue())
+			Expect(publishedRootVersion()).To(Equal(int64(3)))
+			Expect(repository.GetPrivKeys().Targets).NotTo(Equal(oldPrivKeys.Targets))
+
+			entry, err := storage.Get(ctx, storageKeyTufRepositoryKeys)
+			Expect(err).To(Succeed())
+			var storedPrivKeys TufRepoPrivKeys
+			Expect(entry.DecodeJSON(&storedPrivKeys)).To(Succeed())
+			Expect(storedPrivKeys.Targets).To(Equal(repository.GetPrivKeys().Targets))
+
+			newVerifiedClient()
+		})
+	})
+})
@@ -7,25 +7,135 @@ import (
 	"github.com/hashicorp/go-hclog"
 )
 
+// TufRoleLifetime defines how long the role metadata is valid and how often it is re-signed.
+type TufRoleLifetime struct {
+	Expiration     Period
+	RotationPeriod Period
+}
+
+type TufRepoLifetimes struct {
+	Root      TufRoleLifetime
+	Targets   TufRoleLifetime
+	Snapshot  TufRoleLifetime
+	Timestamp TufRoleLifetime
+}
+
+// DefaultTufRepoLifetimes:
+// root expires every year, rotate every 3 months;
+// targets expires every 3 months, rotate every 3 weeks;
+// snapshot expires every 7 days, rotate every 2nd day;
+// timestamp expires every day, rotate every 4th hour.
+func DefaultTufRepoLifetimes() TufRepoLifetimes {
+	return TufRepoLifetimes{
+		Root:      TufRoleLifetime{Expiration: Period{Years: 1}, RotationPeriod: Period{Months: 3}},
+		Targets:   TufRoleLifetime{Expiration: Period{Months: 3}, RotationPeriod: Period{Days: 21}},
+		Snapshot:  TufRoleLifetime{Expiration: Period{Days: 7}, RotationPeriod: Period{Days: 2}},
+		Timestamp: TufRoleLifetime{Expiration: Period{Days: 1}, RotationPeriod: Period{Duration: 4 * time.Hour}},
+	}
+}
+
+// WithDefaults returns lifetimes with unset periods replaced by the default ones.
+func (lifetimes TufRepoLifetimes) WithDefaults() TufRepoLifetimes {
+	defaults := DefaultTufRepoLifetimes()
+
+	res := lifetimes
+	for _, role := range []struct{ dst, def *TufRoleLifetime }{
+		{&res.Root, &defaults.Root},
+		{&res.Targets, &defaults.Targets},
+		{&res.Snapshot, &defaults.Snapshot},
+		{&res.Timestamp, &defaults.Timestamp},
+	} {
+		if role.dst.Expiration.IsZero() {
+			role.dst.Expiration = role.def.Expiration
+		}
+		if role.dst.RotationPeriod.IsZero() {
+			role.dst.RotationPeriod = role.def.RotationPeriod
+		}
+	}
+
+	return res
+}
+
+// Validate checks that every role is rotated before its metadata expires.
+// The roles are rotated by the task running every rotationCheckPeriod, so the task may rotate the role up to the period late
+// and one more run is allowed to fail: expiration must exceed rotation period by at least two check periods.
+func (lifetimes TufRepoLifetimes) Validate(rotationCheckPeriod time.Duration) error {
+	for _, role := range []struct {
+		name     string
+		lifetime TufRoleLifetime
+	}{
+		{"root", lifetimes.Root},
+		{"targets", lifetimes.Targets},
+		{"snapshot", lifetimes.Snapshot},
+		{"timestamp", lifetimes.Timestamp},
+	} {
+		if role.lifetime.RotationPeriod.IsZero() || role.lifetime.Expiration.IsZero() {
+			return fmt.Errorf("%s expiration and rotation period must be set", role.name)
+		}
+
+		if role.lifetime.RotationPeriod.MaxDuration() >= role.lifetime.Expiration.MinDuration() {
+			return fmt.Errorf("%s rotation period %s must be shorter than expiration %s", role.name, role.lifetime.RotationPeriod, role.lifetime.Expiration)
+		}
+
+		if margin := 2 * rotationCheckPeriod; role.lifetime.Expiration.MinDuration()-role.lifetime.RotationPeriod.MaxDuration() < margin {
+			return fmt.Errorf("%s expiration %s must exceed rotation period %s by at least %s", role.name, role.lifetime.Expiration, role.lifetime.RotationPeriod, margin)
+		}
+	}
+
+	return nil
+}
+
+type TufRoleStatus struct {
+	Role     string
+	Version  int64
+	Expires  time.Time
+	RotateAt time.Time
+	Lifetime TufRoleLifetime
+}
+
+// IsNearExpiry reports that the role metadata has not been rotated in time and will expire soon.
+// Normally the role is re-signed every rotation period, so that at least (expiration - rotation period) of validity remains,
+// the role is near expiry when less than a half of that time remains.
+func (status *TufRoleStatus) IsNearExpiry(now time.Time) bool {
+	threshold := (status.Lifetime.Expiration.MinDuration() - status.Lifetime.RotationPeriod.MaxDuration()) / 2
+	return status.Expires.Sub(now) < threshold
+}
+
 type TufRepoRotator struct {
-	TufRepo TufRepoRotatorAccessor
+	TufRepo   TufRepoRotatorAccessor
+	Lifetimes TufRepoLifetimes
+	// SkipRoot disables root.json rotation when root.json cannot be signed by the online keys
+	SkipRoot bool
 }
 
 func NewTufRepoRotator(tufRepo TufRepoRotatorAccessor) *TufRepoRotator {
-	return &TufRepoRotator{TufRepo: tufRepo}
+	return NewTufRepoRotatorWithLifetimes(tufRepo, DefaultTufRepoLifetimes())
+}
+
+func NewTufRepoRotatorWithLifetimes(tufRepo TufRepoRotatorAccessor, lifetimes TufRepoLifetimes) *TufRepoRotator {
+	return &TufRepoRotator{TufRepo: tufRepo, Lifetimes: lifetimes}
 }
 
 func (rotator *TufRepoRotator) Rotate(logger hclog.Logger, now time.Time) error {
+	return rotator.rotate(logger, now, false)
+}
+
+// ForceRotate re-signs all roles regardless of rotation periods (e.g. after keys rotation)
+func (rotator *TufRepoRotator) ForceRotate(logger hclog.Logger, now time.Time) error {
+	return rotator.rotate(logger, now, true)
+}
+
+func (rotator *TufRepoRotator) rotate(logger hclog.Logger, now time.Time, force bool) error {
 	var changedRoot, changedTargets, changedSnapshot, changedTimestamp bool
 
 	logger.Debug("start rotating expiration timestamps and versions of TUF repository roles")
 
-	{
+	if !rotator.SkipRoot {
 		rotateAt, err := rotator.GetRootRotateAt()
 		if err != nil {
 			return fmt.Errorf("unable to get root.json rotation time: %w", err)
 		}
-		hitRotationPeriod := rotateAt.Sub(now) <= 0
+		hitRotationPeriod := force || rotateAt.Sub(now) <= 0
 
 		if hitRotationPeriod {
 			if err := rotator.RotateRoot(now); err != nil {
@@ -41,7 +151,7 @@ func (rotator *TufRepoRotator) Rotate(logger hclog.Logger, now time.Time) error
 		if err != nil {
 			return fmt.Errorf("unable to get targets.json rotation time: %w", err)
 		}
-		hitRotationPeriod := rotateAt.Sub(now) <= 0
+		hitRotationPeriod := force || rotateAt.Sub(now) <= 0
 
 		if hitRotationPeriod {
 			if err := rotator.RotateTargets(now); err != nil {
@@ -57,7 +167,7 @@ func (rotator *TufRepoRotator) Rotate(logger hclog.Logger, now time.Time) error
 		if err != nil {
 			return fmt.Errorf("unable to get snapshot.json rotation time: %w", err)
 		}
-		hitRotationPeriod := rotateAt.Sub(now) <= 0
+		hitRotationPeriod := force || rotateAt.Sub(now) <= 0
 
 		if changedRoot || changedTargets || hitRotationPeriod {
 			if err := rotator.RotateSnapshot(now); err != nil {
@@ -73,7 +183,7 @@ func (rotator *TufRepoRotator) Rotate(logger hclog.Logger, now time.Time) error
 		if err != nil {
 			return fmt.Errorf("unable to get timestamp.json rotation time: %w", err)
 		}
-		hitRotationPeriod := rotateAt.Sub(now) <= 0
+		hitRotationPeriod := force || rotateAt.Sub(now) <= 0
 
 		if changedSnapshot || hitRotationPeriod {
 			if err := rotator.RotateTimestamp(now); err != nil {
@@ -95,56 +205,52 @@ func (rotator *TufRepoRotator) Rotate(logger hclog.Logger, now time.Time) error
 	return nil
 }
 
-// Root expires every year, rotate every 3 month
 func (rotator *TufRepoRotator) GetRootRotateAt() (time.Time, error) {
 	expiresAt, err := rotator.TufRepo.RootExpires()
 	if err != nil {
 		return time.Time{}, fmt.Errorf("unable to get current expires: %w", err)
 	}
-	return expiresAt.AddDate(-1, 0, 0).AddDate(0, 3, 0), nil
+	return rotator.Lifetimes.Root.RotationPeriod.AddTo(rotator.Lifetimes.Root.Expiration.SubFrom(expiresAt)), nil
 }
 
 func (rotator *TufRepoRotator) RotateRoot(now time.Time) error {
-	return rotator.TufRepo.IncrementRootVersionWithExpires(now.AddDate(1, 0, 0))
+	return rotator.TufRepo.IncrementRootVersionWithExpires(rotator.Lifetimes.Root.Expiration.AddTo(now))
 }
 
-// Targets expires every 3 month, rotate every 3 weeks
 func (rotator *TufRepoRotator) GetTargetsRotateAt() (time.Time, error) {
 	expiresAt, err := rotator.TufRepo.TargetsExpires()
 	if err != nil {
 		return time.Time{}, fmt.Errorf("unable to get current expires: %w", err)
 	}
-	return expiresAt.AddDate(0, -3, 0).AddDate(0, 0, 21), nil
+	return rotator.Lifetimes.Targets.RotationPeriod.AddTo(rotator.Lifetimes.Targets.Expiration.SubFrom(expiresAt)), nil
 }
 
 func (rotator *TufRepoRotator) RotateTargets(now time.Time) error {
-	return rotator.TufRepo.IncrementTargetsVersionWithExpires(now.AddDate(0, 3, 0))
+	return rotator.TufRepo.IncrementTargetsVersionWithExpires(rotator.Lifetimes.Targets.Expiration.AddTo(now))
 }
 
-// Snapshot expires every 7 days, rotate every 2nd day
 func (rotator *TufRepoRotator) GetSnapshotRotateAt() (time.Time, error) {
 	expiresAt, err := rotator.TufRepo.SnapshotExpires()
 	if err != nil {
 		return time.Time{}, fmt.Errorf("unable to get current expires: %w", err)
 	}
-	return expiresAt.AddDate(0, 0, -7).AddDate(0, 0, 2), nil
+	return rotator.Lifetimes.Snapshot.RotationPeriod.AddTo(rotator.Lifetimes.Snapshot.Expiration.SubFrom(expiresAt)), nil
 }
 
 func (rotator *TufRepoRotator) RotateSnapshot(now time.Time) error {
-	return rotator.TufRepo.IncrementSnapshotVersionWithExpires(now.AddDate(0, 0, 7))
+	return rotator.TufRepo.IncrementSnapshotVersionWithExpires(rotator.Lifetimes.Snapshot.Expiration.AddTo(now))
 }
 
-// Timestamp expires every day, rotate every 4th hour
 func (rotator *TufRepoRotator) GetTimestampRotateAt() (time.Time, error) {
 	expiresAt, err := rotator.TufRepo.TimestampExpires()
 	if err != nil {
 		return time.Time{}, fmt.Errorf("unable to get current expires: %w", err)
 	}
-	return expiresAt.AddDate(0, 0, -1).Add(time.Hour * 4), nil
+	return rotator.Lifetimes.Timestamp.RotationPeriod.AddTo(rotator.Lifetimes.Timestamp.Expiration.SubFrom(expiresAt)), nil
 }
 
 func (rotator *TufRepoRotator) RotateTimestamp(now time.Time) error {
-	return rotator.TufRepo.IncrementTimestampVersionWithExpires(now.AddDate(0, 0, 1))
+	return rotator.TufRepo.IncrementTimestampVersionWithExpires(rotator.Lifetimes.Timestamp.Expiration.AddTo(now))
 }
 
 func (rotator *TufRepoRotator) Commit() error {
@@ -0,0 +1,154 @@
+package publisher
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+	uuid "github.com/satori/go.uuid"
+	"github.com/theupdateframework/go-tuf/data"
+)
+
+const defaultVaultTransitMountPath = "transit"
+
+type VaultTransitOptions struct {
+	// Address of the Vault server with the Transit secrets engine enabled.
+	// The plugin configuration requires the address: the plugin process environment (VAULT_ADDR) is controlled by Vault
+	Address string
+	// Token with the permissions to create keys, read keys and sign by the keys of the Transit secrets engine
+	Token string
+	// MountPath of the Transit secrets engine, "transit" is used by default
+	MountPath string
+}
+
+// VaultTransitSignerBackend keeps TUF keys in the Vault Transit secrets engine.
+// Keys are not deleted from the Transit secrets engine when revoked from the TUF repository.
+type VaultTransitSignerBackend struct {
+	client    *api.Client
+	mountPath string
+}
+
+func NewVaultTransitSignerBackend(options VaultTransitOptions) (*VaultTransitSignerBackend, error) {
+	config := api.DefaultConfig()
+	if options.Address != "" {
+		config.Address = options.Address
+	}
+
+	client, err := api.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create vault client: %w", err)
+	}
+
+	if options.Token != "" {
+		client.SetToken(options.Token)
+	}
+
+	mountPath := strings.Trim(options.MountPath, "/")
+	if mountPath == "" {
+		mountPath = defaultVaultTransitMountPath
+	}
+
+	return &VaultTransitSignerBackend{client: client, mountPath: mountPath}, nil
+}
+
+func (backend *VaultTransitSignerBackend) Name() string {
+	return SignerTypeVaultTransit
+}
+
+func (backend *VaultTransitSignerBackend) GenerateKey(_ context.Context, role string) (string, *data.PublicKey, error) {
+	keyName := fmt.Sprintf("trdl-tuf-%s-%s", role, uuid.NewV4().String())
+	keyPath := path.Join(backend.mountPath, "keys", keyName)
+
+	if _, err := backend.client.Logical().Write(keyPath, map[string]interface{}{"type": "ed25519"}); err != nil {
+		return "", nil, fmt.Errorf("unable to create key %q: %w", keyPath, err)
+	}
+
+	secret, err := backend.client.Logical().Read(keyPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to read key %q: %w", keyPath, err)
+	}
+	if secret == nil {
+		return "", nil, fmt.Errorf("key %q not found", keyPath)
+	}
+
+	publicKey, err := parseVaultTransitPublicKey(secret.Data)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to parse key %q: %w", keyPath, err)
+	}
+
+	return keyName, publicKey, nil
+}
+
+func (backend *VaultTransitSignerBackend) Sign(_ context.Context, keyName string, message []byte) ([]byte, error) {
+	signPath := path.Join(backend.mountPath, "sign", keyName)
+
+	secret, err := backend.client.Logical().Write(signPath, map[string]interface{}{
+		"input": base64.StdEncoding.EncodeToString(message),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign by %q: %w", signPath, err)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("empty response of %q", signPath)
+	}
+
+	signature, ok := secret.Data["signature"].(string)
+	if !ok {
+		return nil, fmt.Errorf("no signature in the response of %q", signPath)
+	}
+
+	// The signature is in the format vault:v<key version>:<base64 signature>
+	parts := strings.SplitN(signature, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, fmt.Errorf("unexpected signature format %q", signature)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode signature: %w", err)
+	}
+
+	return sig, nil
+}
+
+// parseVaultTransitPublicKey returns the public key of the latest key version.
+func parseVaultTransitPublicKey(keyData map[string]interface{}) (*data.PublicKey, error) {
+	if keyType, _ := keyData["type"].(string); keyType != "ed25519" {
+		return nil, fmt.Errorf("unsupported key type %q: expected ed25519", keyType)
+	}
+
+	latestVersion, ok := keyData["latest_version"].(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("no latest key version")
+	}
+
+	versions, _ := keyData["keys"].(map[string]interface{})
+	version, _ := versions[latestVersion.String()].(map[string]interface{})
+	encodedPublicKey, ok := version["public_key"].(string)
+	if !ok {
+		return nil, fmt.Errorf("no public key of the version %s", latestVersion)
+	}
+
+	publicKey, err := base64.StdEncoding.DecodeString(encodedPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode public key: %w", err)
+	}
+
+	value, err := json.Marshal(struct {
+		Public data.HexBytes `json:"public"`
+	}{Public: publicKey})
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.PublicKey{
+		Type:       data.KeyTypeEd25519,
+		Scheme:     data.KeySchemeEd25519,
+		Algorithms: data.HashAlgorithms,
+		Value:      value,
+	}, nil
+}
@@ -5,9 +5,10 @@ import (
 	"github.com/hashicorp/vault/sdk/logical"
 )
 
-func CheckRequiredFields(req *logical.Request, fields *framework.FieldData) *logical.Response {
+// CheckRequiredFields checks both the request data and the parameters captured from the request path.
+func CheckRequiredFields(fields *framework.FieldData) *logical.Response {
 	for fieldName, schema := range fields.Schema {
-		if schema.Required && req.Get(fieldName) == nil {
+		if schema.Required && fields.Raw[fieldName] == nil {
 			return logical.ErrorResponse("Required field %q must be set", fieldName)
 		}
 	}